@@ -12,7 +12,8 @@ type ApiKey struct {
 	Name        string      `json:"name" description:"API Key 名称"`
 	KeyId       string      `json:"keyId" description:"API Key ID (公开标识)"`
 	KeySecret   string      `json:"keySecret,omitempty" description:"API Key Secret (仅创建时返回)"`
-	Permissions []string    `json:"permissions" description:"权限列表"`
+	Permissions []string    `json:"permissions" description:"权限范围列表，为空表示拥有所属用户的全部权限"`
+	TemplateIds []int64     `json:"templateIds" description:"限定访问的模板ID列表，为空表示不限"`
 	LastUsedAt  *gtime.Time `json:"lastUsedAt" description:"最后使用时间"`
 	LastUsedIp  string      `json:"lastUsedIp" description:"最后使用IP"`
 	ExpiresAt   *gtime.Time `json:"expiresAt" description:"过期时间"`
@@ -60,7 +61,8 @@ type GetApiKeysRes struct {
 type CreateApiKeyReq struct {
 	g.Meta      `path:"/api-keys" method:"post" summary:"创建API Key" tags:"ApiKey"`
 	Name        string      `json:"name" v:"required|length:1,100" description:"API Key 名称"`
-	Permissions []string    `json:"permissions" description:"权限列表"`
+	Permissions []string    `json:"permissions" description:"权限范围列表，为空表示拥有所属用户的全部权限"`
+	TemplateIds []int64     `json:"templateIds" description:"限定访问的模板ID列表，为空表示不限"`
 	ExpiresAt   *gtime.Time `json:"expiresAt" description:"过期时间"`
 }
 
@@ -75,7 +77,8 @@ type UpdateApiKeyReq struct {
 	g.Meta      `path:"/api-keys/{id}" method:"put" summary:"更新API Key" tags:"ApiKey"`
 	Id          int64       `json:"id" in:"path" v:"required" description:"API Key ID"`
 	Name        string      `json:"name" v:"required|length:1,100" description:"API Key 名称"`
-	Permissions []string    `json:"permissions" description:"权限列表"`
+	Permissions []string    `json:"permissions" description:"权限范围列表，为空表示拥有所属用户的全部权限"`
+	TemplateIds []int64     `json:"templateIds" description:"限定访问的模板ID列表，为空表示不限"`
 	ExpiresAt   *gtime.Time `json:"expiresAt" description:"过期时间"`
 	Status      int         `json:"status" v:"in:0,1" description:"状态: 0-禁用, 1-启用"`
 }
@@ -105,6 +108,23 @@ type RegenerateApiKeyRes struct {
 	KeySecret string `json:"keySecret" description:"新的API Key Secret"`
 }
 
+// API Key 权限范围
+type ApiKeyScope struct {
+	Scope       string   `json:"scope" description:"权限范围"`
+	Label       string   `json:"label" description:"权限范围名称"`
+	Permissions []string `json:"permissions" description:"包含的系统权限编码"`
+}
+
+// GetApiKeyScopesReq 获取API Key可用的权限范围请求
+type GetApiKeyScopesReq struct {
+	g.Meta `path:"/api-keys/scopes" method:"get" summary:"获取API Key权限范围" tags:"ApiKey"`
+}
+
+type GetApiKeyScopesRes struct {
+	g.Meta `mime:"application/json"`
+	List   []ApiKeyScope `json:"list" description:"权限范围列表"`
+}
+
 // ============================================================================
 // API Key 使用日志接口
 // ============================================================================
@@ -152,7 +172,8 @@ type GetMyApiKeysRes struct {
 type CreateMyApiKeyReq struct {
 	g.Meta      `path:"/profile/api-keys" method:"post" summary:"创建我的API Key" tags:"Profile"`
 	Name        string      `json:"name" v:"required|length:1,100" description:"API Key 名称"`
-	Permissions []string    `json:"permissions" description:"权限列表"`
+	Permissions []string    `json:"permissions" description:"权限范围列表，为空表示拥有所属用户的全部权限"`
+	TemplateIds []int64     `json:"templateIds" description:"限定访问的模板ID列表，为空表示不限"`
 	ExpiresAt   *gtime.Time `json:"expiresAt" description:"过期时间"`
 }
 
@@ -167,7 +188,8 @@ type UpdateMyApiKeyReq struct {
 	g.Meta      `path:"/profile/api-keys/{id}" method:"put" summary:"更新我的API Key" tags:"Profile"`
 	Id          int64       `json:"id" in:"path" v:"required" description:"API Key ID"`
 	Name        string      `json:"name" v:"required|length:1,100" description:"API Key 名称"`
-	Permissions []string    `json:"permissions" description:"权限列表"`
+	Permissions []string    `json:"permissions" description:"权限范围列表，为空表示拥有所属用户的全部权限"`
+	TemplateIds []int64     `json:"templateIds" description:"限定访问的模板ID列表，为空表示不限"`
 	ExpiresAt   *gtime.Time `json:"expiresAt" description:"过期时间"`
 	Status      int         `json:"status" v:"in:0,1" description:"状态: 0-禁用, 1-启用"`
 }
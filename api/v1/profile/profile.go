@@ -133,4 +133,41 @@ type GetLoginHistoryRes struct {
 	Total int            `json:"total" dc:"总数"`
 	Page  int            `json:"page" dc:"当前页"`
 	Size  int            `json:"size" dc:"每页数量"`
-}
\ No newline at end of file
+}
+// ListSessionsReq 获取登录会话列表请求
+type ListSessionsReq struct {
+	g.Meta `path:"/profile/sessions" method:"get" summary:"获取登录会话列表" tags:"个人中心"`
+}
+
+// Session 登录会话
+type Session struct {
+	Id         string      `json:"id" dc:"会话ID"`
+	IpAddress  string      `json:"ipAddress" dc:"登录IP"`
+	UserAgent  string      `json:"userAgent" dc:"用户代理"`
+	Current    bool        `json:"current" dc:"是否为当前会话"`
+	LastSeenAt *gtime.Time `json:"lastSeenAt" dc:"最后活跃时间"`
+	ExpiresAt  *gtime.Time `json:"expiresAt" dc:"过期时间"`
+	CreatedAt  *gtime.Time `json:"createdAt" dc:"登录时间"`
+}
+
+type ListSessionsRes struct {
+	List []Session `json:"list" dc:"有效的会话列表"`
+}
+
+// RevokeSessionReq 撤销登录会话请求
+type RevokeSessionReq struct {
+	g.Meta `path:"/profile/sessions/{id}" method:"delete" summary:"撤销登录会话" tags:"个人中心"`
+	Id     string `json:"id" v:"required" dc:"会话ID"`
+}
+
+type RevokeSessionRes struct{}
+
+// RevokeAllSessionsReq 撤销全部登录会话请求
+type RevokeAllSessionsReq struct {
+	g.Meta      `path:"/profile/sessions" method:"delete" summary:"撤销全部登录会话" tags:"个人中心"`
+	KeepCurrent bool `json:"keepCurrent" dc:"是否保留当前会话"`
+}
+
+type RevokeAllSessionsRes struct {
+	Revoked int64 `json:"revoked" dc:"撤销的会话数"`
+}
@@ -0,0 +1,31 @@
+package template_bundle
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 模板包-导出
+type TemplateBundleExportReq struct {
+	g.Meta     `path:"/templates/{templateId}/export" method:"get" tags:"模板包" summary:"模板包-导出未渲染的模板"`
+	TemplateId int64 `json:"templateId" v:"required|min:1#模板ID不能为空"`
+}
+
+type TemplateBundleExportRes struct {
+	g.Meta `mime:"application/zip" example:"string"`
+}
+
+// 模板包-导入，模板包通过 multipart 表单的 bundle 字段上传
+type TemplateBundleImportReq struct {
+	g.Meta     `path:"/templates/import" method:"post" tags:"模板包" summary:"模板包-导入"`
+	OnConflict string `json:"onConflict" v:"in:error,rename,overwrite#名称冲突处理方式必须为error,rename,overwrite之一"` // 名称冲突处理方式，默认 error
+	Name       string `json:"name"`                                                                         // 可选：导入后的模板名称，默认使用包中的名称
+}
+
+type TemplateBundleImportRes struct {
+	g.Meta     `mime:"application/json" example:"string"`
+	TemplateId int64    `json:"templateId"` // 导入后的模板ID
+	Name       string   `json:"name"`       // 导入后的模板名称
+	Created    bool     `json:"created"`    // 是否新建模板，覆盖已有模板时为 false
+	FileCount  int      `json:"fileCount"`  // 导入的文件数，不含目录
+	Warnings   []string `json:"warnings"`   // 未能完整导入的关联信息，如本实例不存在的语言
+}
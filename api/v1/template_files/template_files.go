@@ -162,18 +162,25 @@ type TemplateFilesRenderReq struct {
 }
 
 type TemplateFilesRenderRes struct {
-	g.Meta      `mime:"application/json" example:"string"`
-	FileId      int64                  `json:"fileId"`
-	FileName    string                 `json:"fileName"`
-	FileContent string                 `json:"fileContent"` // 渲染后的内容
-	Variables   map[string]interface{} `json:"variables"`   // 使用的变量
-	Success     bool                   `json:"success"`     // 渲染是否成功
-	Error       *TemplateRenderError   `json:"error,omitempty"` // 渲染错误详情
+	g.Meta           `mime:"application/json" example:"string"`
+	FileId           int64                      `json:"fileId"`
+	FileName         string                     `json:"fileName"`
+	FileContent      string                     `json:"fileContent"`                // 渲染后的内容
+	Variables        map[string]interface{}     `json:"variables"`                  // 使用的变量
+	Success          bool                       `json:"success"`                    // 渲染是否成功
+	Error            *TemplateRenderError       `json:"error,omitempty"`            // 渲染错误详情
+	ValidationErrors []*VariableValidationError `json:"validationErrors,omitempty"` // 变量不符合模板变量定义时各字段的错误
+}
+
+// 变量校验错误
+type VariableValidationError struct {
+	Field   string `json:"field"`   // 变量路径，如 Port、Database.Host、Tables[0].Name
+	Message string `json:"message"` // 错误消息
 }
 
 // 模板渲染错误详情
 type TemplateRenderError struct {
-	Type        string `json:"type"`        // 错误类型: "parse_error", "execute_error", "variable_error"
+	Type        string `json:"type"`        // 错误类型: "parse_error", "execute_error", "variable_error", "limit_exceeded"
 	Message     string `json:"message"`     // 错误消息
 	Line        int    `json:"line"`        // 错误行号
 	Column      int    `json:"column"`      // 错误列号
@@ -186,15 +193,29 @@ type TemplateFilesRenderFileTreeReq struct {
 	g.Meta     `path:"/templateFiles/renderFileTree" method:"post" tags:"模板文件" summary:"模板文件-渲染文件树"`
 	TemplateId interface{}            `json:"templateId" v:"required#模板ID不能为空"`
 	Variables  map[string]interface{} `json:"variables"` // 变量值
+	Version    string                 `json:"version"`   // 渲染的发布版本号，默认为最新发布版本，draft 表示模板当前未发布的内容
 }
 
 type TemplateFilesRenderFileTreeRes struct {
-	g.Meta     `mime:"application/json" example:"string"`
-	TemplateId int64                  `json:"templateId"`
-	Tree       []*RenderFileInfo      `json:"tree"`       // 渲染后的文件树
-	Variables  map[string]interface{} `json:"variables"`  // 使用的变量
-	TotalFiles int                    `json:"totalFiles"` // 总文件数
-	TotalSize  int64                  `json:"totalSize"`  // 总文件大小
+	g.Meta           `mime:"application/json" example:"string"`
+	TemplateId       int64                      `json:"templateId"`
+	Version          string                     `json:"version"`                    // 实际渲染的版本号，draft 表示模板当前未发布的内容
+	Deprecated       bool                       `json:"deprecated"`                 // 渲染的版本是否已废弃
+	Tree             []*RenderFileInfo          `json:"tree"`                       // 渲染后的文件树
+	Variables        map[string]interface{}     `json:"variables"`                  // 使用的变量
+	TotalFiles       int                        `json:"totalFiles"`                 // 总文件数
+	TotalSize        int64                      `json:"totalSize"`                  // 总文件大小
+	Error            *TemplateRenderError       `json:"error,omitempty"`            // 变量校验失败或超出渲染限制时的错误详情
+	Diagnostics      []*RenderFileDiagnostic    `json:"diagnostics"`                // 各文件的渲染错误，出错部分保留原始内容
+	ValidationErrors []*VariableValidationError `json:"validationErrors,omitempty"` // 变量不符合模板变量定义时各字段的错误
+}
+
+// 单个文件的渲染诊断信息
+type RenderFileDiagnostic struct {
+	FileId   int64                `json:"fileId"`   // 文件ID
+	FilePath string               `json:"filePath"` // 模板中的原始文件路径
+	Part     string               `json:"part"`     // 出错部分: "fileName", "filePath", "fileContent"
+	Error    *TemplateRenderError `json:"error"`    // 错误详情
 }
 
 // 渲染后的文件信息
@@ -215,6 +236,8 @@ type TemplateFilesDownloadZipReq struct {
 	TemplateId interface{}            `json:"templateId" v:"required#模板ID不能为空"`
 	Variables  map[string]interface{} `json:"variables"` // 变量值
 	FileName   string                 `json:"fileName"`  // 可选的ZIP文件名，默认为模板名
+	Strict     bool                   `json:"strict"`    // 严格模式：任一文件渲染失败时不生成ZIP，返回诊断信息
+	Version    string                 `json:"version"`   // 渲染的发布版本号，默认为最新发布版本，draft 表示模板当前未发布的内容
 }
 
 type TemplateFilesDownloadZipRes struct {
@@ -237,10 +260,11 @@ type TemplateFilesMoveRes struct {
 type TemplateFilesSetConditionReq struct {
 	g.Meta        `path:"/templateFiles/setCondition" method:"put" tags:"模板文件" summary:"模板文件-设置生成条件"`
 	Id            interface{} `json:"id" v:"required#文件ID不能为空"`
-	Enabled       bool        `json:"enabled"`                                    // 是否启用条件
-	VariableName  string      `json:"variableName" v:"required-if:enabled,true"`  // 关联变量名
-	ExpectedValue bool        `json:"expectedValue"`                              // 期望值
-	Description   string      `json:"description"`                                // 条件描述
+	Enabled       bool        `json:"enabled"`       // 是否启用条件
+	Expression    string      `json:"expression"`    // 条件表达式，非空时优先于 variableName/expectedValue
+	VariableName  string      `json:"variableName"`  // 关联变量名
+	ExpectedValue bool        `json:"expectedValue"` // 期望值
+	Description   string      `json:"description"`   // 条件描述
 }
 
 type TemplateFilesSetConditionRes struct {
@@ -255,8 +279,117 @@ type TemplateFilesGetConditionReq struct {
 
 type TemplateFilesGetConditionRes struct {
 	g.Meta        `mime:"application/json" example:"string"`
-	Enabled       bool   `json:"enabled"`       // 是否启用条件
-	VariableName  string `json:"variableName"`  // 关联变量名  
-	ExpectedValue bool   `json:"expectedValue"` // 期望值
-	Description   string `json:"description"`   // 条件描述
+	Enabled       bool     `json:"enabled"`       // 是否启用条件
+	Expression    string   `json:"expression"`    // 条件表达式
+	Variables     []string `json:"variables"`     // 条件引用的变量
+	VariableName  string   `json:"variableName"`  // 关联变量名
+	ExpectedValue bool     `json:"expectedValue"` // 期望值
+	Description   string   `json:"description"`   // 条件描述
+}
+
+// 设置文件批量生成配置接口
+type TemplateFilesSetRepeatReq struct {
+	g.Meta     `path:"/templateFiles/setRepeat" method:"put" tags:"模板文件" summary:"模板文件-设置批量生成"`
+	Id         interface{} `json:"id" v:"required#文件ID不能为空"`
+	Enabled    bool        `json:"enabled"`    // 是否启用批量生成
+	Over       string      `json:"over"`       // 数组变量路径，如 tables
+	ItemAlias  string      `json:"itemAlias"`  // 当前元素变量名，默认 item
+	IndexAlias string      `json:"indexAlias"` // 当前下标变量名，默认 index
+}
+
+type TemplateFilesSetRepeatRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+}
+
+// 获取文件批量生成配置接口
+type TemplateFilesGetRepeatReq struct {
+	g.Meta `path:"/templateFiles/getRepeat" method:"get" tags:"模板文件" summary:"模板文件-获取批量生成配置"`
+	Id     interface{} `json:"id" v:"required#文件ID不能为空"`
+}
+
+type TemplateFilesGetRepeatRes struct {
+	g.Meta     `mime:"application/json" example:"string"`
+	Enabled    bool   `json:"enabled"`    // 是否启用批量生成
+	Over       string `json:"over"`       // 数组变量路径
+	ItemAlias  string `json:"itemAlias"`  // 当前元素变量名
+	IndexAlias string `json:"indexAlias"` // 当前下标变量名
+}
+
+// 文件修订历史接口
+type TemplateFilesRevisionsReq struct {
+	g.Meta `path:"/templateFiles/revisions" method:"get" tags:"模板文件" summary:"模板文件-修订历史"`
+	Id     interface{} `json:"id" v:"required#文件ID不能为空"` // 文件ID，已删除的文件也可查询
+}
+
+type TemplateFilesRevisionsRes struct {
+	g.Meta    `mime:"application/json" example:"string"`
+	Revisions []*model.TemplateFileRevisionInfo `json:"revisions"` // 按修订号从新到旧排列
+}
+
+// 获取单个修订内容接口
+type TemplateFilesRevisionDetailReq struct {
+	g.Meta   `path:"/templateFiles/revisions/detail" method:"get" tags:"模板文件" summary:"模板文件-修订详情"`
+	Id       interface{} `json:"id" v:"required#文件ID不能为空"`
+	Revision int         `json:"revision" v:"required|min:1#修订号不能为空|修订号无效"`
+}
+
+type TemplateFilesRevisionDetailRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	*model.TemplateFileRevisionInfo
+}
+
+// 两个修订之间的差异接口
+type TemplateFilesRevisionDiffReq struct {
+	g.Meta `path:"/templateFiles/revisions/diff" method:"get" tags:"模板文件" summary:"模板文件-修订差异"`
+	Id     interface{} `json:"id" v:"required#文件ID不能为空"`
+	From   int         `json:"from" v:"required|min:1#起始修订号不能为空|起始修订号无效"`
+	To     int         `json:"to"` // 目标修订号，不传时与最新修订比较
+}
+
+type TemplateFilesRevisionDiffRes struct {
+	g.Meta  `mime:"application/json" example:"string"`
+	From    *model.TemplateFileRevisionInfo `json:"from"`
+	To      *model.TemplateFileRevisionInfo `json:"to"`
+	Diff    string                          `json:"diff"`    // 文件内容的统一差异格式（unified diff）
+	Changes []string                        `json:"changes"` // 路径、生成条件等非内容变更的说明
+}
+
+// 恢复修订接口
+type TemplateFilesRevisionRestoreReq struct {
+	g.Meta   `path:"/templateFiles/revisions/restore" method:"put" tags:"模板文件" summary:"模板文件-恢复修订"`
+	Id       interface{} `json:"id" v:"required#文件ID不能为空"`
+	Revision int         `json:"revision" v:"required|min:1#修订号不能为空|修订号无效"`
+}
+
+type TemplateFilesRevisionRestoreRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	*model.TemplateFileRevisionInfo
+}
+
+// 已删除文件列表接口
+type TemplateFilesDeletedReq struct {
+	g.Meta     `path:"/templateFiles/deleted" method:"get" tags:"模板文件" summary:"模板文件-已删除文件"`
+	TemplateId interface{} `json:"templateId" v:"required#模板ID不能为空"`
+}
+
+type TemplateFilesDeletedRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	Files  []*model.TemplateFileRevisionInfo `json:"files"` // 每个已删除文件删除前的最后一个修订
+}
+
+// 模板检查，解析全部文件并检查函数、变量、生成条件和渲染后的路径
+type TemplateFilesLintReq struct {
+	g.Meta     `path:"/templates/{templateId}/lint" method:"get" tags:"模板文件" summary:"模板文件-检查模板"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Version    string `json:"version"` // 检查的版本，默认为 draft 即当前工作区
+}
+
+type TemplateFilesLintRes struct {
+	g.Meta       `mime:"application/json" example:"string"`
+	TemplateId   int64                      `json:"templateId"`
+	Version      string                     `json:"version"`      // 检查的版本，工作区为 draft
+	FileCount    int                        `json:"fileCount"`    // 检查的文件数，不含目录
+	ErrorCount   int                        `json:"errorCount"`   // error 级别的问题数
+	WarningCount int                        `json:"warningCount"` // warning 级别的问题数
+	Issues       []*model.TemplateLintIssue `json:"issues"`       // 按文件路径和行号排序
 }
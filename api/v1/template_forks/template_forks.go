@@ -0,0 +1,36 @@
+package template_forks
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 模板上游-来源
+type TemplateForksUpstreamReq struct {
+	g.Meta     `path:"/templates/{templateId}/upstream" method:"get" tags:"模板上游" summary:"模板上游-来源"`
+	TemplateId int64 `json:"templateId" v:"required|min:1#模板ID不能为空"`
+}
+
+type TemplateForksUpstreamRes struct {
+	g.Meta   `mime:"application/json" example:"string"`
+	Upstream *model.TemplateUpstreamInfo `json:"upstream"`
+}
+
+// 模板上游-拉取修改，将上游自上次同步以来的修改三方合并到当前模板
+type TemplateForksPullReq struct {
+	g.Meta     `path:"/templates/{templateId}/upstream/pull" method:"post" tags:"模板上游" summary:"模板上游-拉取修改"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Version    string `json:"version"` // 拉取的上游发布版本号，默认为 draft，即上游当前未发布的内容
+	DryRun     bool   `json:"dryRun"`  // 只返回合并结果，不修改当前模板
+}
+
+type TemplateForksPullRes struct {
+	g.Meta    `mime:"application/json" example:"string"`
+	Upstream  *model.TemplateUpstreamInfo `json:"upstream"`
+	Version   string                      `json:"version"`   // 本次拉取的上游版本
+	DryRun    bool                        `json:"dryRun"`    // 为 true 时当前模板和同步基准均未修改
+	Files     []*model.TemplateMergeFile  `json:"files"`     // 按路径排列的有变化的文件
+	Conflicts int                         `json:"conflicts"` // 存在冲突的文件数
+	Unchanged int                         `json:"unchanged"` // 上游未修改的文件数
+}
@@ -0,0 +1,39 @@
+package template_git
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 模板git来源-导入，从服务器本地的 git 仓库同步模板文件树
+type TemplateGitImportReq struct {
+	g.Meta     `path:"/templates/{templateId}/git/import" method:"post" tags:"模板git来源" summary:"模板git来源-从本地git仓库导入"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Repository string `json:"repository"` // 仓库路径，本地路径或 file:// 地址，为空时使用上次导入的仓库
+	Ref        string `json:"ref"`        // 分支、标签或提交，为空时使用上次导入的引用，首次导入默认 HEAD
+	Subdir     string `json:"subdir"`     // 仓库中作为模板根目录的子目录，为空时使用上次导入的子目录
+}
+
+type TemplateGitImportRes struct {
+	g.Meta         `mime:"application/json" example:"string"`
+	Source         *model.TemplateGitSourceInfo `json:"source"`         // 本次导入记录的来源
+	PreviousCommit string                       `json:"previousCommit"` // 上次导入的提交，首次导入为空
+	Commits        []*model.TemplateGitCommit   `json:"commits"`        // 上次导入以来的提交，上次的提交已不在仓库中时为空
+	Added          []string                     `json:"added"`          // 新增的文件
+	Modified       []string                     `json:"modified"`       // 内容变化的文件
+	Deleted        []string                     `json:"deleted"`        // 仓库中已不存在而删除的文件
+	Unchanged      int                          `json:"unchanged"`      // 内容未变化的文件数
+	Warnings       []string                     `json:"warnings"`       // 跳过的文件，如二进制文件、子模块和符号链接
+}
+
+// 模板git来源-详情
+type TemplateGitSourceReq struct {
+	g.Meta     `path:"/templates/{templateId}/git/source" method:"get" tags:"模板git来源" summary:"模板git来源-详情"`
+	TemplateId int64 `json:"templateId" v:"required|min:1#模板ID不能为空"`
+}
+
+type TemplateGitSourceRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	*model.TemplateGitSourceInfo
+}
@@ -0,0 +1,43 @@
+package template_releases
+
+import (
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 模板发布版本-列表
+type TemplateReleasesListReq struct {
+	g.Meta            `path:"/templates/{templateId}/releases" method:"get" tags:"模板发布版本" summary:"模板发布版本-列表"`
+	TemplateId        int64 `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	IncludeDeprecated bool  `json:"includeDeprecated"` // 是否包含已废弃的版本
+}
+
+type TemplateReleasesListRes struct {
+	g.Meta   `mime:"application/json" example:"string"`
+	Releases []*model.TemplateReleaseInfo `json:"releases"` // 按版本号从高到低排列
+}
+
+// 模板发布版本-发布
+type TemplateReleasesPublishReq struct {
+	g.Meta     `path:"/templates/{templateId}/releases" method:"post" tags:"模板发布版本" summary:"模板发布版本-发布"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Version    string `json:"version" v:"required|length:1,64#版本号不能为空|版本号长度为1-64个字符"` // 语义化版本号，如 1.2.0
+	Changelog  string `json:"changelog" v:"length:0,5000#版本说明不能超过5000个字符"`
+}
+
+type TemplateReleasesPublishRes struct {
+	g.Meta  `mime:"application/json" example:"string"`
+	Release *model.TemplateReleaseInfo `json:"release"`
+}
+
+// 模板发布版本-废弃
+type TemplateReleasesDeprecateReq struct {
+	g.Meta     `path:"/templates/{templateId}/releases/{version}/deprecate" method:"put" tags:"模板发布版本" summary:"模板发布版本-废弃"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Version    string `json:"version" v:"required#版本号不能为空"`
+	Reason     string `json:"reason" v:"length:0,500#废弃原因不能超过500个字符"`
+}
+
+type TemplateReleasesDeprecateRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+}
@@ -0,0 +1,45 @@
+package template_search
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// TemplateSearchQuery 搜索条件，搜索和替换共用
+type TemplateSearchQuery struct {
+	TemplateId    int64  `json:"templateId"`                                                  // 只搜索指定模板，为空时搜索全部可编辑的模板
+	Query         string `json:"query" v:"required|max-length:1000#搜索内容不能为空|搜索内容不能超过1000个字符"` // 搜索的文本或正则表达式
+	Regex         bool   `json:"regex"`                                                       // 是否按正则表达式搜索，替换文本中可使用 $1、${name} 引用分组
+	CaseSensitive bool   `json:"caseSensitive"`                                               // 是否区分大小写
+	WholeWord     bool   `json:"wholeWord"`                                                   // 是否只匹配完整的单词
+}
+
+// 模板文件搜索
+type TemplateSearchReq struct {
+	g.Meta `path:"/templates/search" method:"post" tags:"模板文件搜索" summary:"模板文件搜索-搜索"`
+	TemplateSearchQuery
+	ContextLines int `json:"contextLines" d:"2" v:"min:0|max:10#上下文行数不能小于0|上下文行数不能超过10"`     // 匹配前后的上下文行数
+	MaxResults   int `json:"maxResults" d:"500" v:"min:1|max:5000#最大匹配数不能小于1|最大匹配数不能超过5000"` // 最多返回的匹配数量
+}
+
+type TemplateSearchRes struct {
+	g.Meta    `mime:"application/json" example:"string"`
+	Total     int                         `json:"total"`     // 返回的匹配数量
+	Truncated bool                        `json:"truncated"` // 匹配数量超过 maxResults，结果不完整
+	Files     []*model.TemplateSearchFile `json:"files"`     // 按模板和路径排列的有匹配的文件
+}
+
+// 模板文件批量替换，在一个事务中替换选中的匹配并记录文件修订历史
+type TemplateReplaceReq struct {
+	g.Meta `path:"/templates/search/replace" method:"post" tags:"模板文件搜索" summary:"模板文件搜索-替换"`
+	TemplateSearchQuery
+	Replacement string   `json:"replacement"`                     // 替换成的文本
+	MatchIds    []string `json:"matchIds" v:"required#请选择要替换的匹配"` // 搜索结果中要替换的匹配ID
+}
+
+type TemplateReplaceRes struct {
+	g.Meta   `mime:"application/json" example:"string"`
+	Replaced int                          `json:"replaced"` // 替换的匹配总数
+	Files    []*model.TemplateReplaceFile `json:"files"`    // 有修改的文件
+}
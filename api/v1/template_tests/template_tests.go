@@ -0,0 +1,106 @@
+package template_tests
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 模板测试用例-列表
+type TemplateTestsListReq struct {
+	g.Meta     `path:"/templates/{templateId}/tests" method:"get" tags:"模板测试用例" summary:"模板测试用例-列表"`
+	TemplateId int64 `json:"templateId" v:"required|min:1#模板ID不能为空"`
+}
+
+type TemplateTestsListRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	Cases  []*model.TemplateTestCaseInfo `json:"cases"` // 按名称排列，不包含期望的渲染结果
+}
+
+// 模板测试用例-详情
+type TemplateTestsDetailReq struct {
+	g.Meta     `path:"/templates/{templateId}/tests/{name}" method:"get" tags:"模板测试用例" summary:"模板测试用例-详情"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Name       string `json:"name" v:"required#用例名称不能为空"`
+}
+
+type TemplateTestsDetailRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	Case   *model.TemplateTestCaseInfo `json:"case"`
+}
+
+// 模板测试用例-新增
+type TemplateTestsAddReq struct {
+	g.Meta      `path:"/templates/{templateId}/tests" method:"post" tags:"模板测试用例" summary:"模板测试用例-新增"`
+	TemplateId  int64                  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Name        string                 `json:"name" v:"required|length:1,64|regex:^[A-Za-z0-9][A-Za-z0-9_.-]*$#用例名称不能为空|用例名称长度为1-64个字符|用例名称只能包含字母、数字、下划线、点和短横线，且以字母或数字开头"`
+	Description string                 `json:"description" v:"length:0,500#用例说明不能超过500个字符"`
+	Variables   map[string]interface{} `json:"variables"` // 渲染使用的变量值
+	Expected    map[string]string      `json:"expected"`  // 期望的渲染结果，键为渲染后的文件路径，为空时按完整文件树比较，可通过运行用例的 accept 模式生成
+	FullTree    bool                   `json:"fullTree"`  // 是否校验完整文件树，为 true 时渲染出期望中没有的文件也视为失败
+}
+
+type TemplateTestsAddRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	Case   *model.TemplateTestCaseInfo `json:"case"`
+}
+
+// 模板测试用例-修改
+type TemplateTestsEditReq struct {
+	g.Meta      `path:"/templates/{templateId}/tests/{name}" method:"put" tags:"模板测试用例" summary:"模板测试用例-修改"`
+	TemplateId  int64                  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Name        string                 `json:"name" v:"required#用例名称不能为空"`
+	Description *string                `json:"description" v:"length:0,500#用例说明不能超过500个字符"` // 为空时保持不变
+	Variables   map[string]interface{} `json:"variables"`                                   // 为空时保持不变
+	Expected    map[string]string      `json:"expected"`                                    // 为空时保持不变
+	FullTree    *bool                  `json:"fullTree"`                                    // 为空时保持不变
+}
+
+type TemplateTestsEditRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	Case   *model.TemplateTestCaseInfo `json:"case"`
+}
+
+// 模板测试用例-删除
+type TemplateTestsDeleteReq struct {
+	g.Meta     `path:"/templates/{templateId}/tests/{name}" method:"delete" tags:"模板测试用例" summary:"模板测试用例-删除"`
+	TemplateId int64  `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Name       string `json:"name" v:"required#用例名称不能为空"`
+}
+
+type TemplateTestsDeleteRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+}
+
+// 模板测试用例-运行，渲染每个用例并与期望结果比较
+type TemplateTestsRunReq struct {
+	g.Meta     `path:"/templates/{templateId}/tests/run" method:"post" tags:"模板测试用例" summary:"模板测试用例-运行"`
+	TemplateId int64    `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Cases      []string `json:"cases"`   // 要运行的用例名称，为空时运行全部用例
+	Version    string   `json:"version"` // 渲染的发布版本号，默认为 draft，即模板当前未发布的内容
+	Accept     bool     `json:"accept"`  // 是否以本次渲染结果覆盖期望结果，渲染出错的用例不会被覆盖
+}
+
+type TemplateTestsRunRes struct {
+	g.Meta     `mime:"application/json" example:"string"`
+	TemplateId int64                  `json:"templateId"`
+	Version    string                 `json:"version"`  // 实际渲染的版本号
+	Accepted   bool                   `json:"accepted"` // 是否已覆盖期望结果
+	Total      int                    `json:"total"`
+	Passed     int                    `json:"passed"`
+	Failed     int                    `json:"failed"`
+	Errors     int                    `json:"errors"`
+	Cases      []*TemplateTestsResult `json:"cases"`
+}
+
+// 单个测试用例的运行结果
+type TemplateTestsResult struct {
+	Name             string                              `json:"name"`
+	Status           string                              `json:"status"`                     // passed、failed 或 error
+	Accepted         bool                                `json:"accepted"`                   // 是否已用本次渲染结果覆盖期望结果
+	Error            *filesApi.TemplateRenderError       `json:"error,omitempty"`            // 变量校验失败或超出渲染限制时的错误详情
+	ValidationErrors []*filesApi.VariableValidationError `json:"validationErrors,omitempty"` // 变量不符合模板变量定义时各字段的错误
+	Diagnostics      []*filesApi.RenderFileDiagnostic    `json:"diagnostics,omitempty"`      // 各文件的渲染错误
+	Files            []*model.TemplateTestFileResult     `json:"files"`                      // 按路径排列的各文件比较结果
+}
@@ -19,7 +19,7 @@ type TemplatesAddReq struct {
 	CategoryId   int                   `json:"categoryId" v:"required#所属分类ID不能为空"`
 	IsFeatured   int                   `json:"isFeatured" v:"required#是否推荐模板不能为空"`
 	TemplateType string                `json:"templateType" v:"required|in:basic,scaffold,data_driven#模板类型不能为空且必须为basic,scaffold,data_driven之一"`
-	TypeConfig   string                `json:"typeConfig"` // 类型配置，JSON格式
+	TypeConfig   string                `json:"typeConfig"` // 类型配置，JSON格式，可通过 {"delimiters":{"left":"[[","right":"]]"}} 自定义定界符
 	Logo         string                `json:"logo"`
 	Icon         string                `json:"icon"`
 	Languages    []TemplateLanguageReq `json:"languages"`
@@ -47,6 +47,40 @@ type TemplatesBatchDelRes struct {
 	g.Meta `mime:"application/json" example:"string"`
 }
 
+// 模板-回收站列表
+type TemplatesTrashReq struct {
+	g.Meta `path:"/templates/trash" method:"get" tags:"模板" summary:"模板-回收站列表"`
+	commonApi.PageReq
+	Name string `json:"name"`
+}
+
+type TemplatesTrashRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	commonApi.ListRes
+	TrashList     []*model.TemplateTrashInfo `json:"trashList"`     // 按删除时间从近到远排列
+	RetentionDays int                        `json:"retentionDays"` // 回收站保留天数，0 表示不自动清理
+}
+
+// 模板-从回收站恢复，连同文件、标签、语言等关联数据一起恢复
+type TemplatesRestoreReq struct {
+	g.Meta `path:"/templates/restore" method:"put" tags:"模板" summary:"模板-从回收站恢复"`
+	Ids    []int64 `json:"ids" v:"required#id不能为空"`
+}
+
+type TemplatesRestoreRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+}
+
+// 模板-彻底删除，删除回收站中的模板及其全部关联数据，不可恢复
+type TemplatesPurgeReq struct {
+	g.Meta `path:"/templates/purge" method:"delete" tags:"模板" summary:"模板-彻底删除"`
+	Ids    []int64 `json:"ids" v:"required#id不能为空"`
+}
+
+type TemplatesPurgeRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+}
+
 type TemplatesEditReq struct {
 	g.Meta       `path:"/templates/edit" method:"put" tags:"模板" summary:"模板-修改"`
 	Id           interface{}           `json:"id" v:"required#模板ID，自增主键不能为空"`
@@ -56,7 +90,7 @@ type TemplatesEditReq struct {
 	CategoryId   int                   `json:"categoryId" v:"required#所属分类ID不能为空"`
 	IsFeatured   int                   `json:"isFeatured" v:"required#是否推荐模板不能为空"`
 	TemplateType string                `json:"templateType" v:"required|in:basic,scaffold,data_driven#模板类型不能为空且必须为basic,scaffold,data_driven之一"`
-	TypeConfig   string                `json:"typeConfig"` // 类型配置，JSON格式
+	TypeConfig   string                `json:"typeConfig"` // 类型配置，JSON格式，可通过 {"delimiters":{"left":"[[","right":"]]"}} 自定义定界符
 	Logo         string                `json:"logo"`
 	Icon         string                `json:"icon"`
 	Languages    []TemplateLanguageReq `json:"languages"`
@@ -123,15 +157,16 @@ type TemplateFunctionInfo struct {
 type TemplatesVariablesReq struct {
 	g.Meta     `path:"/templates/{templateId}/variables" method:"get" tags:"模板" summary:"模板-变量列表"`
 	TemplateId interface{} `json:"templateId" v:"required#模板ID不能为空"`
+	Version    string      `json:"version"` // 变量定义所属的发布版本号，默认为最新发布版本，draft 表示模板当前未发布的内容
 }
 
-// 模板变量列表响应 - 已废弃，保留空结构体以兼容
+// 模板变量列表响应
 type TemplatesVariablesRes struct {
 	g.Meta            `mime:"application/json" example:"string"`
-	CustomVariables   []interface{}           `json:"customVariables"`
-	BuiltinVariables  []*BuiltinVariableInfo  `json:"builtinVariables"`
-	TemplateFunctions []*TemplateFunctionInfo `json:"templateFunctions"`
-	Statistics        *VariableStatistics     `json:"statistics"`
+	CustomVariables   []*model.TemplateVariableDef `json:"customVariables"` // 指定版本中定义的变量，按定义顺序排列
+	BuiltinVariables  []*BuiltinVariableInfo       `json:"builtinVariables"`
+	TemplateFunctions []*TemplateFunctionInfo      `json:"templateFunctions"`
+	Statistics        *VariableStatistics          `json:"statistics"`
 }
 
 // 变量分析请求
@@ -142,11 +177,25 @@ type TemplatesAnalyzeVariablesReq struct {
 
 // 检测到的变量信息
 type DetectedVariable struct {
-	Name        string   `json:"name"`        // 变量名
-	Type        string   `json:"type"`        // 推测的类型
-	Files       []string `json:"files"`       // 出现的文件
-	Contexts    []string `json:"contexts"`    // 使用上下文
-	Suggestions string   `json:"suggestions"` // 类型建议说明
+	Name          string           `json:"name"`          // 变量名
+	Type          string           `json:"type"`          // 推测的类型
+	Files         []string         `json:"files"`         // 出现的文件
+	Contexts      []string         `json:"contexts"`      // 使用上下文
+	Suggestions   string           `json:"suggestions"`   // 类型建议说明
+	Paths         []string         `json:"paths"`         // 引用的完整字段路径，如 Project.Name、Items[].Title
+	Usages        []*VariableUsage `json:"usages"`        // 每一处引用
+	InConditional bool             `json:"inConditional"` // 是否有引用位于 if/with 块中
+	InLoop        bool             `json:"inLoop"`        // 是否有引用位于 range 块中
+}
+
+// 变量的一处引用
+type VariableUsage struct {
+	File          string `json:"file"`          // 文件路径
+	Line          int    `json:"line"`          // 行号
+	Path          string `json:"path"`          // 完整字段路径
+	Context       string `json:"context"`       // 所在的模板动作
+	InConditional bool   `json:"inConditional"` // 是否位于 if/with 块中
+	InLoop        bool   `json:"inLoop"`        // 是否位于 range 块中
 }
 
 // 变量分析响应
@@ -158,6 +207,7 @@ type TemplatesAnalyzeVariablesRes struct {
 	ConflictVariables   []*DetectedVariable `json:"conflictVariables"`   // 冲突的变量（类型不匹配）
 	TotalVariableCount  int                 `json:"totalVariableCount"`  // 总变量数
 	AnalyzedFileCount   int                 `json:"analyzedFileCount"`   // 分析的文件数
+	FailedFiles         []string            `json:"failedFiles"`         // 模板语法错误、无法分析的文件
 }
 
 // 模板类型信息
@@ -0,0 +1,44 @@
+package templatize
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 模板化-预览，查找字面量的各种大小写写法在文件名、路径和内容中的出现位置
+type TemplatizePreviewReq struct {
+	g.Meta     `path:"/templates/{templateId}/templatize/preview" method:"post" tags:"模板化" summary:"模板化-预览"`
+	TemplateId int64    `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Literal    string   `json:"literal" v:"required|length:2,200#字面量不能为空|字面量长度为2-200个字符"`                                                           // 要替换的字面量，如 my-service
+	Variable   string   `json:"variable" v:"required|regex:^[A-Za-z_][A-Za-z0-9_]*(\\.[A-Za-z_][A-Za-z0-9_]*)*$#变量名不能为空|变量名只能包含字母、数字和下划线，嵌套变量以点分隔"` // 目标变量，如 ServiceName
+	Variants   []string `json:"variants"`                                                                                                           // 只替换指定的写法，为空时替换全部写法
+}
+
+type TemplatizePreviewRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	*TemplatizeResult
+}
+
+// 模板化-应用，在一个事务中将字面量的各种写法替换为对应的模板表达式
+type TemplatizeApplyReq struct {
+	g.Meta     `path:"/templates/{templateId}/templatize" method:"post" tags:"模板化" summary:"模板化-应用"`
+	TemplateId int64    `json:"templateId" v:"required|min:1#模板ID不能为空"`
+	Literal    string   `json:"literal" v:"required|length:2,200#字面量不能为空|字面量长度为2-200个字符"`
+	Variable   string   `json:"variable" v:"required|regex:^[A-Za-z_][A-Za-z0-9_]*(\\.[A-Za-z_][A-Za-z0-9_]*)*$#变量名不能为空|变量名只能包含字母、数字和下划线，嵌套变量以点分隔"`
+	Variants   []string `json:"variants"` // 只替换指定的写法，为空时替换全部写法
+}
+
+type TemplatizeApplyRes struct {
+	g.Meta `mime:"application/json" example:"string"`
+	*TemplatizeResult
+}
+
+// 模板化结果，预览和应用返回相同的内容
+type TemplatizeResult struct {
+	TemplateId int64                         `json:"templateId"`
+	Variants   []*model.TemplatizeVariant    `json:"variants"` // 字面量的各种写法，按出现次数从多到少排列
+	Files      []*model.TemplatizeFileChange `json:"files"`    // 按路径排列的有修改的文件
+	Warnings   []string                      `json:"warnings"`
+	Applied    bool                          `json:"applied"` // 是否已修改模板文件
+}
@@ -110,11 +110,21 @@ type ResetPasswordRes struct{}
 type UpdateUserStatusReq struct {
 	g.Meta `path:"/users/{id}/status" method:"put" summary:"更新用户状态" tags:"用户管理"`
 	Id     int64 `json:"id" v:"required" dc:"用户ID"`
-	Status int   `json:"status" v:"in:0,1" dc:"状态：0=禁用，1=正常"`
+	Status int   `json:"status" v:"in:0,1" dc:"状态：0=禁用，1=正常，禁用时同时撤销用户的全部登录会话"`
 }
 
 type UpdateUserStatusRes struct{}
 
+// RevokeUserSessionsReq 撤销用户登录会话请求
+type RevokeUserSessionsReq struct {
+	g.Meta `path:"/users/{id}/sessions" method:"delete" summary:"撤销用户的全部登录会话" tags:"用户管理"`
+	Id     int64 `json:"id" v:"required" dc:"用户ID"`
+}
+
+type RevokeUserSessionsRes struct {
+	Revoked int64 `json:"revoked" dc:"撤销的会话数"`
+}
+
 // AssignUserRolesReq 分配用户角色请求
 type AssignUserRolesReq struct {
 	g.Meta    `path:"/users/{id}/roles" method:"put" summary:"分配用户角色" tags:"用户管理"`
@@ -19,6 +19,7 @@ var createCmd = &cobra.Command{
 
 示例:
   template-cli create my-app --template go-web
+  template-cli create my-app --template go-web@1.2.0
   template-cli create my-service --template microservice --output ./projects
   template-cli create frontend --template vue3-admin --interactive
   template-cli create  # 进入完全交互式模式`,
@@ -36,6 +37,7 @@ func runCreateCommand(cmd *cobra.Command, args []string) error {
 	}
 	
 	templateName, _ := cmd.Flags().GetString("template")
+	templateName, templateVersion := client.ParseTemplateRef(templateName)
 	output, _ := cmd.Flags().GetString("output")
 	interactiveMode, _ := cmd.Flags().GetBool("interactive")
 	configFile, _ := cmd.Flags().GetString("config")
@@ -112,11 +114,15 @@ func runCreateCommand(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("\n使用模板: %s\n", selectedTemplate.Name)
+	if templateVersion != "" {
+		fmt.Printf("\n使用模板: %s@%s\n", selectedTemplate.Name, templateVersion)
+	} else {
+		fmt.Printf("\n使用模板: %s\n", selectedTemplate.Name)
+	}
 	fmt.Printf("模板描述: %s\n", selectedTemplate.Description)
 
 	// 获取模板变量
-	templateVariables, err := apiClient.GetTemplateVariables(fmt.Sprintf("%d", selectedTemplate.ID))
+	templateVariables, err := apiClient.GetTemplateVariables(fmt.Sprintf("%d", selectedTemplate.ID), templateVersion)
 	if err != nil {
 		return fmt.Errorf("获取模板变量失败: %w", err)
 	}
@@ -157,7 +163,7 @@ func runCreateCommand(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n开始创建项目...\n")
 
 	// 调用API渲染模板
-	renderedFiles, err := apiClient.RenderTemplate(fmt.Sprintf("%d", selectedTemplate.ID), variables)
+	renderedFiles, err := apiClient.RenderTemplate(fmt.Sprintf("%d", selectedTemplate.ID), templateVersion, variables)
 	if err != nil {
 		return fmt.Errorf("渲染模板失败: %w", err)
 	}
@@ -200,7 +206,7 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 
 	// 模板标识(可选，不指定时进入交互选择)
-	createCmd.Flags().StringP("template", "t", "", "模板名称或ID (可选，不指定时进入交互式选择)")
+	createCmd.Flags().StringP("template", "t", "", "模板名称或ID，可用 name@1.2.0 指定发布版本，默认为最新发布版本 (可选，不指定时进入交互式选择)")
 
 	// 可选参数
 	createCmd.Flags().StringP("output", "o", ".", "输出目录")
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/ciclebyte/template_starter/cli/internal/client"
@@ -17,7 +18,11 @@ var templateCmd = &cobra.Command{
 子命令:
   list    - 列出可用的模板
   info    - 显示模板详细信息
-  search  - 搜索模板`,
+  search  - 搜索模板
+  export  - 导出模板包
+  import  - 导入模板包
+  lint    - 检查模板
+  test    - 运行模板测试用例`,
 }
 
 // templateListCmd lists available templates
@@ -108,19 +113,31 @@ var templateInfoCmd = &cobra.Command{
 		fmt.Printf("描述: %s\n", template.Description)
 		
 		// 显示变量信息
+		if showVariables {
+			template.Variables, err = apiClient.GetTemplateVariables(fmt.Sprintf("%d", template.ID), "")
+			if err != nil {
+				return fmt.Errorf("获取模板变量失败: %w", err)
+			}
+		}
 		if showVariables && len(template.Variables) > 0 {
 			fmt.Printf("\n变量列表:\n")
 			for _, variable := range template.Variables {
-				fmt.Printf("• %s (%s)", variable.Name, variable.VariableType)
-				if variable.IsRequired == 1 {
+				fmt.Printf("• %s (%s)", variable.Name, variable.Type)
+				if variable.Required {
 					fmt.Printf(" *必需*")
 				}
 				fmt.Println()
 				if variable.Description != "" {
 					fmt.Printf("  %s\n", variable.Description)
 				}
-				if variable.DefaultValue != "" {
-					fmt.Printf("  默认值: %s\n", variable.DefaultValue)
+				if variable.DefaultString() != "" {
+					fmt.Printf("  默认值: %s\n", variable.DefaultString())
+				}
+				if len(variable.Enum) > 0 {
+					fmt.Printf("  可选值: %s\n", strings.Join(variable.Options(), ", "))
+				}
+				if variable.Computed != "" {
+					fmt.Printf("  计算表达式: %s\n", variable.Computed)
 				}
 				fmt.Println()
 			}
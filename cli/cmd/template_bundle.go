@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ciclebyte/template_starter/cli/internal/client"
+	"github.com/ciclebyte/template_starter/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// templateExportCmd exports a template bundle
+var templateExportCmd = &cobra.Command{
+	Use:   "export [template-name]",
+	Short: "导出模板包",
+	Long: `导出未渲染的模板包，包含模板元数据、分类、标签、语言、生成条件、
+暴露字段、订阅的预设以及原始模板文件，可导入到其他实例。
+
+示例:
+  template-cli template export go-web
+  template-cli template export 12 -o go-web.tsbundle.zip`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		apiClient := client.NewClient(cfg.Server.URL, cfg.Server.APIKey)
+
+		templateID, err := apiClient.ResolveTemplateID(args[0])
+		if err != nil {
+			return err
+		}
+		data, fileName, err := apiClient.ExportTemplate(templateID)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			output = fileName
+		}
+		if output == "" {
+			output = args[0] + ".tsbundle.zip"
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return fmt.Errorf("写入模板包失败: %w", err)
+		}
+		fmt.Printf("✅ 模板包已导出: %s (%d 字节)\n", output, len(data))
+		return nil
+	},
+}
+
+// templateImportCmd imports a template bundle
+var templateImportCmd = &cobra.Command{
+	Use:   "import [bundle-file]",
+	Short: "导入模板包",
+	Long: `导入由 template export 导出的模板包。
+
+模板名称已存在时的处理方式 (--on-conflict):
+  error     - 报错，不导入 (默认)
+  rename    - 自动重命名为 name-2、name-3 ...
+  overwrite - 覆盖同名模板的内容
+
+示例:
+  template-cli template import go-web.tsbundle.zip
+  template-cli template import go-web.tsbundle.zip --on-conflict rename
+  template-cli template import go-web.tsbundle.zip --name go-web-staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		name, _ := cmd.Flags().GetString("name")
+		switch onConflict {
+		case "error", "rename", "overwrite":
+		default:
+			return fmt.Errorf("--on-conflict 必须为 error、rename 或 overwrite")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		apiClient := client.NewClient(cfg.Server.URL, cfg.Server.APIKey)
+
+		result, err := apiClient.ImportTemplate(args[0], onConflict, name)
+		if err != nil {
+			return err
+		}
+
+		action := "已创建"
+		if !result.Created {
+			action = "已覆盖"
+		}
+		fmt.Printf("✅ 模板%s: %s (ID: %d)，共导入 %d 个文件\n", action, result.Name, result.TemplateID, result.FileCount)
+		for _, warning := range result.Warnings {
+			fmt.Printf("⚠️  %s\n", warning)
+		}
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateExportCmd)
+	templateCmd.AddCommand(templateImportCmd)
+
+	templateExportCmd.Flags().StringP("output", "o", "", "输出文件路径 (默认为 <模板名称>.tsbundle.zip)")
+
+	templateImportCmd.Flags().String("on-conflict", "error", "模板名称冲突时的处理方式: error、rename、overwrite")
+	templateImportCmd.Flags().String("name", "", "导入后的模板名称 (默认使用模板包中的名称)")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ciclebyte/template_starter/cli/internal/client"
+	"github.com/ciclebyte/template_starter/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// templateTestCmd runs a template's golden test cases
+var templateTestCmd = &cobra.Command{
+	Use:   "test [template-name]",
+	Short: "运行模板测试用例",
+	Long: `按模板中保存的测试用例渲染模板，并将渲染结果与用例的期望结果逐个文件比较，
+不一致的文件输出 unified diff。
+
+默认测试模板当前未发布的内容，可通过 名称@版本 测试指定的发布版本。
+存在失败或出错的用例时命令以非零状态退出，可在评审模板修改时运行。
+确认渲染结果的变化符合预期后，使用 --accept 以本次渲染结果覆盖期望结果。
+
+示例:
+  template-cli template test go-web
+  template-cli template test go-web --case minimal --case with-db
+  template-cli template test go-web --accept
+  template-cli template test go-web@1.2.0 --format json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		cases, _ := cmd.Flags().GetStringSlice("case")
+		accept, _ := cmd.Flags().GetBool("accept")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("--format 必须为 text 或 json")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		apiClient := client.NewClient(cfg.Server.URL, cfg.Server.APIKey)
+
+		templateName, version := client.ParseTemplateRef(args[0])
+		templateID, err := apiClient.ResolveTemplateID(templateName)
+		if err != nil {
+			return err
+		}
+		result, raw, err := apiClient.RunTemplateTests(templateID, version, cases, accept)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			var indented interface{}
+			if err := json.Unmarshal(raw, &indented); err != nil {
+				return fmt.Errorf("解析测试结果失败: %w", err)
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(indented); err != nil {
+				return err
+			}
+		} else {
+			printTestResult(templateName, result)
+		}
+
+		if result.Errors > 0 || (result.Failed > 0 && !result.Accepted) {
+			return fmt.Errorf("模板测试未通过: %d 个失败, %d 个出错", result.Failed, result.Errors)
+		}
+		return nil
+	},
+}
+
+func printTestResult(templateName string, result *client.TestRunResult) {
+	for _, testCase := range result.Cases {
+		switch testCase.Status {
+		case "passed":
+			fmt.Printf("✅ %s\n", testCase.Name)
+			continue
+		case "failed":
+			if testCase.Accepted {
+				fmt.Printf("📝 %s: 已更新期望结果\n", testCase.Name)
+			} else {
+				fmt.Printf("❌ %s\n", testCase.Name)
+			}
+		default:
+			fmt.Printf("💥 %s: 渲染出错\n", testCase.Name)
+		}
+
+		if testCase.Error != nil {
+			fmt.Printf("   %s\n", testCase.Error.Message)
+		}
+		for _, validation := range testCase.ValidationErrors {
+			fmt.Printf("   %s: %s\n", validation.Field, validation.Message)
+		}
+		for _, diagnostic := range testCase.Diagnostics {
+			if diagnostic.Error == nil {
+				continue
+			}
+			location := diagnostic.FilePath
+			if diagnostic.Error.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, diagnostic.Error.Line)
+			}
+			fmt.Printf("   %s (%s): %s\n", location, diagnostic.Part, diagnostic.Error.Message)
+		}
+		for _, file := range testCase.Files {
+			if file.Status == "passed" {
+				continue
+			}
+			fmt.Printf("   %s %s\n", file.Status, file.Path)
+			if file.Diff != "" && !testCase.Accepted {
+				for _, line := range strings.Split(strings.TrimRight(file.Diff, "\n"), "\n") {
+					fmt.Printf("     %s\n", line)
+				}
+			}
+		}
+	}
+	if len(result.Cases) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%s@%s: %d 个用例，%d 个通过，%d 个失败，%d 个出错\n",
+		templateName, result.Version, result.Total, result.Passed, result.Failed, result.Errors)
+}
+
+func init() {
+	templateCmd.AddCommand(templateTestCmd)
+
+	templateTestCmd.Flags().StringSlice("case", nil, "只运行指定的用例，可重复指定")
+	templateTestCmd.Flags().Bool("accept", false, "以本次渲染结果覆盖失败用例的期望结果")
+	templateTestCmd.Flags().String("format", "text", "输出格式: text 或 json")
+}
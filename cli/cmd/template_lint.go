@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ciclebyte/template_starter/cli/internal/client"
+	"github.com/ciclebyte/template_starter/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// templateLintCmd lints a template
+var templateLintCmd = &cobra.Command{
+	Use:   "lint [template-name]",
+	Short: "检查模板",
+	Long: `检查模板的全部文件，报告语法错误、未定义的函数和模板、未声明的变量、
+无效的生成条件以及渲染后为空或相互冲突的路径。
+
+存在 error 级别的问题时命令以非零状态退出，使用 --strict 时 warning 也视为失败，
+可在 CI 中发布模板前运行。--format json 输出服务端返回的原始检查结果。
+
+示例:
+  template-cli template lint go-web
+  template-cli template lint go-web@1.2.0
+  template-cli template lint 12 --format json --strict`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		strict, _ := cmd.Flags().GetBool("strict")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("--format 必须为 text 或 json")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %w", err)
+		}
+		apiClient := client.NewClient(cfg.Server.URL, cfg.Server.APIKey)
+
+		templateName, version := client.ParseTemplateRef(args[0])
+		templateID, err := apiClient.ResolveTemplateID(templateName)
+		if err != nil {
+			return err
+		}
+		result, raw, err := apiClient.LintTemplate(templateID, version)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			var indented interface{}
+			if err := json.Unmarshal(raw, &indented); err != nil {
+				return fmt.Errorf("解析检查结果失败: %w", err)
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(indented); err != nil {
+				return err
+			}
+		} else {
+			printLintResult(templateName, result)
+		}
+
+		if result.ErrorCount > 0 || (strict && result.WarningCount > 0) {
+			return fmt.Errorf("模板检查未通过: %d 个错误, %d 个警告", result.ErrorCount, result.WarningCount)
+		}
+		return nil
+	},
+}
+
+func printLintResult(templateName string, result *client.LintResult) {
+	for _, issue := range result.Issues {
+		location := issue.File
+		if location == "" {
+			location = "<变量定义>"
+		}
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, issue.Line)
+		}
+		if issue.Part != "" && issue.Part != "fileContent" {
+			location = fmt.Sprintf("%s (%s)", location, issue.Part)
+		}
+		icon := "⚠️ "
+		if issue.Severity == "error" {
+			icon = "❌"
+		}
+		fmt.Printf("%s %s: %s [%s]\n", icon, location, issue.Message, issue.Rule)
+	}
+	if len(result.Issues) > 0 {
+		fmt.Println()
+	}
+	if result.ErrorCount == 0 && result.WarningCount == 0 {
+		fmt.Printf("✅ %s@%s 检查通过，共 %d 个文件\n", templateName, result.Version, result.FileCount)
+		return
+	}
+	fmt.Printf("%s@%s: %d 个文件，%d 个错误，%d 个警告\n", templateName, result.Version, result.FileCount, result.ErrorCount, result.WarningCount)
+}
+
+func init() {
+	templateCmd.AddCommand(templateLintCmd)
+
+	templateLintCmd.Flags().String("format", "text", "输出格式: text 或 json")
+	templateLintCmd.Flags().Bool("strict", false, "存在警告时也以非零状态退出")
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ImportResult 模板包导入结果
+type ImportResult struct {
+	TemplateID int64    `json:"templateId"`
+	Name       string   `json:"name"`
+	Created    bool     `json:"created"`
+	FileCount  int      `json:"fileCount"`
+	Warnings   []string `json:"warnings"`
+}
+
+// ResolveTemplateID 将模板名称或ID解析为模板ID
+func (c *Client) ResolveTemplateID(ref string) (string, error) {
+	if _, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return ref, nil
+	}
+
+	params := url.Values{}
+	params.Add("name", ref)
+	params.Add("pageSize", "100")
+	resp, err := c.makeRequest("GET", "/api/v1/templates/list?"+params.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("请求模板列表失败: %w", err)
+	}
+	if resp.Code != 0 {
+		return "", fmt.Errorf("获取模板列表失败: %s", resp.Message)
+	}
+	var listResponse struct {
+		TemplatesList []Template `json:"templatesList"`
+	}
+	if err := json.Unmarshal(resp.Data, &listResponse); err != nil {
+		return "", fmt.Errorf("解析模板列表失败: %w", err)
+	}
+	for _, tmpl := range listResponse.TemplatesList {
+		if tmpl.Name == ref {
+			return strconv.FormatInt(tmpl.ID, 10), nil
+		}
+	}
+	return "", fmt.Errorf("模板 %s 不存在", ref)
+}
+
+// ExportTemplate 导出模板包，返回模板包内容和服务端建议的文件名
+func (c *Client) ExportTemplate(templateID string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("/api/v1/templates/%s/export", url.PathEscape(templateID))
+	req, err := http.NewRequest("GET", c.BaseURL+endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("创建HTTP请求失败: %w", err)
+	}
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("无法连接到服务器 %s: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("HTTP请求失败: %d %s", resp.StatusCode, string(data))
+	}
+	// 导出失败时服务端返回JSON错误
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/zip" {
+		var response Response
+		if err := json.Unmarshal(data, &response); err == nil && response.Code != 0 {
+			return nil, "", fmt.Errorf("导出模板失败: %s", response.Message)
+		}
+		return nil, "", fmt.Errorf("导出模板失败: 服务器返回了非模板包内容")
+	}
+
+	fileName := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		fileName = params["filename"]
+	}
+	return data, fileName, nil
+}
+
+// ImportTemplate 上传模板包导入模板，onConflict 为 error、rename 或 overwrite
+func (c *Client) ImportTemplate(bundlePath, onConflict, name string) (*ImportResult, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开模板包失败: %w", err)
+	}
+	defer file.Close()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("bundle", filepath.Base(bundlePath))
+	if err != nil {
+		return nil, fmt.Errorf("创建上传表单失败: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("读取模板包失败: %w", err)
+	}
+	if onConflict != "" {
+		writer.WriteField("onConflict", onConflict)
+	}
+	if name != "" {
+		writer.WriteField("name", name)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("创建上传表单失败: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.BaseURL+"/api/v1/templates/import", body)
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接到服务器 %s: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP请求失败: %d %s", resp.StatusCode, string(respBody))
+	}
+	var response Response
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if response.Code != 0 {
+		return nil, fmt.Errorf("导入模板失败: %s", response.Message)
+	}
+
+	var result ImportResult
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return nil, fmt.Errorf("解析导入结果失败: %w", err)
+	}
+	return &result, nil
+}
+
+// setAuthHeader 设置认证请求头，api_key 为 <keyId>.<secret> 形式的 API Key 时使用 ApiKey 认证方案，否则视为JWT令牌
+func (c *Client) setAuthHeader(req *http.Request) {
+	if c.APIKey == "" {
+		return
+	}
+	if strings.HasPrefix(c.APIKey, "ak_") && strings.Contains(c.APIKey, ".") {
+		req.Header.Set("Authorization", "ApiKey "+c.APIKey)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+}
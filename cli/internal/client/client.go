@@ -50,16 +50,43 @@ type TemplateLanguage struct {
 	Name string `json:"name"`
 }
 
-// TemplateVariable 模板变量结构
+// TemplateVariable 模板变量定义
 type TemplateVariable struct {
-	ID           int64  `json:"id"`
-	TemplateId   int64  `json:"templateId"`
-	Name         string `json:"name"`
-	VariableType string `json:"variableType"`
-	Description  string `json:"description"`
-	DefaultValue string `json:"defaultValue"`
-	IsRequired   int    `json:"isRequired"`
-	Sort         int    `json:"sort"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"` // string、secret、integer、number、boolean、enum、array、object、object_arr
+	Description string        `json:"description"`
+	Default     interface{}   `json:"default"`
+	Required    bool          `json:"required"`
+	Enum        []interface{} `json:"enum"`
+	Pattern     string        `json:"pattern"`
+	Min         *float64      `json:"min"`
+	Max         *float64      `json:"max"`
+	Computed    string        `json:"computed"` // 计算表达式，非空时变量值由服务端根据其他变量计算
+	Sort        int           `json:"sort"`
+}
+
+// DefaultString 返回默认值的文本形式，对象和数组以JSON表示，没有默认值时返回空字符串
+func (v TemplateVariable) DefaultString() string {
+	switch value := v.Default.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Options 返回枚举可选值的文本形式
+func (v TemplateVariable) Options() []string {
+	options := make([]string, 0, len(v.Enum))
+	for _, option := range v.Enum {
+		options = append(options, fmt.Sprint(option))
+	}
+	return options
 }
 
 // TemplateFile 模板文件结构
@@ -77,6 +104,40 @@ type RenderedFile struct {
 	IsDirectory bool   `json:"isDirectory"`
 }
 
+// RenderError 服务端返回的渲染错误详情
+type RenderError struct {
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Suggestion string `json:"suggestion"`
+}
+
+func (e *RenderError) String() string {
+	msg := e.Message
+	if e.Line > 0 {
+		msg = fmt.Sprintf("第%d行: %s", e.Line, msg)
+	}
+	if e.Suggestion != "" {
+		msg += "（" + e.Suggestion + "）"
+	}
+	return msg
+}
+
+// RenderDiagnostic 单个文件的渲染诊断信息
+type RenderDiagnostic struct {
+	FileId   int64        `json:"fileId"`
+	FilePath string       `json:"filePath"`
+	Part     string       `json:"part"`
+	Error    *RenderError `json:"error"`
+}
+
+// VariableValidationError 变量不符合模板变量定义时单个字段的错误
+type VariableValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 // TreeNode 树形文件结构
 type TreeNode struct {
 	ID          int64      `json:"id"`
@@ -169,9 +230,20 @@ func (c *Client) GetTemplateInfo(templateID string) (*Template, error) {
 	return detailResponse.TemplatesInfo, nil
 }
 
-// GetTemplateVariables 获取模板变量
-func (c *Client) GetTemplateVariables(templateID string) ([]TemplateVariable, error) {
+// ParseTemplateRef 解析 name@version 形式的模板引用，未指定版本时 version 为空，表示最新发布版本
+func ParseTemplateRef(ref string) (name, version string) {
+	if i := strings.LastIndex(ref, "@"); i > 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// GetTemplateVariables 获取模板指定版本的变量定义，version 为空时为最新发布版本
+func (c *Client) GetTemplateVariables(templateID, version string) ([]TemplateVariable, error) {
 	endpoint := fmt.Sprintf("/api/v1/templates/%s/variables", url.PathEscape(templateID))
+	if version != "" {
+		endpoint += "?version=" + url.QueryEscape(version)
+	}
 	
 	resp, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
@@ -193,13 +265,14 @@ func (c *Client) GetTemplateVariables(templateID string) ([]TemplateVariable, er
 	return variablesResponse.CustomVariables, nil
 }
 
-// RenderTemplate 渲染模板
-func (c *Client) RenderTemplate(templateID string, variables map[string]interface{}) ([]RenderedFile, error) {
+// RenderTemplate 渲染模板的指定版本，version 为空时为最新发布版本
+func (c *Client) RenderTemplate(templateID, version string, variables map[string]interface{}) ([]RenderedFile, error) {
 	endpoint := "/api/v1/templateFiles/renderFileTree"
 	
 	requestBody := map[string]interface{}{
 		"templateId": templateID,
 		"variables":  variables,
+		"version":    version,
 	}
 	
 	fmt.Printf("🔄 发送渲染请求: templateId=%s, variables=%+v\n", templateID, variables)
@@ -217,12 +290,30 @@ func (c *Client) RenderTemplate(templateID string, variables map[string]interfac
 	
 	// 解析树形响应结构
 	var renderResponse struct {
-		TemplateID int64      `json:"templateId"`
-		Tree       []TreeNode `json:"tree"`
+		TemplateID       int64                     `json:"templateId"`
+		Version          string                    `json:"version"`
+		Deprecated       bool                      `json:"deprecated"`
+		Tree             []TreeNode                `json:"tree"`
+		Error            *RenderError              `json:"error"`
+		Diagnostics      []RenderDiagnostic        `json:"diagnostics"`
+		ValidationErrors []VariableValidationError `json:"validationErrors"`
 	}
 	if err := json.Unmarshal(resp.Data, &renderResponse); err != nil {
 		return nil, fmt.Errorf("解析渲染结果失败: %w", err)
 	}
+	for _, validationError := range renderResponse.ValidationErrors {
+		fmt.Printf("❌ 变量 %s %s\n", validationError.Field, validationError.Message)
+	}
+	if renderResponse.Error != nil {
+		return nil, fmt.Errorf("模板渲染失败: %s", renderResponse.Error)
+	}
+	if renderResponse.Deprecated {
+		fmt.Printf("⚠️  模板版本 %s 已废弃，建议升级到最新版本\n", renderResponse.Version)
+	}
+	fmt.Printf("📌 渲染版本: %s\n", renderResponse.Version)
+	for _, diagnostic := range renderResponse.Diagnostics {
+		fmt.Printf("⚠️  %s 渲染失败 [%s]: %s\n", diagnostic.FilePath, diagnostic.Part, diagnostic.Error)
+	}
 	
 	fmt.Printf("✅ 解析树形结构成功，根节点数量: %d\n", len(renderResponse.Tree))
 	
@@ -323,9 +414,7 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*Respon
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	if c.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	}
+	c.setAuthHeader(req)
 	
 	// 发送请求
 	resp, err := c.HTTPClient.Do(req)
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// TestFileResult 测试用例中单个文件的比较结果
+type TestFileResult struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // passed、failed、missing 或 unexpected
+	Diff   string `json:"diff"`
+}
+
+// TestRenderError 变量校验失败或渲染出错时的错误详情
+type TestRenderError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+}
+
+// TestDiagnostic 单个文件的渲染错误
+type TestDiagnostic struct {
+	FilePath string           `json:"filePath"`
+	Part     string           `json:"part"`
+	Error    *TestRenderError `json:"error"`
+}
+
+// TestValidationError 变量校验错误
+type TestValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// TestCaseResult 单个测试用例的运行结果
+type TestCaseResult struct {
+	Name             string                `json:"name"`
+	Status           string                `json:"status"` // passed、failed 或 error
+	Accepted         bool                  `json:"accepted"`
+	Error            *TestRenderError      `json:"error"`
+	ValidationErrors []TestValidationError `json:"validationErrors"`
+	Diagnostics      []TestDiagnostic      `json:"diagnostics"`
+	Files            []TestFileResult      `json:"files"`
+}
+
+// TestRunResult 模板测试用例的运行结果
+type TestRunResult struct {
+	TemplateID int64            `json:"templateId"`
+	Version    string           `json:"version"`
+	Accepted   bool             `json:"accepted"`
+	Total      int              `json:"total"`
+	Passed     int              `json:"passed"`
+	Failed     int              `json:"failed"`
+	Errors     int              `json:"errors"`
+	Cases      []TestCaseResult `json:"cases"`
+}
+
+// RunTemplateTests 运行模板的测试用例，cases 为空时运行全部用例，accept 为 true 时以渲染结果覆盖期望结果
+func (c *Client) RunTemplateTests(templateID, version string, cases []string, accept bool) (*TestRunResult, json.RawMessage, error) {
+	endpoint := fmt.Sprintf("/api/v1/templates/%s/tests/run", url.PathEscape(templateID))
+	body := map[string]interface{}{
+		"version": version,
+		"cases":   cases,
+		"accept":  accept,
+	}
+
+	resp, err := c.makeRequest("POST", endpoint, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("请求运行模板测试失败: %w", err)
+	}
+	if resp.Code != 0 {
+		return nil, nil, fmt.Errorf("运行模板测试失败: %s", resp.Message)
+	}
+
+	var result TestRunResult
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, nil, fmt.Errorf("解析测试结果失败: %w", err)
+	}
+	return &result, resp.Data, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// LintIssue 模板检查发现的问题
+type LintIssue struct {
+	Severity string `json:"severity"` // error 或 warning
+	Rule     string `json:"rule"`
+	FileID   int64  `json:"fileId"`
+	File     string `json:"file"`
+	Part     string `json:"part"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// LintResult 模板检查结果
+type LintResult struct {
+	TemplateID   int64       `json:"templateId"`
+	Version      string      `json:"version"`
+	FileCount    int         `json:"fileCount"`
+	ErrorCount   int         `json:"errorCount"`
+	WarningCount int         `json:"warningCount"`
+	Issues       []LintIssue `json:"issues"`
+}
+
+// LintTemplate 检查模板，version 为空时检查当前工作区
+func (c *Client) LintTemplate(templateID, version string) (*LintResult, json.RawMessage, error) {
+	endpoint := fmt.Sprintf("/api/v1/templates/%s/lint", url.PathEscape(templateID))
+	if version != "" {
+		endpoint += "?version=" + url.QueryEscape(version)
+	}
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("请求模板检查失败: %w", err)
+	}
+	if resp.Code != 0 {
+		return nil, nil, fmt.Errorf("模板检查失败: %s", resp.Message)
+	}
+
+	var result LintResult
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, nil, fmt.Errorf("解析检查结果失败: %w", err)
+	}
+	return &result, resp.Data, nil
+}
@@ -31,6 +31,10 @@ func (c *Collector) CollectVariables(tmpl *client.Template) (map[string]interfac
 	fmt.Println()
 	
 	for _, variable := range tmpl.Variables {
+		// 计算变量由服务端根据其他变量生成，无需输入
+		if variable.Computed != "" {
+			continue
+		}
 		value, err := c.collectVariable(variable)
 		if err != nil {
 			return nil, err
@@ -45,7 +49,7 @@ func (c *Collector) CollectVariables(tmpl *client.Template) (map[string]interfac
 func (c *Collector) collectVariable(variable client.TemplateVariable) (interface{}, error) {
 	// 显示变量信息
 	fmt.Printf("📌 %s", variable.Name)
-	if variable.IsRequired == 1 {
+	if variable.Required {
 		fmt.Print(" (必需)")
 	}
 	fmt.Println()
@@ -54,17 +58,21 @@ func (c *Collector) collectVariable(variable client.TemplateVariable) (interface
 		fmt.Printf("   📄 %s\n", variable.Description)
 	}
 	
-	if variable.DefaultValue != "" {
-		fmt.Printf("   🔧 默认值: %s\n", variable.DefaultValue)
+	if variable.DefaultString() != "" {
+		fmt.Printf("   🔧 默认值: %s\n", variable.DefaultString())
+	}
+	
+	if len(variable.Enum) > 0 {
+		fmt.Printf("   📋 可选值: %s\n", strings.Join(variable.Options(), ", "))
 	}
 	
 	// 根据类型收集值
-	switch variable.VariableType {
-	case "text", "string":
+	switch variable.Type {
+	case "string", "secret", "enum":
 		return c.collectString(variable)
-	case "boolean", "conditional":
+	case "boolean":
 		return c.collectBoolean(variable)
-	case "number":
+	case "integer", "number":
 		return c.collectNumber(variable)
 	default:
 		return c.collectString(variable)
@@ -83,16 +91,21 @@ func (c *Collector) collectString(variable client.TemplateVariable) (string, err
 		input = strings.TrimSpace(input)
 		
 		// 如果为空且有默认值，使用默认值
-		if input == "" && variable.DefaultValue != "" {
-			return variable.DefaultValue, nil
+		if input == "" && variable.DefaultString() != "" {
+			return variable.DefaultString(), nil
 		}
 		
 		// 如果为空且是必需的，要求重新输入
-		if input == "" && variable.IsRequired == 1 {
+		if input == "" && variable.Required {
 			fmt.Println("   ❌ 此变量为必需，请输入值")
 			continue
 		}
 		
+		if input != "" && len(variable.Enum) > 0 && !containsOption(variable.Options(), input) {
+			fmt.Println("   ❌ 请输入可选值中的一项")
+			continue
+		}
+		
 		return input, nil
 	}
 }
@@ -109,8 +122,8 @@ func (c *Collector) collectBoolean(variable client.TemplateVariable) (bool, erro
 		input = strings.TrimSpace(strings.ToLower(input))
 		
 		// 如果为空且有默认值，使用默认值
-		if input == "" && variable.DefaultValue != "" {
-			defaultBool := variable.DefaultValue == "true" || variable.DefaultValue == "1"
+		if input == "" && variable.DefaultString() != "" {
+			defaultBool := variable.DefaultString() == "true" || variable.DefaultString() == "1"
 			return defaultBool, nil
 		}
 		
@@ -120,7 +133,7 @@ func (c *Collector) collectBoolean(variable client.TemplateVariable) (bool, erro
 		case "n", "no", "false", "0":
 			return false, nil
 		case "":
-			if variable.IsRequired == 1 {
+			if variable.Required {
 				fmt.Println("   ❌ 此变量为必需，请输入 y 或 n")
 				continue
 			}
@@ -144,14 +157,14 @@ func (c *Collector) collectNumber(variable client.TemplateVariable) (float64, er
 		input = strings.TrimSpace(input)
 		
 		// 如果为空且有默认值，使用默认值
-		if input == "" && variable.DefaultValue != "" {
-			if defaultVal, err := strconv.ParseFloat(variable.DefaultValue, 64); err == nil {
+		if input == "" && variable.DefaultString() != "" {
+			if defaultVal, err := strconv.ParseFloat(variable.DefaultString(), 64); err == nil {
 				return defaultVal, nil
 			}
 		}
 		
 		// 如果为空且是必需的，要求重新输入
-		if input == "" && variable.IsRequired == 1 {
+		if input == "" && variable.Required {
 			fmt.Println("   ❌ 此变量为必需，请输入数字")
 			continue
 		}
@@ -161,12 +174,35 @@ func (c *Collector) collectNumber(variable client.TemplateVariable) (float64, er
 		}
 		
 		// 尝试解析数字
-		if value, err := strconv.ParseFloat(input, 64); err == nil {
-			return value, nil
+		value, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			fmt.Println("   ❌ 请输入有效的数字")
+			continue
+		}
+		if variable.Type == "integer" && value != float64(int64(value)) {
+			fmt.Println("   ❌ 请输入整数")
+			continue
+		}
+		if variable.Min != nil && value < *variable.Min {
+			fmt.Printf("   ❌ 不能小于 %v\n", *variable.Min)
+			continue
+		}
+		if variable.Max != nil && value > *variable.Max {
+			fmt.Printf("   ❌ 不能大于 %v\n", *variable.Max)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// containsOption 判断输入是否为可选值之一
+func containsOption(options []string, input string) bool {
+	for _, option := range options {
+		if option == input {
+			return true
 		}
-		
-		fmt.Println("   ❌ 请输入有效的数字")
 	}
+	return false
 }
 
 // ConfirmGeneration 确认生成项目
@@ -1,7 +1,10 @@
 package interactive
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -83,44 +86,64 @@ func CollectVariables(template *client.Template) (map[string]interface{}, error)
 	}
 
 	for _, variable := range template.Variables {
+		// 计算变量由服务端根据其他变量生成，无需输入
+		if variable.Computed != "" {
+			continue
+		}
 		value, err := collectSingleVariable(variable)
 		if err != nil {
 			return nil, fmt.Errorf("收集变量 %s 失败: %w", variable.Name, err)
 		}
-		variables[variable.Name] = value
+		// 未填写的可选变量不提交，由服务端按变量定义处理
+		if value != nil {
+			variables[variable.Name] = value
+		}
 	}
 
 	return variables, nil
 }
 
-// collectSingleVariable 收集单个变量
+// collectSingleVariable 按变量类型收集单个变量
 func collectSingleVariable(variable client.TemplateVariable) (interface{}, error) {
 	// 构建提示信息
 	label := variable.Name
 	if variable.Description != "" {
 		label += fmt.Sprintf(" (%s)", variable.Description)
 	}
-	if variable.IsRequired == 1 {
+	if variable.Required {
 		label += " *"
 	}
 
-	switch variable.VariableType {
-	case "boolean", "conditional":
+	switch {
+	case variable.Type == "boolean":
 		return collectBooleanVariable(variable, label)
-	case "number":
+	case variable.Type == "integer" || variable.Type == "number":
 		return collectNumberVariable(variable, label)
-	case "select":
+	case variable.Type == "enum" || len(variable.Enum) > 0:
 		return collectSelectVariable(variable, label)
-	default: // string 或 text
+	case variable.Type == "array" || variable.Type == "object" || variable.Type == "object_arr":
+		return collectJSONVariable(variable, label)
+	default: // string、secret
 		return collectStringVariable(variable, label)
 	}
 }
 
-// collectStringVariable 收集字符串变量
-func collectStringVariable(variable client.TemplateVariable, label string) (string, error) {
+// collectStringVariable 收集字符串变量，secret 类型输入时不回显
+func collectStringVariable(variable client.TemplateVariable, label string) (interface{}, error) {
+	var pattern *regexp.Regexp
+	if variable.Pattern != "" {
+		pattern, _ = regexp.Compile(variable.Pattern)
+	}
 	validate := func(input string) error {
-		if variable.IsRequired == 1 && strings.TrimSpace(input) == "" {
-			return fmt.Errorf("该字段为必填项")
+		input = strings.TrimSpace(input)
+		if input == "" {
+			if variable.Required && variable.DefaultString() == "" {
+				return fmt.Errorf("该字段为必填项")
+			}
+			return nil
+		}
+		if pattern != nil && !pattern.MatchString(input) {
+			return fmt.Errorf("格式不正确，应匹配 %s", variable.Pattern)
 		}
 		return nil
 	}
@@ -129,26 +152,33 @@ func collectStringVariable(variable client.TemplateVariable, label string) (stri
 		Label:    label,
 		Validate: validate,
 	}
-
-	// 设置默认值
-	if variable.DefaultValue != "" {
-		prompt.Default = variable.DefaultValue
+	if variable.Type == "secret" {
+		prompt.Mask = '*'
+	} else {
+		prompt.Default = variable.DefaultString()
 	}
 
 	result, err := prompt.Run()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return strings.TrimSpace(result), nil
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return nil, nil
+	}
+	return result, nil
 }
 
 // collectBooleanVariable 收集布尔变量
 func collectBooleanVariable(variable client.TemplateVariable, label string) (bool, error) {
 	// 确定默认值
 	defaultValue := false
-	if variable.DefaultValue != "" {
-		defaultValue = variable.DefaultValue == "true" || variable.DefaultValue == "1"
+	switch value := variable.Default.(type) {
+	case bool:
+		defaultValue = value
+	case string:
+		defaultValue = value == "true" || value == "1"
 	}
 
 	prompt := promptui.Prompt{
@@ -174,16 +204,28 @@ func collectBooleanVariable(variable client.TemplateVariable, label string) (boo
 	return result == "y" || result == "yes" || result == "true", nil
 }
 
-// collectNumberVariable 收集数字变量
-func collectNumberVariable(variable client.TemplateVariable, label string) (float64, error) {
+// collectNumberVariable 收集数字变量，integer 类型只接受整数，并校验取值范围
+func collectNumberVariable(variable client.TemplateVariable, label string) (interface{}, error) {
 	validate := func(input string) error {
-		if variable.IsRequired == 1 && strings.TrimSpace(input) == "" {
-			return fmt.Errorf("该字段为必填项")
-		}
-		if input != "" {
-			if _, err := strconv.ParseFloat(input, 64); err != nil {
-				return fmt.Errorf("请输入有效的数字")
+		input = strings.TrimSpace(input)
+		if input == "" {
+			if variable.Required && variable.DefaultString() == "" {
+				return fmt.Errorf("该字段为必填项")
 			}
+			return nil
+		}
+		value, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return fmt.Errorf("请输入有效的数字")
+		}
+		if variable.Type == "integer" && value != math.Trunc(value) {
+			return fmt.Errorf("请输入整数")
+		}
+		if variable.Min != nil && value < *variable.Min {
+			return fmt.Errorf("不能小于 %v", *variable.Min)
+		}
+		if variable.Max != nil && value > *variable.Max {
+			return fmt.Errorf("不能大于 %v", *variable.Max)
 		}
 		return nil
 	}
@@ -191,30 +233,103 @@ func collectNumberVariable(variable client.TemplateVariable, label string) (floa
 	prompt := promptui.Prompt{
 		Label:    label,
 		Validate: validate,
+		Default:  variable.DefaultString(),
 	}
 
-	// 设置默认值
-	if variable.DefaultValue != "" {
-		prompt.Default = variable.DefaultValue
+	result, err := prompt.Run()
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := prompt.Run()
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(result, 64)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	if variable.Type == "integer" {
+		return int64(value), nil
+	}
+	return value, nil
+}
+
+// collectSelectVariable 从枚举可选值中选择
+func collectSelectVariable(variable client.TemplateVariable, label string) (interface{}, error) {
+	options := variable.Options()
+	if len(options) == 0 {
+		return collectStringVariable(variable, label)
 	}
 
-	if strings.TrimSpace(result) == "" {
-		return 0, nil
+	cursor := 0
+	for i, option := range options {
+		if option == variable.DefaultString() {
+			cursor = i
+		}
 	}
 
-	return strconv.ParseFloat(result, 64)
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     options,
+		CursorPos: cursor,
+	}
+
+	index, _, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return variable.Enum[index], nil
 }
 
-// collectSelectVariable 收集选择变量
-func collectSelectVariable(variable client.TemplateVariable, label string) (string, error) {
-	// 由于后端模型没有Options字段，这里回退到字符串输入
-	// 或者可以从Description中解析选项
-	return collectStringVariable(variable, label)
+// collectJSONVariable 以JSON形式收集数组和对象变量
+func collectJSONVariable(variable client.TemplateVariable, label string) (interface{}, error) {
+	parse := func(input string) (interface{}, error) {
+		var value interface{}
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			return nil, fmt.Errorf("请输入有效的JSON")
+		}
+		switch value.(type) {
+		case []interface{}:
+			if variable.Type == "object" {
+				return nil, fmt.Errorf("请输入JSON对象")
+			}
+		case map[string]interface{}:
+			if variable.Type != "object" {
+				return nil, fmt.Errorf("请输入JSON数组")
+			}
+		default:
+			return nil, fmt.Errorf("请输入JSON对象或数组")
+		}
+		return value, nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:   label + " (JSON)",
+		Default: variable.DefaultString(),
+		Validate: func(input string) error {
+			input = strings.TrimSpace(input)
+			if input == "" {
+				if variable.Required && variable.DefaultString() == "" {
+					return fmt.Errorf("该字段为必填项")
+				}
+				return nil
+			}
+			_, err := parse(input)
+			return err
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return nil, nil
+	}
+	return parse(result)
 }
 
 // ConfirmVariables 确认变量配置
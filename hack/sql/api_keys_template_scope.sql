@@ -0,0 +1,2 @@
+-- API Key 限定模板：permissions 为权限范围列表，template_ids 不为空时只能访问其中的模板
+ALTER TABLE `api_keys` ADD COLUMN `template_ids` json DEFAULT NULL COMMENT '限定访问的模板ID列表，为空表示不限' AFTER `permissions`;
@@ -0,0 +1,8 @@
+-- 接口限流策略：按用户、API Key 或匿名访问的客户端IP分别限流，修改后最多30秒生效。
+-- 部署在反向代理之后时需要在 trustedProxies 中配置代理地址，否则所有匿名请求按代理IP共用令牌桶
+INSERT INTO `system_config` (`config_key`, `config_value`, `config_group`, `config_type`, `display_name`, `description`, `is_public`, `is_required`, `default_value`, `sort_order`, `status`) VALUES
+('system.rate_limit',
+ '{"enabled":true,"policies":[{"name":"auth","routes":["/auth/login","/auth/register","/auth/refresh"],"limit":10,"window":60,"burst":5},{"name":"ai","routes":["/ai","/v1/chat/completions"],"limit":20,"window":60,"burst":5},{"name":"render","routes":["/templateFiles/render","/templateFiles/renderFileTree","/templateFiles/downloadZip","/templates/{templateId}/export","/templates/{templateId}/tests/run"],"limit":60,"window":60,"burst":20}],"trustedProxies":[]}',
+ 'system', 'json', '接口限流', '按路由分组的令牌桶限流策略，limit 为每 window 秒补充的令牌数，burst 为令牌桶容量，trustedProxies 为可信反向代理的IP或CIDR，只有来自可信代理的请求才使用 X-Forwarded-For 中的客户端IP', 0, 0,
+ '{"enabled":true,"policies":[{"name":"auth","routes":["/auth/login","/auth/register","/auth/refresh"],"limit":10,"window":60,"burst":5},{"name":"ai","routes":["/ai","/v1/chat/completions"],"limit":20,"window":60,"burst":5},{"name":"render","routes":["/templateFiles/render","/templateFiles/renderFileTree","/templateFiles/downloadZip","/templates/{templateId}/export","/templates/{templateId}/tests/run"],"limit":60,"window":60,"burst":20}],"trustedProxies":[]}',
+ 100, 1);
@@ -0,0 +1,24 @@
+-- 模板文件修订历史：文件每次新增、修改内容、重命名、移动、修改生成条件或删除时记录一条完整快照
+CREATE TABLE IF NOT EXISTS `template_file_revisions` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '修订ID，自增主键',
+    `file_id` bigint(20) NOT NULL COMMENT '模板文件ID，文件删除后仍保留',
+    `template_id` bigint(20) unsigned NOT NULL COMMENT '所属模板ID',
+    `revision` int(11) NOT NULL COMMENT '文件内的修订号，从1开始递增',
+    `action` varchar(20) NOT NULL COMMENT '变更类型：create/edit/rename/move/condition/repeat/delete/restore',
+    `file_path` varchar(500) NOT NULL DEFAULT '' COMMENT '文件路径（相对路径）',
+    `file_name` varchar(255) NOT NULL DEFAULT '' COMMENT '文件名',
+    `file_content` longtext DEFAULT NULL COMMENT '文件内容',
+    `file_size` int(10) unsigned NOT NULL DEFAULT 0 COMMENT '文件大小（字节）',
+    `is_directory` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否为目录',
+    `md5` varchar(32) NOT NULL DEFAULT '' COMMENT 'md5',
+    `sort` int(11) NOT NULL DEFAULT 0 COMMENT '排序',
+    `parent_id` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '父目录ID',
+    `generate_condition` text DEFAULT NULL COMMENT '生成条件，JSON格式',
+    `repeat_config` text DEFAULT NULL COMMENT '批量生成配置，JSON格式',
+    `author_id` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '修改人ID，匿名修改为0',
+    `author_name` varchar(50) NOT NULL DEFAULT '' COMMENT '修改人用户名',
+    `created_at` datetime DEFAULT NULL COMMENT '修改时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_file_revision` (`file_id`, `revision`),
+    KEY `idx_template_action` (`template_id`, `action`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='模板文件修订历史表';
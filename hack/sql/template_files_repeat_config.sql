@@ -0,0 +1,2 @@
+-- 模板文件批量生成配置：按数组变量为每个元素生成一个文件
+ALTER TABLE `template_files` ADD COLUMN `repeat_config` text DEFAULT NULL COMMENT '按数组变量批量生成配置，JSON格式' AFTER `generate_condition`;
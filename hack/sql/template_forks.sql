@@ -0,0 +1,16 @@
+-- 模板的上游来源：记录 Fork 出的模板来自哪个模板，以及最近一次同步时上游的文件内容，作为拉取上游修改时三方合并的基准
+CREATE TABLE IF NOT EXISTS `template_forks` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID，自增主键',
+    `template_id` bigint(20) unsigned NOT NULL COMMENT 'Fork 出的模板ID',
+    `upstream_template_id` bigint(20) unsigned NOT NULL COMMENT '上游模板ID',
+    `base_version` varchar(64) NOT NULL DEFAULT 'draft' COMMENT '基准对应的上游版本，draft 表示上游当时未发布的内容',
+    `base_files` longtext NOT NULL COMMENT '基准文件内容，JSON对象，键为文件路径，值为文件内容',
+    `synced_by_id` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '最近一次同步人ID',
+    `synced_by` varchar(64) NOT NULL DEFAULT '' COMMENT '最近一次同步人用户名',
+    `synced_at` datetime DEFAULT NULL COMMENT '最近一次拉取上游修改的时间，未拉取过为空',
+    `created_at` datetime DEFAULT NULL COMMENT 'Fork 时间',
+    `updated_at` datetime DEFAULT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_template` (`template_id`),
+    KEY `idx_upstream` (`upstream_template_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='模板上游来源表';
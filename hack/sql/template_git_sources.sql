@@ -0,0 +1,16 @@
+-- 模板的 git 来源：记录最近一次从 git 仓库导入的仓库、引用和提交，再次导入时据此报告变更
+CREATE TABLE IF NOT EXISTS `template_git_sources` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID，自增主键',
+    `template_id` bigint(20) unsigned NOT NULL COMMENT '所属模板ID',
+    `repository` varchar(1000) NOT NULL COMMENT '仓库路径，本地路径或 file:// 地址',
+    `ref` varchar(255) NOT NULL DEFAULT 'HEAD' COMMENT '导入的引用，分支、标签或提交',
+    `subdir` varchar(500) NOT NULL DEFAULT '' COMMENT '仓库中作为模板根目录的子目录，空表示仓库根目录',
+    `commit_sha` varchar(64) NOT NULL COMMENT '导入时引用指向的提交',
+    `commit_message` varchar(500) NOT NULL DEFAULT '' COMMENT '提交说明的首行',
+    `imported_by_id` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '导入人ID',
+    `imported_by` varchar(64) NOT NULL DEFAULT '' COMMENT '导入人用户名',
+    `created_at` datetime DEFAULT NULL COMMENT '首次导入时间',
+    `updated_at` datetime DEFAULT NULL COMMENT '最近导入时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_template` (`template_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='模板git来源表';
@@ -0,0 +1,17 @@
+-- 模板发布版本：发布时快照整棵文件树、生成条件和变量定义，发布后不可修改
+CREATE TABLE IF NOT EXISTS `template_releases` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '发布版本ID，自增主键',
+    `template_id` bigint(20) unsigned NOT NULL COMMENT '所属模板ID',
+    `version` varchar(64) NOT NULL COMMENT '语义化版本号，如 1.2.0',
+    `status` varchar(20) NOT NULL DEFAULT 'published' COMMENT '状态：published 已发布，deprecated 已废弃',
+    `changelog` text DEFAULT NULL COMMENT '版本说明',
+    `deprecated_reason` varchar(500) NOT NULL DEFAULT '' COMMENT '废弃原因',
+    `snapshot` longtext NOT NULL COMMENT '文件树、生成条件和变量定义快照，JSON格式',
+    `file_count` int(11) NOT NULL DEFAULT 0 COMMENT '快照中的文件数',
+    `deprecated_at` datetime DEFAULT NULL COMMENT '废弃时间',
+    `created_at` datetime DEFAULT NULL COMMENT '发布时间',
+    `updated_at` datetime DEFAULT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_template_version` (`template_id`, `version`),
+    KEY `idx_template_status` (`template_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='模板发布版本表';
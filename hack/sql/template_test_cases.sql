@@ -0,0 +1,14 @@
+-- 模板测试用例：一组变量值及其期望的渲染结果（golden），用于在修改模板时校验渲染输出
+CREATE TABLE IF NOT EXISTS `template_test_cases` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID，自增主键',
+    `template_id` bigint(20) unsigned NOT NULL COMMENT '所属模板ID',
+    `name` varchar(64) NOT NULL COMMENT '用例名称，同一模板内唯一',
+    `description` varchar(500) NOT NULL DEFAULT '' COMMENT '用例说明',
+    `variables` longtext NOT NULL COMMENT '渲染使用的变量值，JSON对象',
+    `expected` longtext NOT NULL COMMENT '期望的渲染结果，JSON对象，键为渲染后的文件路径，值为文件内容',
+    `full_tree` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否校验完整文件树：1-渲染结果中多出的文件视为失败，0-只校验期望中列出的文件',
+    `created_at` datetime DEFAULT NULL COMMENT '创建时间',
+    `updated_at` datetime DEFAULT NULL COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_template_name` (`template_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='模板测试用例表';
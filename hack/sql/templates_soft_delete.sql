@@ -0,0 +1,4 @@
+-- 模板软删除：删除的模板进入回收站，可恢复，超过保留期限后由定时任务彻底删除
+ALTER TABLE `templates` ADD COLUMN `deleted_at` datetime DEFAULT NULL COMMENT '删除时间，不为空表示在回收站中' AFTER `type_config`;
+ALTER TABLE `templates` ADD COLUMN `deleted_by` varchar(64) NOT NULL DEFAULT '' COMMENT '删除人用户名' AFTER `deleted_at`;
+ALTER TABLE `templates` ADD KEY `idx_deleted_at` (`deleted_at`);
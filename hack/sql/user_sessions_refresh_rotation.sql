@@ -0,0 +1,2 @@
+-- 刷新令牌轮换：会话即令牌家族，只有最近一次签发的刷新令牌有效，旧的刷新令牌再次使用时撤销整个会话
+ALTER TABLE `user_sessions` ADD COLUMN `refresh_token_id` varchar(64) DEFAULT NULL COMMENT '当前有效的刷新令牌ID（jti），每次刷新时轮换' AFTER `data`;
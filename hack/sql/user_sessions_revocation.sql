@@ -0,0 +1,4 @@
+-- 会话撤销：令牌通过 sid 绑定会话，会话被撤销、删除或过期后令牌立即失效
+ALTER TABLE `user_sessions` ADD COLUMN `last_seen_at` datetime DEFAULT NULL COMMENT '最后活跃时间' AFTER `expires_at`;
+ALTER TABLE `user_sessions` ADD COLUMN `revoked_at` datetime DEFAULT NULL COMMENT '撤销时间，不为空表示会话已失效' AFTER `last_seen_at`;
+ALTER TABLE `user_sessions` ADD KEY `idx_user_revoked` (`user_id`, `revoked_at`);
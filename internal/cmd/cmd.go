@@ -6,9 +6,11 @@ import (
 
 	_ "github.com/ciclebyte/template_starter/internal/logic"
 	"github.com/ciclebyte/template_starter/internal/router"
+	"github.com/ciclebyte/template_starter/internal/service"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gcmd"
+	"github.com/gogf/gf/v2/os/gcron"
 )
 
 var (
@@ -44,6 +46,22 @@ var (
 					}
 				})
 			})
+			
+			// 定时彻底删除在回收站中超过保留期限的模板
+			purgeCron := g.Cfg().MustGet(ctx, "trash.purgeCron", "@every 1h").String()
+			_, err = gcron.AddSingleton(ctx, purgeCron, func(ctx context.Context) {
+				purged, err := service.Templates().PurgeExpired(ctx)
+				if err != nil {
+					g.Log().Error(ctx, "清理回收站模板失败:", err)
+					return
+				}
+				if purged > 0 {
+					g.Log().Info(ctx, "已彻底删除回收站中过期的模板:", purged)
+				}
+			}, "template-trash-purge")
+			if err != nil {
+				return err
+			}
 			s.Run()
 			return nil
 		},
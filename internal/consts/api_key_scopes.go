@@ -0,0 +1,74 @@
+package consts
+
+// API Key 权限范围常量定义，每个范围对应一组系统权限编码（permissions.code）
+const (
+	// ApiKeyScopeTemplateRead 查看模板、文件、变量、发布版本等，以及分类、语言和标签
+	ApiKeyScopeTemplateRead = "template:read"
+
+	// ApiKeyScopeTemplateRender 渲染模板、下载生成结果和运行模板测试
+	ApiKeyScopeTemplateRender = "template:render"
+
+	// ApiKeyScopeTemplateWrite 创建模板，编辑模板信息和文件
+	ApiKeyScopeTemplateWrite = "template:write"
+
+	// ApiKeyScopeTemplateDelete 删除、恢复和彻底删除模板
+	ApiKeyScopeTemplateDelete = "template:delete"
+
+	// ApiKeyScopeTemplateManage 管理变量预设等全局模板配置
+	ApiKeyScopeTemplateManage = "template:manage"
+
+	// ApiKeyScopeCatalogManage 管理分类、语言和标签
+	ApiKeyScopeCatalogManage = "catalog:manage"
+
+	// ApiKeyScopeUserRead 查看用户、角色和权限
+	ApiKeyScopeUserRead = "user:read"
+
+	// ApiKeyScopeUserManage 管理用户、角色和权限
+	ApiKeyScopeUserManage = "user:manage"
+
+	// ApiKeyScopeSystem 系统配置、审计日志和统计分析
+	ApiKeyScopeSystem = "system"
+)
+
+// ApiKeyScopePermissions 权限范围包含的系统权限编码
+var ApiKeyScopePermissions = map[string][]string{
+	ApiKeyScopeTemplateRead:   {"template:read", "category:read", "language:read", "tag:read"},
+	ApiKeyScopeTemplateRender: {"template:use"},
+	ApiKeyScopeTemplateWrite:  {"template:create", "template:edit"},
+	ApiKeyScopeTemplateDelete: {"template:delete"},
+	ApiKeyScopeTemplateManage: {"template:manage"},
+	ApiKeyScopeCatalogManage:  {"category:manage", "language:manage", "tag:manage"},
+	ApiKeyScopeUserRead:       {"user:read", "role:read"},
+	ApiKeyScopeUserManage:     {"user:read", "user:manage", "user:assign_role", "role:read", "role:manage", "role:assign_permission"},
+	ApiKeyScopeSystem:         {"system:config", "system:audit", "system:analytics"},
+}
+
+// ApiKeyScopeLabels 权限范围标签映射
+var ApiKeyScopeLabels = map[string]string{
+	ApiKeyScopeTemplateRead:   "查看模板",
+	ApiKeyScopeTemplateRender: "渲染模板",
+	ApiKeyScopeTemplateWrite:  "编辑模板",
+	ApiKeyScopeTemplateDelete: "删除模板",
+	ApiKeyScopeTemplateManage: "管理模板配置",
+	ApiKeyScopeCatalogManage:  "管理分类、语言和标签",
+	ApiKeyScopeUserRead:       "查看用户和角色",
+	ApiKeyScopeUserManage:     "管理用户和角色",
+	ApiKeyScopeSystem:         "系统管理",
+}
+
+// IsValidApiKeyScope 验证权限范围是否有效
+func IsValidApiKeyScope(scope string) bool {
+	_, exists := ApiKeyScopePermissions[scope]
+	return exists
+}
+
+// ApiKeyScopesPermissions 获取一组权限范围包含的全部系统权限编码
+func ApiKeyScopesPermissions(scopes []string) map[string]bool {
+	permissions := make(map[string]bool)
+	for _, scope := range scopes {
+		for _, permission := range ApiKeyScopePermissions[scope] {
+			permissions[permission] = true
+		}
+	}
+	return permissions
+}
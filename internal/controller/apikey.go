@@ -80,6 +80,19 @@ func (c *apiKeyController) RegenerateApiKey(ctx context.Context, req *api.Regene
 	return
 }
 
+// GetApiKeyScopes 获取API Key权限范围
+func (c *apiKeyController) GetApiKeyScopes(ctx context.Context, req *api.GetApiKeyScopesReq) (res *api.GetApiKeyScopesRes, err error) {
+	res = new(api.GetApiKeyScopesRes)
+
+	result, err := service.ApiKey().GetApiKeyScopes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	*res = *result
+	return
+}
+
 // GetApiKeyLogs 获取API Key使用日志
 func (c *apiKeyController) GetApiKeyLogs(ctx context.Context, req *api.GetApiKeyLogsReq) (res *api.GetApiKeyLogsRes, err error) {
 	res = new(api.GetApiKeyLogsRes)
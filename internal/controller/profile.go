@@ -102,6 +102,44 @@ func (c *profileController) GetLoginHistory(ctx context.Context, req *profileApi
 	return
 }
 
+// ListSessions 获取登录会话列表
+func (c *profileController) ListSessions(ctx context.Context, req *profileApi.ListSessionsReq) (res *profileApi.ListSessionsRes, err error) {
+	res = new(profileApi.ListSessionsRes)
+
+	result, err := service.Profile().ListSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	*res = *result
+	return
+}
+
+// RevokeSession 撤销登录会话
+func (c *profileController) RevokeSession(ctx context.Context, req *profileApi.RevokeSessionReq) (res *profileApi.RevokeSessionRes, err error) {
+	res = new(profileApi.RevokeSessionRes)
+
+	_, err = service.Profile().RevokeSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// RevokeAllSessions 撤销全部登录会话
+func (c *profileController) RevokeAllSessions(ctx context.Context, req *profileApi.RevokeAllSessionsReq) (res *profileApi.RevokeAllSessionsRes, err error) {
+	res = new(profileApi.RevokeAllSessionsRes)
+
+	result, err := service.Profile().RevokeAllSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	*res = *result
+	return
+}
+
 // ============================================================================
 // 个人 API Key 管理
 // ============================================================================
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_bundle"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateBundleController 模板包导入导出控制器
+type templateBundleController struct{}
+
+var TemplateBundle = &templateBundleController{}
+
+// Export 导出模板包
+func (c *templateBundleController) Export(ctx context.Context, req *template_bundle.TemplateBundleExportReq) (res *template_bundle.TemplateBundleExportRes, err error) {
+	err = service.TemplateBundle().Export(ctx, req.TemplateId)
+	return
+}
+
+// Import 导入模板包
+func (c *templateBundleController) Import(ctx context.Context, req *template_bundle.TemplateBundleImportReq) (res *template_bundle.TemplateBundleImportRes, err error) {
+	return service.TemplateBundle().Import(ctx, req)
+}
@@ -132,3 +132,53 @@ func (c *templateFilesController) SetCondition(ctx context.Context, req *api.Tem
 func (c *templateFilesController) GetCondition(ctx context.Context, req *api.TemplateFilesGetConditionReq) (res *api.TemplateFilesGetConditionRes, err error) {
 	return service.TemplateFiles().GetCondition(ctx, req)
 }
+
+// SetRepeat 设置文件批量生成配置
+func (c *templateFilesController) SetRepeat(ctx context.Context, req *api.TemplateFilesSetRepeatReq) (res *api.TemplateFilesSetRepeatRes, err error) {
+	res = new(api.TemplateFilesSetRepeatRes)
+	err = service.TemplateFiles().SetRepeat(ctx, req)
+	return
+}
+
+// GetRepeat 获取文件批量生成配置
+func (c *templateFilesController) GetRepeat(ctx context.Context, req *api.TemplateFilesGetRepeatReq) (res *api.TemplateFilesGetRepeatRes, err error) {
+	return service.TemplateFiles().GetRepeat(ctx, req)
+}
+
+// Revisions 获取文件修订历史
+func (c *templateFilesController) Revisions(ctx context.Context, req *api.TemplateFilesRevisionsReq) (res *api.TemplateFilesRevisionsRes, err error) {
+	res = new(api.TemplateFilesRevisionsRes)
+	res.Revisions, err = service.TemplateFiles().Revisions(ctx, gconv.Int64(req.Id))
+	return
+}
+
+// RevisionDetail 获取文件修订内容
+func (c *templateFilesController) RevisionDetail(ctx context.Context, req *api.TemplateFilesRevisionDetailReq) (res *api.TemplateFilesRevisionDetailRes, err error) {
+	res = new(api.TemplateFilesRevisionDetailRes)
+	res.TemplateFileRevisionInfo, err = service.TemplateFiles().RevisionDetail(ctx, gconv.Int64(req.Id), req.Revision)
+	return
+}
+
+// RevisionDiff 比较文件的两个修订
+func (c *templateFilesController) RevisionDiff(ctx context.Context, req *api.TemplateFilesRevisionDiffReq) (res *api.TemplateFilesRevisionDiffRes, err error) {
+	return service.TemplateFiles().RevisionDiff(ctx, req)
+}
+
+// RestoreRevision 恢复文件到指定修订
+func (c *templateFilesController) RestoreRevision(ctx context.Context, req *api.TemplateFilesRevisionRestoreReq) (res *api.TemplateFilesRevisionRestoreRes, err error) {
+	res = new(api.TemplateFilesRevisionRestoreRes)
+	res.TemplateFileRevisionInfo, err = service.TemplateFiles().RestoreRevision(ctx, req)
+	return
+}
+
+// Deleted 获取已删除的文件
+func (c *templateFilesController) Deleted(ctx context.Context, req *api.TemplateFilesDeletedReq) (res *api.TemplateFilesDeletedRes, err error) {
+	res = new(api.TemplateFilesDeletedRes)
+	res.Files, err = service.TemplateFiles().DeletedFiles(ctx, gconv.Int64(req.TemplateId))
+	return
+}
+
+// Lint 检查模板
+func (c *templateFilesController) Lint(ctx context.Context, req *api.TemplateFilesLintReq) (res *api.TemplateFilesLintRes, err error) {
+	return service.TemplateFiles().Lint(ctx, req)
+}
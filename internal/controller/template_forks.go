@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_forks"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateForksController 模板上游控制器
+type templateForksController struct{}
+
+var TemplateForks = &templateForksController{}
+
+// Upstream 获取模板的上游来源
+func (c *templateForksController) Upstream(ctx context.Context, req *template_forks.TemplateForksUpstreamReq) (res *template_forks.TemplateForksUpstreamRes, err error) {
+	res = new(template_forks.TemplateForksUpstreamRes)
+	res.Upstream, err = service.TemplateForks().Upstream(ctx, req.TemplateId)
+	return
+}
+
+// Pull 拉取上游修改
+func (c *templateForksController) Pull(ctx context.Context, req *template_forks.TemplateForksPullReq) (res *template_forks.TemplateForksPullRes, err error) {
+	return service.TemplateForks().Pull(ctx, req)
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_git"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateGitController 模板git来源控制器
+type templateGitController struct{}
+
+var TemplateGit = &templateGitController{}
+
+// Import 从本地git仓库导入模板文件
+func (c *templateGitController) Import(ctx context.Context, req *template_git.TemplateGitImportReq) (res *template_git.TemplateGitImportRes, err error) {
+	return service.TemplateGit().Import(ctx, req)
+}
+
+// Source 获取模板的git来源
+func (c *templateGitController) Source(ctx context.Context, req *template_git.TemplateGitSourceReq) (res *template_git.TemplateGitSourceRes, err error) {
+	source, err := service.TemplateGit().Source(ctx, req.TemplateId)
+	if err != nil {
+		return
+	}
+	res = &template_git.TemplateGitSourceRes{TemplateGitSourceInfo: source}
+	return
+}
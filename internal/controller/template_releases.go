@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_releases"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateReleasesController 模板发布版本控制器
+type templateReleasesController struct{}
+
+var TemplateReleases = &templateReleasesController{}
+
+// List 获取模板发布版本列表
+func (c *templateReleasesController) List(ctx context.Context, req *template_releases.TemplateReleasesListReq) (res *template_releases.TemplateReleasesListRes, err error) {
+	res = new(template_releases.TemplateReleasesListRes)
+	res.Releases, err = service.TemplateReleases().List(ctx, req)
+	return
+}
+
+// Publish 发布模板版本
+func (c *templateReleasesController) Publish(ctx context.Context, req *template_releases.TemplateReleasesPublishReq) (res *template_releases.TemplateReleasesPublishRes, err error) {
+	res = new(template_releases.TemplateReleasesPublishRes)
+	res.Release, err = service.TemplateReleases().Publish(ctx, req)
+	return
+}
+
+// Deprecate 废弃模板发布版本
+func (c *templateReleasesController) Deprecate(ctx context.Context, req *template_releases.TemplateReleasesDeprecateReq) (res *template_releases.TemplateReleasesDeprecateRes, err error) {
+	res = new(template_releases.TemplateReleasesDeprecateRes)
+	err = service.TemplateReleases().Deprecate(ctx, req)
+	return
+}
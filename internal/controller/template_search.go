@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_search"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateSearchController 模板文件搜索控制器
+type templateSearchController struct{}
+
+var TemplateSearch = &templateSearchController{}
+
+// Search 搜索模板文件内容
+func (c *templateSearchController) Search(ctx context.Context, req *template_search.TemplateSearchReq) (res *template_search.TemplateSearchRes, err error) {
+	return service.TemplateSearch().Search(ctx, req)
+}
+
+// Replace 批量替换选中的匹配
+func (c *templateSearchController) Replace(ctx context.Context, req *template_search.TemplateReplaceReq) (res *template_search.TemplateReplaceRes, err error) {
+	return service.TemplateSearch().Replace(ctx, req)
+}
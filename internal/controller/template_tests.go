@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/template_tests"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templateTestsController 模板测试用例控制器
+type templateTestsController struct{}
+
+var TemplateTests = &templateTestsController{}
+
+// List 获取模板测试用例列表
+func (c *templateTestsController) List(ctx context.Context, req *template_tests.TemplateTestsListReq) (res *template_tests.TemplateTestsListRes, err error) {
+	res = new(template_tests.TemplateTestsListRes)
+	res.Cases, err = service.TemplateTests().List(ctx, req)
+	return
+}
+
+// Detail 获取模板测试用例详情
+func (c *templateTestsController) Detail(ctx context.Context, req *template_tests.TemplateTestsDetailReq) (res *template_tests.TemplateTestsDetailRes, err error) {
+	res = new(template_tests.TemplateTestsDetailRes)
+	res.Case, err = service.TemplateTests().Detail(ctx, req)
+	return
+}
+
+// Add 新增模板测试用例
+func (c *templateTestsController) Add(ctx context.Context, req *template_tests.TemplateTestsAddReq) (res *template_tests.TemplateTestsAddRes, err error) {
+	res = new(template_tests.TemplateTestsAddRes)
+	res.Case, err = service.TemplateTests().Add(ctx, req)
+	return
+}
+
+// Edit 修改模板测试用例
+func (c *templateTestsController) Edit(ctx context.Context, req *template_tests.TemplateTestsEditReq) (res *template_tests.TemplateTestsEditRes, err error) {
+	res = new(template_tests.TemplateTestsEditRes)
+	res.Case, err = service.TemplateTests().Edit(ctx, req)
+	return
+}
+
+// Delete 删除模板测试用例
+func (c *templateTestsController) Delete(ctx context.Context, req *template_tests.TemplateTestsDeleteReq) (res *template_tests.TemplateTestsDeleteRes, err error) {
+	res = new(template_tests.TemplateTestsDeleteRes)
+	err = service.TemplateTests().Delete(ctx, req)
+	return
+}
+
+// Run 运行模板测试用例
+func (c *templateTestsController) Run(ctx context.Context, req *template_tests.TemplateTestsRunReq) (res *template_tests.TemplateTestsRunRes, err error) {
+	return service.TemplateTests().Run(ctx, req)
+}
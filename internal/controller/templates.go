@@ -65,8 +65,31 @@ func (c *templatesController) BatchDelete(ctx context.Context, req *api.Template
 	return
 }
 
+func (c *templatesController) Trash(ctx context.Context, req *api.TemplatesTrashReq) (res *api.TemplatesTrashRes, err error) {
+	res = new(api.TemplatesTrashRes)
+	if req.PageSize == 0 {
+		req.PageSize = consts.PageSize
+	}
+	if req.PageNum == 0 {
+		req.PageNum = 1
+	}
+	res.Total, res.TrashList, res.RetentionDays, err = service.Templates().Trash(ctx, req)
+	res.CurrentPage = req.PageNum
+	return
+}
+
+func (c *templatesController) Restore(ctx context.Context, req *api.TemplatesRestoreReq) (res *api.TemplatesRestoreRes, err error) {
+	err = service.Templates().Restore(ctx, req.Ids)
+	return
+}
+
+func (c *templatesController) Purge(ctx context.Context, req *api.TemplatesPurgeReq) (res *api.TemplatesPurgeRes, err error) {
+	err = service.Templates().Purge(ctx, req.Ids)
+	return
+}
+
 func (c *templatesController) GetVariables(ctx context.Context, req *api.TemplatesVariablesReq) (res *api.TemplatesVariablesRes, err error) {
-	res, err = service.Templates().GetVariables(ctx, gconv.Int64(req.TemplateId))
+	res, err = service.Templates().GetVariables(ctx, gconv.Int64(req.TemplateId), req.Version)
 	return
 }
 
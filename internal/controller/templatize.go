@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ciclebyte/template_starter/api/v1/templatize"
+	"github.com/ciclebyte/template_starter/internal/service"
+)
+
+// templatizeController 模板化控制器
+type templatizeController struct{}
+
+var Templatize = &templatizeController{}
+
+// Preview 预览模板化的修改
+func (c *templatizeController) Preview(ctx context.Context, req *templatize.TemplatizePreviewReq) (res *templatize.TemplatizePreviewRes, err error) {
+	res = new(templatize.TemplatizePreviewRes)
+	res.TemplatizeResult, err = service.Templatize().Preview(ctx, req)
+	return
+}
+
+// Apply 将字面量替换为模板变量
+func (c *templatizeController) Apply(ctx context.Context, req *templatize.TemplatizeApplyReq) (res *templatize.TemplatizeApplyRes, err error) {
+	res = new(templatize.TemplatizeApplyRes)
+	res.TemplatizeResult, err = service.Templatize().Apply(ctx, req)
+	return
+}
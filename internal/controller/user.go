@@ -100,6 +100,19 @@ func (c *userController) UpdateUserStatus(ctx context.Context, req *api.UpdateUs
 	return
 }
 
+// RevokeUserSessions 撤销用户的全部登录会话
+func (c *userController) RevokeUserSessions(ctx context.Context, req *api.RevokeUserSessionsReq) (res *api.RevokeUserSessionsRes, err error) {
+	res = new(api.RevokeUserSessionsRes)
+
+	result, err := service.User().RevokeUserSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	*res = *result
+	return
+}
+
 // AssignUserRoles 分配用户角色
 func (c *userController) AssignUserRoles(ctx context.Context, req *api.AssignUserRolesReq) (res *api.AssignUserRolesRes, err error) {
 	res = new(api.AssignUserRolesRes)
@@ -26,6 +26,7 @@ type ApiKeysColumns struct {
 	KeyId       string // API Key ID (公开标识)
 	KeySecret   string // API Key Secret (加密存储)
 	Permissions string // API Key 权限列表
+	TemplateIds string // 限定访问的模板ID列表
 	LastUsedAt  string // 最后使用时间
 	LastUsedIp  string // 最后使用IP
 	ExpiresAt   string // 过期时间
@@ -42,6 +43,7 @@ var apiKeysColumns = ApiKeysColumns{
 	KeyId:       "key_id",
 	KeySecret:   "key_secret",
 	Permissions: "permissions",
+	TemplateIds: "template_ids",
 	LastUsedAt:  "last_used_at",
 	LastUsedIp:  "last_used_ip",
 	ExpiresAt:   "expires_at",
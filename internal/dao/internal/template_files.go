@@ -33,6 +33,7 @@ type TemplateFilesColumns struct {
 	CreatedAt         string // 记录创建时间
 	UpdatedAt         string //
 	GenerateCondition string //
+	RepeatConfig      string // 按数组变量批量生成配置，JSON格式
 }
 
 // templateFilesColumns holds the columns for table template_files.
@@ -50,6 +51,7 @@ var templateFilesColumns = TemplateFilesColumns{
 	CreatedAt:         "created_at",
 	UpdatedAt:         "updated_at",
 	GenerateCondition: "generate_condition",
+	RepeatConfig:      "repeat_config",
 }
 
 // NewTemplateFilesDao creates and returns a new DAO object for table data access.
@@ -32,6 +32,8 @@ type TemplatesColumns struct {
 	Icon         string // 模板图标名称
 	TemplateType string // 模板类型：basic=基础模板，scaffold=脚手架模板，data_driven=数据驱动模板
 	TypeConfig   string // 类型相关配置，JSON格式
+	DeletedAt    string // 删除时间，不为空表示在回收站中
+	DeletedBy    string // 删除人用户名
 }
 
 // templatesColumns holds the columns for table templates.
@@ -48,6 +50,8 @@ var templatesColumns = TemplatesColumns{
 	Icon:         "icon",
 	TemplateType: "template_type",
 	TypeConfig:   "type_config",
+	DeletedAt:    "deleted_at",
+	DeletedBy:    "deleted_by",
 }
 
 // NewTemplatesDao creates and returns a new DAO object for table data access.
@@ -20,26 +20,32 @@ type UserSessionsDao struct {
 
 // UserSessionsColumns defines and stores column names for table user_sessions.
 type UserSessionsColumns struct {
-	Id        string // 会话ID
-	UserId    string // 用户ID
-	IpAddress string // IP地址
-	UserAgent string // 用户代理
-	Data      string // 会话数据
-	ExpiresAt string // 过期时间
-	CreatedAt string //
-	UpdatedAt string //
+	Id             string // 会话ID
+	UserId         string // 用户ID
+	IpAddress      string // IP地址
+	UserAgent      string // 用户代理
+	Data           string // 会话数据
+	RefreshTokenId string // 当前有效的刷新令牌ID（jti），每次刷新时轮换
+	ExpiresAt      string // 过期时间
+	LastSeenAt     string // 最后活跃时间
+	RevokedAt      string // 撤销时间，不为空表示会话已失效
+	CreatedAt      string //
+	UpdatedAt      string //
 }
 
 // userSessionsColumns holds the columns for table user_sessions.
 var userSessionsColumns = UserSessionsColumns{
-	Id:        "id",
-	UserId:    "user_id",
-	IpAddress: "ip_address",
-	UserAgent: "user_agent",
-	Data:      "data",
-	ExpiresAt: "expires_at",
-	CreatedAt: "created_at",
-	UpdatedAt: "updated_at",
+	Id:             "id",
+	UserId:         "user_id",
+	IpAddress:      "ip_address",
+	UserAgent:      "user_agent",
+	Data:           "data",
+	RefreshTokenId: "refresh_token_id",
+	ExpiresAt:      "expires_at",
+	LastSeenAt:     "last_seen_at",
+	RevokedAt:      "revoked_at",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
 }
 
 // NewUserSessionsDao creates and returns a new DAO object for table data access.
@@ -0,0 +1,27 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"github.com/ciclebyte/template_starter/internal/dao/internal"
+)
+
+// internalTemplateFileRevisionsDao is internal type for wrapping internal DAO implements.
+type internalTemplateFileRevisionsDao = *internal.TemplateFileRevisionsDao
+
+// templateFileRevisionsDao is the data access object for table template_file_revisions.
+// You can define custom methods on it to extend its functionality as you wish.
+type templateFileRevisionsDao struct {
+	internalTemplateFileRevisionsDao
+}
+
+var (
+	// TemplateFileRevisions is globally public accessible object for table template_file_revisions operations.
+	TemplateFileRevisions = templateFileRevisionsDao{
+		internal.NewTemplateFileRevisionsDao(),
+	}
+)
+
+// Fill with you ideas below.
\ No newline at end of file
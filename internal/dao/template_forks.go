@@ -0,0 +1,27 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"github.com/ciclebyte/template_starter/internal/dao/internal"
+)
+
+// internalTemplateForksDao is internal type for wrapping internal DAO implements.
+type internalTemplateForksDao = *internal.TemplateForksDao
+
+// templateForksDao is the data access object for table template_forks.
+// You can define custom methods on it to extend its functionality as you wish.
+type templateForksDao struct {
+	internalTemplateForksDao
+}
+
+var (
+	// TemplateForks is globally public accessible object for table template_forks operations.
+	TemplateForks = templateForksDao{
+		internal.NewTemplateForksDao(),
+	}
+)
+
+// Fill with you ideas below.
\ No newline at end of file
@@ -0,0 +1,27 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"github.com/ciclebyte/template_starter/internal/dao/internal"
+)
+
+// internalTemplateGitSourcesDao is internal type for wrapping internal DAO implements.
+type internalTemplateGitSourcesDao = *internal.TemplateGitSourcesDao
+
+// templateGitSourcesDao is the data access object for table template_git_sources.
+// You can define custom methods on it to extend its functionality as you wish.
+type templateGitSourcesDao struct {
+	internalTemplateGitSourcesDao
+}
+
+var (
+	// TemplateGitSources is globally public accessible object for table template_git_sources operations.
+	TemplateGitSources = templateGitSourcesDao{
+		internal.NewTemplateGitSourcesDao(),
+	}
+)
+
+// Fill with you ideas below.
\ No newline at end of file
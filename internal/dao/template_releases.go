@@ -0,0 +1,27 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"github.com/ciclebyte/template_starter/internal/dao/internal"
+)
+
+// internalTemplateReleasesDao is internal type for wrapping internal DAO implements.
+type internalTemplateReleasesDao = *internal.TemplateReleasesDao
+
+// templateReleasesDao is the data access object for table template_releases.
+// You can define custom methods on it to extend its functionality as you wish.
+type templateReleasesDao struct {
+	internalTemplateReleasesDao
+}
+
+var (
+	// TemplateReleases is globally public accessible object for table template_releases operations.
+	TemplateReleases = templateReleasesDao{
+		internal.NewTemplateReleasesDao(),
+	}
+)
+
+// Fill with you ideas below.
\ No newline at end of file
@@ -0,0 +1,27 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"github.com/ciclebyte/template_starter/internal/dao/internal"
+)
+
+// internalTemplateTestCasesDao is internal type for wrapping internal DAO implements.
+type internalTemplateTestCasesDao = *internal.TemplateTestCasesDao
+
+// templateTestCasesDao is the data access object for table template_test_cases.
+// You can define custom methods on it to extend its functionality as you wish.
+type templateTestCasesDao struct {
+	internalTemplateTestCasesDao
+}
+
+var (
+	// TemplateTestCases is globally public accessible object for table template_test_cases operations.
+	TemplateTestCases = templateTestCasesDao{
+		internal.NewTemplateTestCasesDao(),
+	}
+)
+
+// Fill with you ideas below.
\ No newline at end of file
@@ -6,13 +6,17 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/ciclebyte/template_starter/api/v1/apikey"
+	"github.com/ciclebyte/template_starter/internal/consts"
 	"github.com/ciclebyte/template_starter/internal/dao"
 	"github.com/ciclebyte/template_starter/internal/model/do"
 	"github.com/ciclebyte/template_starter/internal/model/entity"
 	"github.com/ciclebyte/template_starter/internal/service"
 	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
@@ -91,10 +95,10 @@ func (s *sApiKey) CreateApiKey(ctx context.Context, req *apikey.CreateApiKeyReq)
 		return nil, err
 	}
 
-	// 转换权限为JSON
-	permissionsJson := ""
-	if len(req.Permissions) > 0 {
-		permissionsJson = gconv.String(req.Permissions)
+	// 校验并转换权限范围和限定模板为JSON
+	permissionsJson, templateIds, err := s.encodeScope(ctx, req.Permissions, req.TemplateIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// 插入数据库
@@ -104,6 +108,7 @@ func (s *sApiKey) CreateApiKey(ctx context.Context, req *apikey.CreateApiKeyReq)
 		KeyId:       keyId,
 		KeySecret:   hashedSecret,
 		Permissions: permissionsJson,
+		TemplateIds: templateIds,
 		ExpiresAt:   req.ExpiresAt,
 		Status:      1,
 		CreatedAt:   gtime.Now(),
@@ -130,16 +135,17 @@ func (s *sApiKey) CreateApiKey(ctx context.Context, req *apikey.CreateApiKeyReq)
 
 // UpdateApiKey 更新API Key
 func (s *sApiKey) UpdateApiKey(ctx context.Context, req *apikey.UpdateApiKeyReq) (*apikey.UpdateApiKeyRes, error) {
-	// 转换权限为JSON
-	permissionsJson := ""
-	if len(req.Permissions) > 0 {
-		permissionsJson = gconv.String(req.Permissions)
+	// 校验并转换权限范围和限定模板为JSON
+	permissionsJson, templateIds, err := s.encodeScope(ctx, req.Permissions, req.TemplateIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// 更新数据库
-	_, err := dao.ApiKeys.Ctx(ctx).Data(do.ApiKeys{
+	_, err = dao.ApiKeys.Ctx(ctx).Data(do.ApiKeys{
 		Name:        req.Name,
 		Permissions: permissionsJson,
+		TemplateIds: templateIds,
 		ExpiresAt:   req.ExpiresAt,
 		Status:      req.Status,
 		UpdatedAt:   gtime.Now(),
@@ -254,10 +260,10 @@ func (s *sApiKey) CreateMyApiKey(ctx context.Context, req *apikey.CreateMyApiKey
 		return nil, err
 	}
 
-	// 转换权限为JSON
-	permissionsJson := ""
-	if len(req.Permissions) > 0 {
-		permissionsJson = gconv.String(req.Permissions)
+	// 校验并转换权限范围和限定模板为JSON
+	permissionsJson, templateIds, err := s.encodeScope(ctx, req.Permissions, req.TemplateIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// 插入数据库
@@ -267,6 +273,7 @@ func (s *sApiKey) CreateMyApiKey(ctx context.Context, req *apikey.CreateMyApiKey
 		KeyId:       keyId,
 		KeySecret:   hashedSecret,
 		Permissions: permissionsJson,
+		TemplateIds: templateIds,
 		ExpiresAt:   req.ExpiresAt,
 		Status:      1,
 		CreatedAt:   gtime.Now(),
@@ -300,16 +307,17 @@ func (s *sApiKey) UpdateMyApiKey(ctx context.Context, req *apikey.UpdateMyApiKey
 	}
 	userId := gconv.Int64(userIdVar)
 
-	// 转换权限为JSON
-	permissionsJson := ""
-	if len(req.Permissions) > 0 {
-		permissionsJson = gconv.String(req.Permissions)
+	// 校验并转换权限范围和限定模板为JSON
+	permissionsJson, templateIds, err := s.encodeScope(ctx, req.Permissions, req.TemplateIds)
+	if err != nil {
+		return nil, err
 	}
 
 	// 更新数据库（只能更新自己的API Key）
-	_, err := dao.ApiKeys.Ctx(ctx).Data(do.ApiKeys{
+	_, err = dao.ApiKeys.Ctx(ctx).Data(do.ApiKeys{
 		Name:        req.Name,
 		Permissions: permissionsJson,
+		TemplateIds: templateIds,
 		ExpiresAt:   req.ExpiresAt,
 		Status:      req.Status,
 		UpdatedAt:   gtime.Now(),
@@ -369,6 +377,25 @@ func (s *sApiKey) RegenerateMyApiKey(ctx context.Context, req *apikey.Regenerate
 	}, nil
 }
 
+// GetApiKeyScopes 获取API Key可用的权限范围及其包含的系统权限编码
+func (s *sApiKey) GetApiKeyScopes(ctx context.Context, req *apikey.GetApiKeyScopesReq) (*apikey.GetApiKeyScopesRes, error) {
+	scopes := make([]string, 0, len(consts.ApiKeyScopePermissions))
+	for scope := range consts.ApiKeyScopePermissions {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	list := make([]apikey.ApiKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		list = append(list, apikey.ApiKeyScope{
+			Scope:       scope,
+			Label:       consts.ApiKeyScopeLabels[scope],
+			Permissions: consts.ApiKeyScopePermissions[scope],
+		})
+	}
+	return &apikey.GetApiKeyScopesRes{List: list}, nil
+}
+
 // GetApiKeyLogs 获取API Key使用日志
 func (s *sApiKey) GetApiKeyLogs(ctx context.Context, req *apikey.GetApiKeyLogsReq) (*apikey.GetApiKeyLogsRes, error) {
 	var (
@@ -450,6 +477,38 @@ func (s *sApiKey) generateApiKey() (keyId, keySecret, hashedSecret string, err e
 	return
 }
 
+// encodeScope 校验权限范围和限定的模板，转换为存储的JSON，不限定模板时写入NULL
+func (s *sApiKey) encodeScope(ctx context.Context, scopes []string, templateIds []int64) (permissionsJson string, templateIdsJson interface{}, err error) {
+	for _, scope := range scopes {
+		if !consts.IsValidApiKeyScope(scope) {
+			return "", nil, fmt.Errorf("无效的权限范围: %s", scope)
+		}
+	}
+	if len(scopes) > 0 {
+		permissionsJson = gconv.String(scopes)
+	}
+
+	if len(templateIds) == 0 {
+		return permissionsJson, gdb.Raw("NULL"), nil
+	}
+	unique := make([]int64, 0, len(templateIds))
+	seen := make(map[int64]bool, len(templateIds))
+	for _, id := range templateIds {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	count, err := dao.Templates.Ctx(ctx).WhereIn(dao.Templates.Columns().Id, unique).Count()
+	if err != nil {
+		return "", nil, err
+	}
+	if count != len(unique) {
+		return "", nil, errors.New("限定的模板不存在")
+	}
+	return permissionsJson, gconv.String(unique), nil
+}
+
 // convertToApiKeyResponse 转换entity为API响应格式
 func (s *sApiKey) convertToApiKeyResponse(item entity.ApiKeys) apikey.ApiKey {
 	// 解析权限JSON
@@ -457,6 +516,10 @@ func (s *sApiKey) convertToApiKeyResponse(item entity.ApiKeys) apikey.ApiKey {
 	if item.Permissions != "" {
 		gconv.Scan(item.Permissions, &permissions)
 	}
+	var templateIds []int64
+	if item.TemplateIds != "" {
+		gconv.Scan(item.TemplateIds, &templateIds)
+	}
 
 	return apikey.ApiKey{
 		Id:          int64(item.Id),
@@ -465,6 +528,7 @@ func (s *sApiKey) convertToApiKeyResponse(item entity.ApiKeys) apikey.ApiKey {
 		KeyId:       item.KeyId,
 		KeySecret:   "", // 不返回Secret
 		Permissions: permissions,
+		TemplateIds: templateIds,
 		LastUsedAt:  item.LastUsedAt,
 		LastUsedIp:  item.LastUsedIp,
 		ExpiresAt:   item.ExpiresAt,
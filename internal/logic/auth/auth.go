@@ -2,7 +2,6 @@ package auth
 
 import (
 	"context"
-	"crypto/md5"
 	"errors"
 	"fmt"
 	"time"
@@ -20,6 +19,9 @@ import (
 	"github.com/gogf/gf/v2/util/gconv"
 )
 
+// sessionTouchInterval 会话最后活跃时间的更新间隔
+const sessionTouchInterval = time.Minute
+
 type sAuth struct{}
 
 func init() {
@@ -113,8 +115,10 @@ func (s *sAuth) Register(ctx context.Context, req *service.RegisterReq) (*servic
 			return err
 		}
 
-		// 生成令牌
+		// 生成令牌，令牌绑定到新建的会话
+		sessionId := guid.S()
 		tokenInfo, err := libJWT.GetManager().GenerateTokens(
+			sessionId,
 			userInfo.ID, 
 			userInfo.Username, 
 			userInfo.Email,
@@ -127,9 +131,10 @@ func (s *sAuth) Register(ctx context.Context, req *service.RegisterReq) (*servic
 		}
 
 		// 记录会话
-		err = s.createUserSession(ctx, tx, userInfo.ID, tokenInfo.AccessToken)
+		err = s.createUserSession(ctx, tx, sessionId, userInfo.ID, tokenInfo.RefreshTokenID)
 		if err != nil {
-			g.Log().Warning(ctx, "create user session failed:", err)
+			g.Log().Error(ctx, "create user session failed:", err)
+			return errors.New("创建会话失败")
 		}
 
 		result = &service.RegisterRes{
@@ -193,8 +198,10 @@ func (s *sAuth) Login(ctx context.Context, req *service.LoginReq) (*service.Logi
 			return err
 		}
 
-		// 生成令牌
+		// 生成令牌，令牌绑定到新建的会话
+		sessionId := guid.S()
 		tokenInfo, err := libJWT.GetManager().GenerateTokens(
+			sessionId,
 			userInfo.ID, 
 			userInfo.Username, 
 			userInfo.Email,
@@ -207,9 +214,10 @@ func (s *sAuth) Login(ctx context.Context, req *service.LoginReq) (*service.Logi
 		}
 
 		// 记录会话
-		err = s.createUserSession(ctx, tx, userInfo.ID, tokenInfo.AccessToken)
+		err = s.createUserSession(ctx, tx, sessionId, userInfo.ID, tokenInfo.RefreshTokenID)
 		if err != nil {
-			g.Log().Warning(ctx, "create user session failed:", err)
+			g.Log().Error(ctx, "create user session failed:", err)
+			return errors.New("创建会话失败")
 		}
 
 		result = &service.LoginRes{
@@ -237,10 +245,13 @@ func (s *sAuth) Logout(ctx context.Context) error {
 		return nil
 	}
 
-	// 删除用户会话
-	_, err := dao.UserSessions.Ctx(ctx).Where("user_id", userId).Delete()
-	if err != nil {
-		g.Log().Warning(ctx, "delete user session failed:", err)
+	// 撤销当前会话，其他设备上的会话不受影响
+	sessionId := g.RequestFromCtx(ctx).GetCtxVar("session_id").String()
+	if sessionId == "" {
+		return nil
+	}
+	if err := s.RevokeSession(ctx, userId, sessionId); err != nil {
+		g.Log().Warning(ctx, "revoke user session failed:", err)
 	}
 
 	return nil
@@ -270,10 +281,15 @@ func (s *sAuth) GetCurrentUser(ctx context.Context) (*service.AuthUserInfo, erro
 func (s *sAuth) RefreshToken(ctx context.Context, req *service.RefreshTokenReq) (*service.RefreshTokenRes, error) {
 	// 验证刷新令牌并获取用户信息
 	claims, err := libJWT.GetManager().ValidateToken(req.RefreshToken)
-	if err != nil {
+	if err != nil || claims.TokenType != "refresh" {
 		return nil, errors.New("刷新令牌无效")
 	}
 
+	// 会话被撤销后刷新令牌同时失效
+	if err = s.ValidateSession(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	// 获取用户最新的角色和权限
 	roles, err := s.GetUserRoles(ctx, claims.UserID)
 	if err != nil {
@@ -294,6 +310,11 @@ func (s *sAuth) RefreshToken(ctx context.Context, req *service.RefreshTokenReq)
 		return nil, errors.New("刷新令牌失败")
 	}
 
+	// 轮换刷新令牌，旧的刷新令牌随即失效
+	if err = s.rotateRefreshToken(ctx, claims, tokenInfo.RefreshTokenID); err != nil {
+		return nil, err
+	}
+
 	return &service.RefreshTokenRes{
 		TokenInfo: tokenInfo,
 	}, nil
@@ -436,23 +457,147 @@ func (s *sAuth) getUserInfoById(ctx context.Context, tx gdb.TX, userId int64) (*
 	}, nil
 }
 
-// createUserSession 创建用户会话记录
-func (s *sAuth) createUserSession(ctx context.Context, tx gdb.TX, userId int64, accessToken string) error {
+// createUserSession 创建用户会话记录，会话在刷新令牌过期时失效。
+// 会话即刷新令牌的家族，记录当前有效的刷新令牌ID用于轮换和重用检测
+func (s *sAuth) createUserSession(ctx context.Context, tx gdb.TX, sessionId string, userId int64, refreshTokenId string) error {
 	request := g.RequestFromCtx(ctx)
-	sessionId := guid.S()
+	now := gtime.Now()
 
-	// Calculate MD5 hash of access token
-	hashBytes := md5.Sum([]byte(accessToken))
-	tokenHash := fmt.Sprintf("%x", hashBytes)
-	
 	_, err := dao.UserSessions.Ctx(ctx).TX(tx).Data(do.UserSessions{
-		Id:        sessionId,
-		UserId:    userId,
-		IpAddress: request.GetClientIp(),
-		UserAgent: request.Header.Get("User-Agent"),
-		Data:      fmt.Sprintf(`{"access_token_hash": "%s"}`, tokenHash),
-		ExpiresAt: gtime.Now().Add(2 * time.Hour),
+		Id:             sessionId,
+		UserId:         userId,
+		IpAddress:      request.GetClientIp(),
+		UserAgent:      request.Header.Get("User-Agent"),
+		RefreshTokenId: refreshTokenId,
+		ExpiresAt:      now.Add(libJWT.GetManager().RefreshExpire),
+		LastSeenAt:     now,
 	}).Insert()
 
 	return err
-}
\ No newline at end of file
+}
+
+// rotateRefreshToken 将会话当前有效的刷新令牌替换为新签发的刷新令牌，并延长会话的过期时间。
+// 只有会话当前有效的刷新令牌才能完成轮换，已经使用过的刷新令牌再次出现说明令牌可能已泄露，
+// 此时撤销整个会话，攻击者和合法用户持有的令牌同时失效，合法用户需要重新登录
+func (s *sAuth) rotateRefreshToken(ctx context.Context, claims *libJWT.Claims, refreshTokenId string) error {
+	columns := dao.UserSessions.Columns()
+	// 以旧的刷新令牌ID作为条件更新，并发使用同一个刷新令牌时只有一个请求能成功。
+	// 轮换功能上线前创建的会话没有记录刷新令牌ID，首次刷新时直接轮换
+	result, err := dao.UserSessions.Ctx(ctx).Data(do.UserSessions{
+		RefreshTokenId: refreshTokenId,
+		ExpiresAt:      gtime.Now().Add(libJWT.GetManager().RefreshExpire),
+	}).
+		Where(columns.Id, claims.SessionID).
+		WhereNull(columns.RevokedAt).
+		Where(fmt.Sprintf("(%s = ? OR %s IS NULL)", columns.RefreshTokenId, columns.RefreshTokenId), claims.ID).
+		Update()
+	if err != nil {
+		g.Log().Error(ctx, "rotate refresh token failed:", err)
+		return errors.New("刷新令牌失败")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	// 会话在验证之后被撤销，不属于令牌重用
+	revoked, err := dao.UserSessions.Ctx(ctx).Where(columns.Id, claims.SessionID).WhereNotNull(columns.RevokedAt).Count()
+	if err != nil {
+		g.Log().Error(ctx, "find user session failed:", err)
+		return errors.New("刷新令牌失败")
+	}
+	if revoked > 0 {
+		return errors.New("会话已失效，请重新登录")
+	}
+
+	// 刷新令牌已被使用过，撤销整个令牌家族并记录安全事件
+	request := g.RequestFromCtx(ctx)
+	g.Log().Warning(ctx, "security event: refresh token reuse detected, session revoked", g.Map{
+		"user_id":          claims.UserID,
+		"session_id":       claims.SessionID,
+		"refresh_token_id": claims.ID,
+		"ip":               request.GetClientIp(),
+		"user_agent":       request.UserAgent(),
+	})
+	if err = s.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil {
+		g.Log().Error(ctx, "revoke session after refresh token reuse failed:", err)
+	}
+	return errors.New("刷新令牌已被使用，请重新登录")
+}
+
+// ValidateSession 验证令牌所属的会话，会话不存在、已撤销、已过期或不属于令牌的用户时返回错误，验证通过时更新会话的最后活跃时间
+func (s *sAuth) ValidateSession(ctx context.Context, claims *libJWT.Claims) error {
+	// 未绑定会话的令牌（旧版本签发）不再接受
+	if claims.SessionID == "" {
+		return errors.New("会话已失效，请重新登录")
+	}
+
+	var session *entity.UserSessions
+	err := dao.UserSessions.Ctx(ctx).Where(dao.UserSessions.Columns().Id, claims.SessionID).Scan(&session)
+	if err != nil {
+		g.Log().Error(ctx, "find user session failed:", err)
+		return errors.New("会话验证失败")
+	}
+	if session == nil || session.UserId != claims.UserID || !session.RevokedAt.IsZero() {
+		return errors.New("会话已失效，请重新登录")
+	}
+
+	now := gtime.Now()
+	if !session.ExpiresAt.IsZero() && session.ExpiresAt.Before(now) {
+		return errors.New("会话已过期，请重新登录")
+	}
+
+	// 最后活跃时间按间隔更新，避免每个请求都写数据库
+	if session.LastSeenAt.IsZero() || now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		_, err = dao.UserSessions.Ctx(ctx).Data(do.UserSessions{
+			LastSeenAt: now,
+		}).Where(dao.UserSessions.Columns().Id, session.Id).Update()
+		if err != nil {
+			g.Log().Warning(ctx, "update session last seen failed:", err)
+		}
+	}
+	return nil
+}
+
+// RevokeSession 撤销用户的一个会话，会话绑定的令牌立即失效
+func (s *sAuth) RevokeSession(ctx context.Context, userId int64, sessionId string) error {
+	columns := dao.UserSessions.Columns()
+	result, err := dao.UserSessions.Ctx(ctx).Data(do.UserSessions{
+		RevokedAt: gtime.Now(),
+	}).Where(columns.Id, sessionId).Where(columns.UserId, userId).WhereNull(columns.RevokedAt).Update()
+	if err != nil {
+		g.Log().Error(ctx, "revoke user session failed:", err)
+		return errors.New("撤销会话失败")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("会话不存在或已失效")
+	}
+	return nil
+}
+
+// RevokeUserSessions 撤销用户的全部会话，exceptSessionId 不为空时保留该会话，返回撤销的会话数
+func (s *sAuth) RevokeUserSessions(ctx context.Context, userId int64, exceptSessionId string) (int64, error) {
+	columns := dao.UserSessions.Columns()
+	query := dao.UserSessions.Ctx(ctx).Data(do.UserSessions{
+		RevokedAt: gtime.Now(),
+	}).Where(columns.UserId, userId).WhereNull(columns.RevokedAt)
+	if exceptSessionId != "" {
+		query = query.WhereNot(columns.Id, exceptSessionId)
+	}
+
+	result, err := query.Update()
+	if err != nil {
+		g.Log().Error(ctx, "revoke user sessions failed:", err)
+		return 0, errors.New("撤销会话失败")
+	}
+	return result.RowsAffected()
+}
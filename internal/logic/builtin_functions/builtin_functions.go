@@ -2,8 +2,10 @@ package builtin_functions
 
 import (
 	"context"
-	"io/ioutil"
-	"os"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -47,18 +49,18 @@ func (s *sBuiltinFunctions) GetBuiltinFunctions(ctx context.Context) (*model.Bui
 func (s *sBuiltinFunctions) buildFileOperationFunctions() model.BuiltinFunctionCategory {
 	return model.BuiltinFunctionCategory{
 		Name:        "文件操作函数",
-		Description: "文件和目录操作相关函数",
+		Description: "访问当前模板内文件和目录的相关函数",
 		Functions: []model.BuiltinFunction{
 			{
 				Name:        "readFile",
 				DisplayName: "读取文件",
-				Description: "读取指定文件的内容",
+				Description: "读取当前模板中指定文件的原始内容，相对路径以当前文件所在目录为基准，以 / 开头则从模板根目录开始",
 				Params: []model.FunctionParam{
 					{
 						Name:        "filepath",
 						Type:        "string",
 						Required:    true,
-						Description: "文件路径",
+						Description: "文件路径（不能超出模板根目录）",
 					},
 				},
 				ReturnType: "string",
@@ -69,7 +71,7 @@ func (s *sBuiltinFunctions) buildFileOperationFunctions() model.BuiltinFunctionC
 			{
 				Name:        "fileExists",
 				DisplayName: "文件是否存在",
-				Description: "检查指定文件是否存在",
+				Description: "检查当前模板中指定文件是否存在，路径规则同 readFile",
 				Params: []model.FunctionParam{
 					{
 						Name:        "filepath",
@@ -86,7 +88,7 @@ func (s *sBuiltinFunctions) buildFileOperationFunctions() model.BuiltinFunctionC
 			{
 				Name:        "dirExists",
 				DisplayName: "目录是否存在",
-				Description: "检查指定目录是否存在",
+				Description: "检查当前模板中指定目录是否存在，路径规则同 readFile",
 				Params: []model.FunctionParam{
 					{
 						Name:        "dirpath",
@@ -324,14 +326,13 @@ func (s *sBuiltinFunctions) buildProjectStructureFunctions() model.BuiltinFuncti
 			{
 				Name:        "modulePrefix",
 				DisplayName: "模块前缀",
-				Description: "从go.mod文件获取模块前缀",
+				Description: "从当前模板中的go.mod文件获取模块前缀",
 				Params: []model.FunctionParam{
 					{
 						Name:        "goModPath",
 						Type:        "string",
 						Required:    false,
-						Description: "go.mod文件路径，默认为当前目录",
-						Default:     "go.mod",
+						Description: "go.mod文件路径，默认从当前文件所在目录逐级向上查找",
 					},
 				},
 				ReturnType: "string",
@@ -344,23 +345,40 @@ func (s *sBuiltinFunctions) buildProjectStructureFunctions() model.BuiltinFuncti
 }
 
 // GetTemplateFuncMap 获取模板函数映射（供模板渲染使用）
-func (s *sBuiltinFunctions) GetTemplateFuncMap() template.FuncMap {
+// fsys 为当前模板的虚拟文件系统，currentPath 为正在渲染的文件路径，
+// 文件操作函数只能访问 fsys 中的文件，相对路径以 currentPath 所在目录为基准
+func (s *sBuiltinFunctions) GetTemplateFuncMap(fsys fs.FS, currentPath string) template.FuncMap {
 	return template.FuncMap{
 		// 文件操作函数
-		"readFile": func(filepath string) string {
-			content, err := ioutil.ReadFile(filepath)
+		"readFile": func(filepath string) (string, error) {
+			name, err := resolveTemplatePath(fsys, currentPath, filepath)
+			if err != nil {
+				return "", err
+			}
+			content, err := fs.ReadFile(fsys, name)
 			if err != nil {
-				return ""
+				if errors.Is(err, fs.ErrNotExist) {
+					return "", fmt.Errorf("文件 %q 在模板中不存在", filepath)
+				}
+				return "", fmt.Errorf("读取文件 %q 失败: %v", filepath, err)
 			}
-			return string(content)
+			return string(content), nil
 		},
-		"fileExists": func(filepath string) bool {
-			_, err := os.Stat(filepath)
-			return !os.IsNotExist(err)
+		"fileExists": func(filepath string) (bool, error) {
+			name, err := resolveTemplatePath(fsys, currentPath, filepath)
+			if err != nil {
+				return false, err
+			}
+			info, err := fs.Stat(fsys, name)
+			return err == nil && !info.IsDir(), nil
 		},
-		"dirExists": func(dirpath string) bool {
-			info, err := os.Stat(dirpath)
-			return err == nil && info.IsDir()
+		"dirExists": func(dirpath string) (bool, error) {
+			name, err := resolveTemplatePath(fsys, currentPath, dirpath)
+			if err != nil {
+				return false, err
+			}
+			info, err := fs.Stat(fsys, name)
+			return err == nil && info.IsDir(), nil
 		},
 
 		// 字符串处理函数
@@ -506,25 +524,73 @@ func (s *sBuiltinFunctions) GetTemplateFuncMap() template.FuncMap {
 			}
 			return filepath.ToSlash(rel)
 		},
-		"modulePrefix": func(goModPath ...string) string {
-			modPath := "go.mod"
-			if len(goModPath) > 0 && goModPath[0] != "" {
-				modPath = goModPath[0]
+		"modulePrefix": func(goModPath ...string) (string, error) {
+			if fsys == nil {
+				return "", errTemplateFSUnavailable
 			}
-
-			content, err := ioutil.ReadFile(modPath)
-			if err != nil {
-				return ""
+			var content []byte
+			if len(goModPath) > 0 && goModPath[0] != "" {
+				name, err := resolveTemplatePath(fsys, currentPath, goModPath[0])
+				if err != nil {
+					return "", err
+				}
+				if content, err = fs.ReadFile(fsys, name); err != nil {
+					return "", nil
+				}
+			} else {
+				// 未指定路径时从当前文件所在目录逐级向上查找 go.mod
+				for dir := templateDir(currentPath); ; dir = path.Dir(dir) {
+					if data, err := fs.ReadFile(fsys, path.Join(dir, "go.mod")); err == nil {
+						content = data
+						break
+					}
+					if dir == "." {
+						return "", nil
+					}
+				}
 			}
 
 			lines := strings.Split(string(content), "\n")
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
 				if strings.HasPrefix(line, "module ") {
-					return strings.TrimSpace(strings.TrimPrefix(line, "module"))
+					return strings.TrimSpace(strings.TrimPrefix(line, "module")), nil
 				}
 			}
-			return ""
+			return "", nil
 		},
 	}
 }
+
+var errTemplateFSUnavailable = errors.New("文件操作函数仅在渲染模板文件时可用")
+
+// resolveTemplatePath 将模板中引用的路径解析为虚拟文件系统中的路径，
+// 相对路径以当前渲染文件所在目录为基准，以 / 开头的路径以模板根目录为基准，
+// 解析结果超出模板根目录时返回错误
+func resolveTemplatePath(fsys fs.FS, currentPath, name string) (string, error) {
+	if fsys == nil {
+		return "", errTemplateFSUnavailable
+	}
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.TrimSpace(name) == "" {
+		return "", errors.New("文件路径不能为空")
+	}
+	if strings.Contains(name, ":") {
+		return "", fmt.Errorf("路径 %q 超出模板范围，只能访问当前模板内的文件", name)
+	}
+	var joined string
+	if strings.HasPrefix(name, "/") {
+		joined = path.Clean(strings.TrimLeft(name, "/"))
+	} else {
+		joined = path.Join(templateDir(currentPath), name)
+	}
+	if joined == ".." || strings.HasPrefix(joined, "../") || !fs.ValidPath(joined) {
+		return "", fmt.Errorf("路径 %q 超出模板范围，只能访问当前模板内的文件", name)
+	}
+	return joined, nil
+}
+
+// templateDir 返回当前渲染文件在模板中的所在目录
+func templateDir(currentPath string) string {
+	return path.Dir(strings.Trim(strings.ReplaceAll(currentPath, "\\", "/"), "/"))
+}
@@ -15,11 +15,18 @@ import (
 	_ "github.com/ciclebyte/template_starter/internal/logic/statistics"
 	_ "github.com/ciclebyte/template_starter/internal/logic/system_config"
 	_ "github.com/ciclebyte/template_starter/internal/logic/tags"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_bundle"
 	_ "github.com/ciclebyte/template_starter/internal/logic/template_expose"
 	_ "github.com/ciclebyte/template_starter/internal/logic/template_files"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_forks"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_git"
 	_ "github.com/ciclebyte/template_starter/internal/logic/template_languages"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_releases"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_search"
+	_ "github.com/ciclebyte/template_starter/internal/logic/template_tests"
 	_ "github.com/ciclebyte/template_starter/internal/logic/template_variable_presets"
 	_ "github.com/ciclebyte/template_starter/internal/logic/templates"
+	_ "github.com/ciclebyte/template_starter/internal/logic/templatize"
 	_ "github.com/ciclebyte/template_starter/internal/logic/user"
 	_ "github.com/ciclebyte/template_starter/internal/logic/var_preset"
 )
@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/util/gconv"
+
+	"github.com/ciclebyte/template_starter/internal/consts"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/library/libResponse"
+)
+
+// apiKeyScopeCtxKey 请求上下文中API Key访问限制的键
+const apiKeyScopeCtxKey = "api_key_scope"
+
+// apiKeyScope API Key的访问限制，没有权限范围时拥有所属用户的全部权限，没有限定模板时可访问全部模板
+type apiKeyScope struct {
+	permissions map[string]bool
+	templateIds map[int64]bool
+}
+
+// apiKeyRoute API Key访问接口所需的系统权限
+type apiKeyRoute struct {
+	prefix     string // 去掉 /api/v1 后的路由前缀
+	method     string // 为空表示不限请求方法
+	permission string // 为空表示不需要额外的权限
+	template   bool   // 是否访问具体的模板，限定模板的API Key必须能从请求中确定访问的模板
+	global     bool   // 是否列出或创建模板而不针对具体模板，限定模板的API Key不能访问
+
+	// 请求参数 writeFlag 为 true 时接口会修改数据，还需要 writePermission 权限
+	writeFlag       string
+	writePermission string
+}
+
+// apiKeyRoutes 有权限范围的API Key可以访问的接口，按顺序匹配。
+// 未列出的接口（个人资料、API Key管理、AI等）不允许有权限范围的API Key访问
+var apiKeyRoutes = []apiKeyRoute{
+	{prefix: "/auth/me", method: http.MethodGet},
+	{prefix: "/auth/check-permission", method: http.MethodPost},
+	{prefix: "/auth/check-role", method: http.MethodPost},
+	{prefix: "/index", method: http.MethodGet},
+	{prefix: "/builtin-functions", method: http.MethodGet},
+	{prefix: "/sprig-functions", method: http.MethodGet},
+	{prefix: "/systemConfig/public", method: http.MethodGet},
+	{prefix: "/templates/types", method: http.MethodGet},
+
+	// 模板
+	{prefix: "/templates/search/replace", method: http.MethodPost, permission: "template:edit", template: true},
+	{prefix: "/templates/search", method: http.MethodPost, permission: "template:edit", template: true},
+	{prefix: "/templates/add", permission: "template:create", global: true},
+	{prefix: "/templates/import", permission: "template:create", global: true},
+	{prefix: "/templates/list", method: http.MethodGet, permission: "template:read", global: true},
+	{prefix: "/templates/trash", method: http.MethodGet, permission: "template:read", global: true},
+	{prefix: "/templates/fork", permission: "template:create", template: true},
+	{prefix: "/templates/del", permission: "template:delete", template: true},
+	{prefix: "/templates/batchdel", permission: "template:delete", template: true},
+	{prefix: "/templates/purge", permission: "template:delete", template: true},
+	{prefix: "/templates/restore", permission: "template:delete", template: true},
+	{prefix: "/templates/{templateId}/tests/run", method: http.MethodPost, permission: "template:use", template: true, writeFlag: "accept", writePermission: "template:edit"},
+	{prefix: "/templates/{templateId}/analyze-variables", method: http.MethodPost, permission: "template:read", template: true},
+	{prefix: "/templates/{templateId}/templatize/preview", method: http.MethodPost, permission: "template:read", template: true},
+	{prefix: "/templates", method: http.MethodGet, permission: "template:read", template: true},
+	{prefix: "/templates", permission: "template:edit", template: true},
+
+	// 模板文件和模板语言
+	{prefix: "/templateFiles/render", method: http.MethodPost, permission: "template:use", template: true},
+	{prefix: "/templateFiles/renderFileTree", method: http.MethodPost, permission: "template:use", template: true},
+	{prefix: "/templateFiles/downloadZip", method: http.MethodPost, permission: "template:use", template: true},
+	{prefix: "/templateFiles", method: http.MethodGet, permission: "template:read", template: true},
+	{prefix: "/templateFiles", permission: "template:edit", template: true},
+	{prefix: "/templateLanguages", method: http.MethodGet, permission: "template:read", template: true},
+	{prefix: "/templateLanguages", permission: "template:edit", template: true},
+
+	// 分类、语言、标签和变量预设
+	{prefix: "/categories", method: http.MethodGet, permission: "category:read"},
+	{prefix: "/categories", permission: "category:manage"},
+	{prefix: "/languages", method: http.MethodGet, permission: "language:read"},
+	{prefix: "/languages", permission: "language:manage"},
+	{prefix: "/tags/{tagId}/templates", method: http.MethodGet, permission: "template:read", global: true},
+	{prefix: "/tags", method: http.MethodGet, permission: "tag:read"},
+	{prefix: "/tags", permission: "tag:manage"},
+	{prefix: "/var-preset", method: http.MethodGet, permission: "template:read"},
+	{prefix: "/var-preset", permission: "template:manage"},
+
+	// 用户、角色和权限
+	{prefix: "/users", method: http.MethodGet, permission: "user:read"},
+	{prefix: "/users", permission: "user:manage"},
+	{prefix: "/roles", method: http.MethodGet, permission: "role:read"},
+	{prefix: "/roles", permission: "role:manage"},
+	{prefix: "/permissions", method: http.MethodGet, permission: "role:read"},
+	{prefix: "/permissions", permission: "role:manage"},
+
+	// 系统
+	{prefix: "/systemConfig", permission: "system:config"},
+	{prefix: "/statistics", method: http.MethodGet, permission: "system:analytics"},
+}
+
+// newApiKeyScope 解析API Key的权限范围和限定的模板
+func newApiKeyScope(apiKey *entity.ApiKeys) *apiKeyScope {
+	scope := &apiKeyScope{}
+	if apiKey.Permissions != "" {
+		var scopes []string
+		gconv.Scan(apiKey.Permissions, &scopes)
+		if len(scopes) > 0 {
+			scope.permissions = consts.ApiKeyScopesPermissions(scopes)
+		}
+	}
+	if apiKey.TemplateIds != "" {
+		var templateIds []int64
+		gconv.Scan(apiKey.TemplateIds, &templateIds)
+		if len(templateIds) > 0 {
+			scope.templateIds = make(map[int64]bool, len(templateIds))
+			for _, id := range templateIds {
+				scope.templateIds[id] = true
+			}
+		}
+	}
+	return scope
+}
+
+// apiKeyScopeFromRequest 获取当前请求使用的API Key的访问限制，不是API Key认证的请求返回nil
+func apiKeyScopeFromRequest(r *ghttp.Request) *apiKeyScope {
+	scope, _ := r.GetCtxVar(apiKeyScopeCtxKey).Val().(*apiKeyScope)
+	return scope
+}
+
+// allowsPermission 权限范围是否包含该系统权限，不是API Key认证的请求不受限制
+func (s *apiKeyScope) allowsPermission(permission string) bool {
+	return s == nil || s.permissions == nil || s.permissions[permission]
+}
+
+// checkTemplates 检查请求访问的模板是否都在限定的模板中
+func (s *apiKeyScope) checkTemplates(templateIds []int64) error {
+	if s == nil || s.templateIds == nil {
+		return nil
+	}
+	if len(templateIds) == 0 {
+		return fmt.Errorf("该API Key限定了可访问的模板，请求需要指定模板")
+	}
+	for _, id := range templateIds {
+		if !s.templateIds[id] {
+			return fmt.Errorf("该API Key无权访问模板 %d", id)
+		}
+	}
+	return nil
+}
+
+// ApiKeyTemplateIds 当前请求使用的API Key限定的模板，不是API Key认证或没有限定模板时 limited 为false
+func (s *sMiddleware) ApiKeyTemplateIds(ctx context.Context) (templateIds []int64, limited bool) {
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return nil, false
+	}
+	scope := apiKeyScopeFromRequest(r)
+	if scope == nil || scope.templateIds == nil {
+		return nil, false
+	}
+	templateIds = make([]int64, 0, len(scope.templateIds))
+	for id := range scope.templateIds {
+		templateIds = append(templateIds, id)
+	}
+	return templateIds, true
+}
+
+// CheckApiKeyTemplates 检查当前请求使用的API Key能否访问这些模板，不是API Key认证的请求不受限制
+func (s *sMiddleware) CheckApiKeyTemplates(ctx context.Context, templateIds []int64) error {
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return nil
+	}
+	return apiKeyScopeFromRequest(r).checkTemplates(templateIds)
+}
+
+// checkRoute 检查API Key能否访问当前接口
+func (s *apiKeyScope) checkRoute(r *ghttp.Request) error {
+	if s.permissions == nil && s.templateIds == nil {
+		return nil
+	}
+	uri := ""
+	if r.Router != nil {
+		uri = strings.TrimPrefix(r.Router.Uri, "/api/v1")
+	}
+	route := matchApiKeyRoute(r.Method, uri)
+	if route == nil {
+		if s.permissions != nil {
+			return fmt.Errorf("该API Key无权访问此接口")
+		}
+		return nil
+	}
+	if route.permission != "" && !s.allowsPermission(route.permission) {
+		return fmt.Errorf("该API Key缺少权限 %s", route.permission)
+	}
+	if route.writeFlag != "" && r.Get(route.writeFlag).Bool() && !s.allowsPermission(route.writePermission) {
+		return fmt.Errorf("该API Key缺少权限 %s，不能使用 %s 参数", route.writePermission, route.writeFlag)
+	}
+	if route.global && s.templateIds != nil {
+		return fmt.Errorf("该API Key限定了可访问的模板，不能访问此接口")
+	}
+	if route.template && s.templateIds != nil {
+		templateIds, err := requestTemplateIds(r.Context(), r, uri)
+		if err != nil {
+			return err
+		}
+		return s.checkTemplates(templateIds)
+	}
+	return nil
+}
+
+// matchApiKeyRoute 查找接口对应的规则
+func matchApiKeyRoute(method, uri string) *apiKeyRoute {
+	for i := range apiKeyRoutes {
+		route := &apiKeyRoutes[i]
+		if route.method != "" && !strings.EqualFold(route.method, method) {
+			continue
+		}
+		if matchRoutePrefix(uri, route.prefix) {
+			return route
+		}
+	}
+	return nil
+}
+
+// matchRoutePrefix 路由是否以该前缀开头，按路径分段匹配
+func matchRoutePrefix(uri, prefix string) bool {
+	return uri == prefix || strings.HasPrefix(uri, prefix+"/")
+}
+
+// requestTemplateIds 获取请求访问的模板ID，模板文件和模板语言接口通过文件ID和关联ID查找所属模板
+func requestTemplateIds(ctx context.Context, r *ghttp.Request, uri string) ([]int64, error) {
+	var templateIds []int64
+	add := func(values ...*gvar.Var) {
+		for _, value := range values {
+			for _, id := range value.Int64s() {
+				if id > 0 {
+					templateIds = append(templateIds, id)
+				}
+			}
+		}
+	}
+	add(r.Get("templateId"), r.Get("sourceId"))
+
+	switch {
+	case strings.HasPrefix(uri, "/templates/"):
+		add(r.Get("id"), r.Get("ids"))
+	case strings.HasPrefix(uri, "/templateFiles/"):
+		fileIds := append(r.Get("id").Int64s(), r.Get("fileId").Int64s()...)
+		if len(fileIds) == 0 {
+			break
+		}
+		values, err := dao.TemplateFiles.Ctx(ctx).WhereIn(dao.TemplateFiles.Columns().Id, fileIds).Array(dao.TemplateFiles.Columns().TemplateId)
+		if err != nil {
+			return nil, err
+		}
+		// 已删除的文件只保留在修订历史中
+		revisions, err := dao.TemplateFileRevisions.Ctx(ctx).WhereIn(dao.TemplateFileRevisions.Columns().FileId, fileIds).Distinct().Array(dao.TemplateFileRevisions.Columns().TemplateId)
+		if err != nil {
+			return nil, err
+		}
+		add(append(values, revisions...)...)
+	case strings.HasPrefix(uri, "/templateLanguages/"):
+		ids := r.Get("id").Int64s()
+		if len(ids) == 0 {
+			break
+		}
+		values, err := dao.TemplateLanguages.Ctx(ctx).WhereIn(dao.TemplateLanguages.Columns().Id, ids).Array(dao.TemplateLanguages.Columns().TemplateId)
+		if err != nil {
+			return nil, err
+		}
+		add(values...)
+	}
+	return templateIds, nil
+}
+
+// denyApiKey 以403拒绝API Key的请求
+func denyApiKey(r *ghttp.Request, msg string) {
+	r.Response.WriteHeader(http.StatusForbidden)
+	libResponse.JsonExit(r, 403, msg)
+}
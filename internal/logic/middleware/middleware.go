@@ -1,6 +1,13 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ciclebyte/template_starter/internal/model/entity"
 	"github.com/ciclebyte/template_starter/internal/service"
 	"github.com/ciclebyte/template_starter/library/libJWT"
 	"github.com/ciclebyte/template_starter/library/libResponse"
@@ -24,9 +31,27 @@ func (s *sMiddleware) MiddlewareCORS(r *ghttp.Request) {
 	r.Middleware.Next()
 }
 
-// RequireAuth 认证中间件 - 验证JWT令牌
+// RequireAuth 认证中间件 - 验证JWT令牌或API Key
 func (s *sMiddleware) RequireAuth(r *ghttp.Request) {
 	ctx := r.Context()
+
+	// 外层的OptionalAuth已通过API Key或令牌认证时不再重复验证和记录日志
+	if apiKeyScopeFromRequest(r) != nil || r.GetCtxVar("user_info").Val() != nil {
+		r.Middleware.Next()
+		return
+	}
+
+	// 优先使用API Key认证
+	if keyId, keySecret, ok := extractApiKey(r); ok {
+		apiKey, err := s.authenticateApiKey(r, keyId, keySecret)
+		if err != nil {
+			g.Log().Warning(ctx, "api key validation failed:", err)
+			libResponse.JsonExit(r, 401, err.Error())
+			return
+		}
+		s.serveWithApiKey(r, apiKey)
+		return
+	}
 	
 	// 获取Authorization头
 	authHeader := r.Header.Get("Authorization")
@@ -44,18 +69,30 @@ func (s *sMiddleware) RequireAuth(r *ghttp.Request) {
 
 	// 验证Token
 	claims, err := libJWT.GetManager().ValidateToken(token)
+	if err == nil && claims.TokenType != "access" {
+		// 刷新令牌只能用于换取新的令牌
+		err = libJWT.ErrTokenInvalid
+	}
 	if err != nil {
 		g.Log().Warning(ctx, "token validation failed:", err)
 		libResponse.JsonExit(r, 401, "认证令牌无效或已过期")
 		return
 	}
 
+	// 验证令牌所属的会话，会话被撤销后令牌立即失效
+	if err = service.Auth().ValidateSession(ctx, claims); err != nil {
+		g.Log().Warning(ctx, "session validation failed:", err)
+		libResponse.JsonExit(r, 401, err.Error())
+		return
+	}
+
 	// 添加调试日志
 	g.Log().Debug(ctx, "JWT claims UserID:", claims.UserID, "Username:", claims.Username)
 
 	// 将用户信息存储到请求上下文
 	r.SetCtxVar("user_id", claims.UserID)
 	r.SetCtxVar("username", claims.Username)
+	r.SetCtxVar("session_id", claims.SessionID)
 	
 	// 检查用户状态
 	userInfo, err := service.Auth().GetCurrentUser(r.Context())
@@ -78,15 +115,27 @@ func (s *sMiddleware) RequireAuth(r *ghttp.Request) {
 
 // OptionalAuth 可选认证中间件 - 支持匿名访问
 func (s *sMiddleware) OptionalAuth(r *ghttp.Request) {
+	if keyId, keySecret, ok := extractApiKey(r); ok {
+		apiKey, err := s.authenticateApiKey(r, keyId, keySecret)
+		if err == nil {
+			s.serveWithApiKey(r, apiKey)
+			return
+		}
+		g.Log().Warning(r.Context(), "api key validation failed:", err)
+		r.Middleware.Next()
+		return
+	}
+
 	authHeader := r.Header.Get("Authorization")
 	if authHeader != "" {
 		token := libJWT.ExtractTokenFromHeader(authHeader)
 		if token != "" {
 			claims, err := libJWT.GetManager().ValidateToken(token)
-			if err == nil {
+			if err == nil && claims.TokenType == "access" && service.Auth().ValidateSession(r.Context(), claims) == nil {
 				// 设置用户ID到上下文
 				r.SetCtxVar("user_id", claims.UserID)
 				r.SetCtxVar("username", claims.Username)
+				r.SetCtxVar("session_id", claims.SessionID)
 				
 				// 验证成功，设置用户信息
 				userInfo, err := service.Auth().GetCurrentUser(r.Context())
@@ -118,6 +167,12 @@ func (s *sMiddleware) RequirePermission(permission string) ghttp.HandlerFunc {
 			return
 		}
 
+		// API Key的权限范围需要包含该权限
+		if !apiKeyScopeFromRequest(r).allowsPermission(permission) {
+			denyApiKey(r, "该API Key缺少权限 "+permission)
+			return
+		}
+
 		// 检查权限
 		hasPermission, err := service.Auth().HasPermission(ctx, userId, permission)
 		if err != nil {
@@ -215,6 +270,19 @@ func (s *sMiddleware) RequireTemplateOwnerOrPermission(permission string) ghttp.
 			}
 		}
 
+		// API Key的权限范围需要包含该权限，且限定的模板需要包含该模板
+		scope := apiKeyScopeFromRequest(r)
+		if !scope.allowsPermission(permission) {
+			denyApiKey(r, "该API Key缺少权限 "+permission)
+			return
+		}
+		if templateId > 0 {
+			if err := scope.checkTemplates([]int64{templateId}); err != nil {
+				denyApiKey(r, err.Error())
+				return
+			}
+		}
+
 		// 否则检查权限
 		hasPermission, err := service.Auth().HasPermission(ctx, userId, permission)
 		if err != nil {
@@ -236,3 +304,87 @@ func (s *sMiddleware) RequireTemplateOwnerOrPermission(permission string) ghttp.
 		r.Middleware.Next()
 	}
 }
+
+// apiKeyScheme Authorization头中API Key的认证方案
+const apiKeyScheme = "ApiKey "
+
+// extractApiKey 从请求头中提取API Key，支持 Authorization: ApiKey <keyId>.<secret> 和 X-API-Key: <keyId>.<secret>
+func extractApiKey(r *ghttp.Request) (keyId, keySecret string, ok bool) {
+	value := r.Header.Get("X-API-Key")
+	if value == "" {
+		authHeader := r.Header.Get("Authorization")
+		if len(authHeader) <= len(apiKeyScheme) || !strings.EqualFold(authHeader[:len(apiKeyScheme)], apiKeyScheme) {
+			return "", "", false
+		}
+		value = authHeader[len(apiKeyScheme):]
+	}
+	keyId, keySecret, _ = strings.Cut(strings.TrimSpace(value), ".")
+	return keyId, keySecret, true
+}
+
+// authenticateApiKey 验证API Key的状态、有效期和Secret，并将所属用户信息存储到请求上下文
+func (s *sMiddleware) authenticateApiKey(r *ghttp.Request, keyId, keySecret string) (*entity.ApiKeys, error) {
+	ctx := r.Context()
+	apiKey, err := service.ApiKey().VerifyApiKey(ctx, keyId, keySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	r.SetCtxVar("user_id", int64(apiKey.UserId))
+	userInfo, err := service.Auth().GetCurrentUser(ctx)
+	if err != nil {
+		r.SetCtxVar("user_id", nil)
+		g.Log().Warning(ctx, "get api key owner failed:", err)
+		return nil, errors.New("API Key所属用户不存在")
+	}
+	if userInfo.Status != 1 {
+		r.SetCtxVar("user_id", nil)
+		return nil, errors.New("API Key所属用户账户已被禁用")
+	}
+
+	r.SetCtxVar("username", userInfo.Username)
+	r.SetCtxVar("user_info", userInfo)
+	r.SetCtxVar("api_key_id", int64(apiKey.Id))
+	r.SetCtxVar(apiKeyScopeCtxKey, newApiKeyScope(apiKey))
+	return apiKey, nil
+}
+
+// serveWithApiKey 以API Key所属用户的身份继续处理请求，请求结束后更新API Key使用信息并记录一条使用日志
+func (s *sMiddleware) serveWithApiKey(r *ghttp.Request, apiKey *entity.ApiKeys) {
+	defer func() {
+		// 请求结束后客户端可能已断开，日志写入不随请求取消
+		ctx := context.WithoutCancel(r.Context())
+		ip := r.GetClientIp()
+
+		// 处理函数返回错误时HTTP状态码仍为200，按服务端错误记录
+		statusCode := r.Response.Status
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		if statusCode == http.StatusOK && r.GetError() != nil {
+			statusCode = http.StatusInternalServerError
+		}
+		responseTime := int(time.Since(r.EnterTime.Time) / time.Millisecond)
+
+		if err := service.ApiKey().UpdateApiKeyUsage(ctx, int64(apiKey.Id), ip); err != nil {
+			g.Log().Warning(ctx, "update api key usage failed:", err)
+		}
+		err := service.ApiKey().LogApiKeyUsage(ctx, int64(apiKey.Id), int64(apiKey.UserId), r.Method, r.URL.Path, ip, r.UserAgent(), statusCode, responseTime)
+		if err != nil {
+			g.Log().Warning(ctx, "log api key usage failed:", err)
+		}
+	}()
+
+	// 检查API Key的权限范围和限定的模板
+	if err := apiKeyScopeFromRequest(r).checkRoute(r); err != nil {
+		g.Log().Warning(r.Context(), "api key scope denied:", g.Map{
+			"api_key_id": apiKey.Id,
+			"path":       r.URL.Path,
+			"reason":     err.Error(),
+		})
+		denyApiKey(r, err.Error())
+		return
+	}
+
+	r.Middleware.Next()
+}
@@ -0,0 +1,314 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/util/gconv"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/libResponse"
+)
+
+const (
+	// rateLimitConfigKey 系统配置中限流策略的键
+	rateLimitConfigKey = "system.rate_limit"
+	// rateLimitConfigTTL 限流策略的缓存时长，修改系统配置后最多经过该时长生效
+	rateLimitConfigTTL = 30 * time.Second
+	// rateLimitSweepInterval 清理空闲令牌桶的间隔
+	rateLimitSweepInterval = time.Minute
+)
+
+// defaultRateLimitConfig 系统配置中没有限流策略时使用的默认策略
+var defaultRateLimitConfig = &model.RateLimitConfig{
+	Enabled: true,
+	Policies: []*model.RateLimitPolicy{
+		{Name: "auth", Routes: []string{"/auth/login", "/auth/register", "/auth/refresh"}, Limit: 10, Window: 60, Burst: 5},
+		{Name: "ai", Routes: []string{"/ai", "/v1/chat/completions"}, Limit: 20, Window: 60, Burst: 5},
+		{Name: "render", Routes: []string{
+			"/templateFiles/render",
+			"/templateFiles/renderFileTree",
+			"/templateFiles/downloadZip",
+			"/templates/{templateId}/export",
+			"/templates/{templateId}/tests/run",
+		}, Limit: 60, Window: 60, Burst: 20},
+	},
+}
+
+// tokenBucket 令牌桶，令牌按固定速率补充，不超过容量
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // 每秒补充的令牌数
+	updated  time.Time
+}
+
+// rateLimitResult 一次取令牌的结果
+type rateLimitResult struct {
+	allowed    bool
+	limit      int
+	remaining  int
+	retryAfter time.Duration // 被拒绝时距离补充出一个令牌的时长
+	resetAfter time.Duration // 距离令牌桶补满的时长
+}
+
+// rateLimiter 内存中的令牌桶集合，按策略和访问身份区分
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+
+	configMu       sync.Mutex
+	config         *model.RateLimitConfig
+	trustedProxies []*net.IPNet
+	configExpires  time.Time
+}
+
+var limiter = &rateLimiter{buckets: make(map[string]*tokenBucket)}
+
+// RateLimit 限流中间件，需要在认证中间件之后使用，按用户、API Key 或匿名访问的客户端IP分别计数
+func (s *sMiddleware) RateLimit(r *ghttp.Request) {
+	ctx := r.Context()
+	config, trustedProxies := limiter.loadConfig(ctx)
+	if !config.Enabled || r.Router == nil {
+		r.Middleware.Next()
+		return
+	}
+	policy := matchRateLimitPolicy(config, strings.TrimPrefix(r.Router.Uri, "/api/v1"))
+	if policy == nil {
+		r.Middleware.Next()
+		return
+	}
+
+	clientIp := rateLimitClientIp(r, trustedProxies)
+	result := limiter.take(policy, policy.Name+"|"+rateLimitIdentity(r, clientIp), time.Now())
+	header := r.Response.Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(result.limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(result.remaining))
+	header.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.resetAfter)))
+	if !result.allowed {
+		retryAfter := ceilSeconds(result.retryAfter)
+		header.Set("Retry-After", strconv.Itoa(retryAfter))
+		g.Log().Warning(ctx, "rate limit exceeded", g.Map{
+			"policy": policy.Name,
+			"path":   r.URL.Path,
+			"ip":     clientIp,
+		})
+		r.Response.WriteHeader(http.StatusTooManyRequests)
+		libResponse.JsonExit(r, http.StatusTooManyRequests, fmt.Sprintf("请求过于频繁，请在 %d 秒后重试", retryAfter))
+		return
+	}
+	r.Middleware.Next()
+}
+
+// rateLimitIdentity 限流的访问身份，API Key 认证的请求按 API Key 计数，登录用户按用户计数，匿名访问按客户端IP计数
+func rateLimitIdentity(r *ghttp.Request, clientIp string) string {
+	if apiKeyId := r.GetCtxVar("api_key_id").Int64(); apiKeyId > 0 {
+		return "key:" + strconv.FormatInt(apiKeyId, 10)
+	}
+	if userId := gconv.Int64(r.GetCtxVar("user_id").Val()); userId > 0 {
+		return "user:" + strconv.FormatInt(userId, 10)
+	}
+	return "ip:" + clientIp
+}
+
+// rateLimitClientIp 限流使用的客户端IP。转发头可以由客户端任意伪造，只有直接连接的对端是可信代理时才使用：
+// 从 X-Forwarded-For 的最后一项向前跳过可信代理，第一个不可信的地址即为客户端IP
+func rateLimitClientIp(r *ghttp.Request, trustedProxies []*net.IPNet) string {
+	remoteIp := r.GetRemoteIp()
+	if !isTrustedProxy(remoteIp, trustedProxies) {
+		return remoteIp
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip == "" {
+			continue
+		}
+		if net.ParseIP(ip) == nil {
+			// 无法解析的地址之前的内容都不可信
+			return remoteIp
+		}
+		if !isTrustedProxy(ip, trustedProxies) || i == 0 {
+			return ip
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return remoteIp
+}
+
+// isTrustedProxy IP是否在可信代理列表中
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRateLimitPolicy 查找接口使用的限流策略
+func matchRateLimitPolicy(config *model.RateLimitConfig, uri string) *model.RateLimitPolicy {
+	for _, policy := range config.Policies {
+		for _, prefix := range policy.Routes {
+			if matchRoutePrefix(uri, prefix) {
+				return policy
+			}
+		}
+	}
+	return nil
+}
+
+// take 从令牌桶中取一个令牌，策略修改后令牌桶按新的容量和速率继续计算
+func (l *rateLimiter) take(policy *model.RateLimitPolicy, key string, now time.Time) rateLimitResult {
+	capacity := float64(policy.Burst)
+	if capacity <= 0 {
+		capacity = float64(policy.Limit)
+	}
+	rate := float64(policy.Limit) / float64(policy.Window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.capacity, bucket.rate = capacity, rate
+	bucket.refill(now)
+
+	result := rateLimitResult{limit: int(capacity)}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.allowed = true
+	} else {
+		result.retryAfter = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	result.remaining = int(math.Floor(bucket.tokens))
+	result.resetAfter = time.Duration((capacity - bucket.tokens) / rate * float64(time.Second))
+	return result
+}
+
+// sweep 定期移除已补满的令牌桶，补满的令牌桶与新建的令牌桶等价
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+	}
+	b.updated = now
+}
+
+// loadConfig 读取并缓存系统配置中的限流策略和可信代理，配置不存在或无效时使用默认策略
+func (l *rateLimiter) loadConfig(ctx context.Context) (*model.RateLimitConfig, []*net.IPNet) {
+	l.configMu.Lock()
+	defer l.configMu.Unlock()
+	if l.config != nil && time.Now().Before(l.configExpires) {
+		return l.config, l.trustedProxies
+	}
+
+	config := defaultRateLimitConfig
+	value, err := service.SystemConfig().GetConfigString(ctx, rateLimitConfigKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		g.Log().Warning(ctx, "load rate limit config failed:", err)
+	} else if value != "" {
+		if parsed, err := parseRateLimitConfig(value); err != nil {
+			g.Log().Warning(ctx, "invalid rate limit config:", err)
+		} else {
+			config = parsed
+		}
+	}
+	// 可信代理已在解析配置时校验，默认策略没有可信代理
+	trustedProxies, _ := parseTrustedProxies(config.TrustedProxies)
+	l.config = config
+	l.trustedProxies = trustedProxies
+	l.configExpires = time.Now().Add(rateLimitConfigTTL)
+	return config, trustedProxies
+}
+
+// parseRateLimitConfig 解析并校验限流策略
+func parseRateLimitConfig(value string) (*model.RateLimitConfig, error) {
+	var config model.RateLimitConfig
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return nil, err
+	}
+	for _, policy := range config.Policies {
+		if policy == nil || policy.Name == "" {
+			return nil, fmt.Errorf("限流策略缺少名称")
+		}
+		if policy.Limit <= 0 || policy.Window <= 0 || policy.Burst < 0 {
+			return nil, fmt.Errorf("限流策略 %s 的 limit 和 window 必须大于0，burst 不能小于0", policy.Name)
+		}
+	}
+	if _, err := parseTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// parseTrustedProxies 解析可信代理列表，支持单个IP和CIDR
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("可信代理 %q 不是有效的IP或CIDR", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("可信代理 %q 不是有效的IP或CIDR", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// ceilSeconds 向上取整的秒数
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -324,6 +324,90 @@ func (s *sProfile) GetLoginHistory(ctx context.Context, req *profile.GetLoginHis
 	}, nil
 }
 
+// ============================================================================
+// 登录会话
+// ============================================================================
+
+// ListSessions 获取登录会话列表，只返回未撤销且未过期的会话
+func (s *sProfile) ListSessions(ctx context.Context, req *profile.ListSessionsReq) (*profile.ListSessionsRes, error) {
+	// 获取当前用户ID
+	request := g.RequestFromCtx(ctx)
+	userIdVar := request.GetCtxVar("user_id")
+	if userIdVar == nil {
+		return nil, errors.New("未登录")
+	}
+	userId := gconv.Int64(userIdVar)
+	currentSessionId := request.GetCtxVar("session_id").String()
+
+	columns := dao.UserSessions.Columns()
+	var sessions []entity.UserSessions
+	err := dao.UserSessions.Ctx(ctx).
+		Where(columns.UserId, userId).
+		WhereNull(columns.RevokedAt).
+		WhereGT(columns.ExpiresAt, gtime.Now()).
+		OrderDesc(columns.LastSeenAt).
+		OrderDesc(columns.CreatedAt).
+		Scan(&sessions)
+	if err != nil {
+		g.Log().Error(ctx, "get user sessions failed:", err)
+		return nil, err
+	}
+
+	list := make([]profile.Session, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, profile.Session{
+			Id:         session.Id,
+			IpAddress:  session.IpAddress,
+			UserAgent:  session.UserAgent,
+			Current:    session.Id == currentSessionId,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+		})
+	}
+
+	return &profile.ListSessionsRes{List: list}, nil
+}
+
+// RevokeSession 撤销登录会话，撤销当前会话相当于退出登录
+func (s *sProfile) RevokeSession(ctx context.Context, req *profile.RevokeSessionReq) (*profile.RevokeSessionRes, error) {
+	// 获取当前用户ID
+	userIdVar := g.RequestFromCtx(ctx).GetCtxVar("user_id")
+	if userIdVar == nil {
+		return nil, errors.New("未登录")
+	}
+	userId := gconv.Int64(userIdVar)
+
+	if err := service.Auth().RevokeSession(ctx, userId, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &profile.RevokeSessionRes{}, nil
+}
+
+// RevokeAllSessions 撤销全部登录会话，可保留当前会话以便在其他设备上退出登录
+func (s *sProfile) RevokeAllSessions(ctx context.Context, req *profile.RevokeAllSessionsReq) (*profile.RevokeAllSessionsRes, error) {
+	// 获取当前用户ID
+	request := g.RequestFromCtx(ctx)
+	userIdVar := request.GetCtxVar("user_id")
+	if userIdVar == nil {
+		return nil, errors.New("未登录")
+	}
+	userId := gconv.Int64(userIdVar)
+
+	exceptSessionId := ""
+	if req.KeepCurrent {
+		exceptSessionId = request.GetCtxVar("session_id").String()
+	}
+
+	revoked, err := service.Auth().RevokeUserSessions(ctx, userId, exceptSessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile.RevokeAllSessionsRes{Revoked: revoked}, nil
+}
+
 // ============================================================================
 // 辅助方法
 // ============================================================================
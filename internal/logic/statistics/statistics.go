@@ -86,7 +86,7 @@ func (s *sStatistics) GetCategoryDistribution(ctx context.Context, req *statisti
 
 	var categoryCountData []CategoryCount
 	err := dao.Categories.Ctx(ctx).
-		LeftJoin("templates t", "categories.id = t.category_id").
+		LeftJoin("templates t", "categories.id = t.category_id AND t.deleted_at IS NULL").
 		Fields("categories.name as category_name, COUNT(t.id) as template_count").
 		Group("categories.id, categories.name").
 		Order("template_count DESC").
@@ -162,9 +162,10 @@ func (s sTags) WithCount(ctx context.Context) (res []*model.TagWithTemplateCount
 		sql := `
 			SELECT 
 				t.id, t.name, t.description, t.sort, t.created_at, t.updated_at, t.deleted_at,
-				COALESCE(COUNT(tt.template_id), 0) as template_count
+				COALESCE(COUNT(tpl.id), 0) as template_count
 			FROM tags t
 			LEFT JOIN template_tags tt ON t.id = tt.tag_id
+			LEFT JOIN templates tpl ON tpl.id = tt.template_id AND tpl.deleted_at IS NULL
 			WHERE t.deleted_at IS NULL
 			GROUP BY t.id, t.name, t.description, t.sort, t.created_at, t.updated_at, t.deleted_at
 			ORDER BY t.sort DESC, t.created_at DESC
@@ -290,7 +291,7 @@ func (s sTags) GetTemplatesByTag(ctx context.Context, tagId int64, req *api.Temp
 			SELECT t.id, t.name, t.description, t.introduction, t.category_id, t.is_featured, t.logo, t.created_at, t.updated_at
 			FROM templates t
 			INNER JOIN template_tags tt ON t.id = tt.template_id
-			WHERE tt.tag_id = ?
+			WHERE tt.tag_id = ? AND t.deleted_at IS NULL
 		`
 		
 		// 统计总数
@@ -298,7 +299,7 @@ func (s sTags) GetTemplatesByTag(ctx context.Context, tagId int64, req *api.Temp
 			SELECT COUNT(*)
 			FROM templates t
 			INNER JOIN template_tags tt ON t.id = tt.template_id
-			WHERE tt.tag_id = ?
+			WHERE tt.tag_id = ? AND t.deleted_at IS NULL
 		`
 		
 		total, err = dao.Templates.DB().Raw(countSql, tagId).Value()
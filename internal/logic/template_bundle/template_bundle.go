@@ -0,0 +1,682 @@
+package template_bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_bundle"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+const (
+	// defaultBundleMaxSize 模板包默认的大小上限，可通过 server.bundleMaxSize 配置
+	defaultBundleMaxSize = 20 * 1024 * 1024
+	// defaultBundleMaxUncompressedSize 模板包解压后所有文件的默认总大小上限，可通过 server.bundleMaxUncompressedSize 配置
+	defaultBundleMaxUncompressedSize = 200 * 1024 * 1024
+)
+
+type sTemplateBundle struct{}
+
+func init() {
+	service.RegisterTemplateBundle(New())
+}
+
+func New() *sTemplateBundle {
+	return &sTemplateBundle{}
+}
+
+// Export 导出模板包：清单加未渲染的模板文件，直接写入响应
+func (s *sTemplateBundle) Export(ctx context.Context, templateId int64) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		data, manifest, err := s.build(ctx, templateId)
+		liberr.ErrIsNil(ctx, err)
+
+		response := g.RequestFromCtx(ctx).Response
+		response.Header().Set("Content-Type", "application/zip")
+		response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s%s\"", manifest.Template.Name, model.BundleFileExt))
+		response.Header().Set("Content-Transfer-Encoding", "binary")
+		response.Write(data)
+	})
+	return
+}
+
+// build 生成模板包的内容
+func (s *sTemplateBundle) build(ctx context.Context, templateId int64) ([]byte, *model.TemplateBundleManifest, error) {
+	var template *entity.Templates
+	if err := dao.Templates.Ctx(ctx).WherePri(templateId).Scan(&template); err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板失败")
+	}
+	if template == nil {
+		return nil, nil, gerror.Newf("模板ID %d 不存在", templateId)
+	}
+
+	manifest := &model.TemplateBundleManifest{
+		FormatVersion: model.BundleFormatVersion,
+		ExportedAt:    gtime.Now().Format("c"),
+		Template: &model.TemplateBundleTemplate{
+			Name:         template.Name,
+			Description:  template.Description,
+			Introduction: template.Introduction,
+			TemplateType: template.TemplateType,
+			TypeConfig:   template.TypeConfig,
+			IsFeatured:   template.IsFeatured,
+			Logo:         template.Logo,
+			Icon:         template.Icon,
+		},
+		Tags:      make([]*model.TemplateBundleTag, 0),
+		Languages: make([]*model.TemplateBundleLanguage, 0),
+		Presets:   make([]*model.TemplateBundlePreset, 0),
+		Files:     make([]*model.TemplateBundleFile, 0),
+	}
+
+	var category *entity.Categories
+	if err := dao.Categories.Ctx(ctx).WherePri(template.CategoryId).Scan(&category); err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板分类失败")
+	}
+	if category != nil {
+		manifest.Category = &model.TemplateBundleCategory{
+			Name:        category.Name,
+			Description: category.Description,
+			Icon:        category.Icon,
+			Sort:        category.Sort,
+		}
+	}
+
+	var tags []*entity.Tags
+	err := dao.Tags.Ctx(ctx).
+		Where("id IN(?)", dao.TemplateTags.Ctx(ctx).Fields(dao.TemplateTags.Columns().TagId).Where(dao.TemplateTags.Columns().TemplateId, templateId)).
+		Where("deleted_at IS NULL").
+		OrderAsc(dao.Tags.Columns().Name).
+		Scan(&tags)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板标签失败")
+	}
+	for _, tag := range tags {
+		manifest.Tags = append(manifest.Tags, &model.TemplateBundleTag{Name: tag.Name, Description: tag.Description})
+	}
+
+	var templateLanguages []*entity.TemplateLanguages
+	if err = dao.TemplateLanguages.Ctx(ctx).Where(dao.TemplateLanguages.Columns().TemplateId, templateId).Scan(&templateLanguages); err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板语言失败")
+	}
+	for _, templateLanguage := range templateLanguages {
+		var language *entity.Languages
+		if err = dao.Languages.Ctx(ctx).WherePri(templateLanguage.LanguageId).Scan(&language); err != nil {
+			return nil, nil, gerror.Wrap(err, "获取模板语言失败")
+		}
+		if language != nil {
+			manifest.Languages = append(manifest.Languages, &model.TemplateBundleLanguage{
+				Code:      language.Code,
+				Name:      language.Name,
+				IsPrimary: templateLanguage.IsPrimary,
+			})
+		}
+	}
+
+	var expose *entity.TemplateExposeFields
+	err = dao.TemplateExposeFields.Ctx(ctx).
+		Where(dao.TemplateExposeFields.Columns().TemplateId, templateId).
+		OrderDesc(dao.TemplateExposeFields.Columns().Id).
+		Limit(1).
+		Scan(&expose)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板暴露字段失败")
+	}
+	if expose != nil {
+		manifest.ExposeFields = &model.TemplateBundleExpose{
+			FieldSchemaJson: expose.FieldSchemaJson,
+			Version:         expose.Version,
+			Description:     stringValue(expose.Description),
+		}
+	}
+
+	var presets []*entity.VarPreset
+	err = dao.VarPreset.Ctx(ctx).
+		Where("id IN(?)", dao.TemplateVariablePresets.Ctx(ctx).Fields("preset_id").Where(dao.TemplateVariablePresets.Columns().TemplateId, templateId)).
+		OrderAsc(dao.VarPreset.Columns().Name).
+		Scan(&presets)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板订阅的预设失败")
+	}
+	for _, preset := range presets {
+		manifest.Presets = append(manifest.Presets, &model.TemplateBundlePreset{
+			Name:            preset.Name,
+			DisplayName:     preset.DisplayName,
+			Description:     stringValue(preset.Description),
+			SchemaJson:      preset.SchemaJson,
+			DefaultDataJson: stringValue(preset.DefaultDataJson),
+			Icon:            stringValue(preset.Icon),
+			Version:         preset.Version,
+		})
+	}
+
+	var files []*entity.TemplateFiles
+	if err = dao.TemplateFiles.Ctx(ctx).Where(dao.TemplateFiles.Columns().TemplateId, templateId).Scan(&files); err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板文件失败")
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return normalizeBundlePath(files[i].FilePath) < normalizeBundlePath(files[j].FilePath)
+	})
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range files {
+		filePath := normalizeBundlePath(file.FilePath)
+		manifest.Files = append(manifest.Files, &model.TemplateBundleFile{
+			Path:              filePath,
+			IsDirectory:       file.IsDirectory,
+			Sort:              file.Sort,
+			Md5:               file.Md5,
+			GenerateCondition: file.GenerateCondition,
+			RepeatConfig:      file.RepeatConfig,
+		})
+		if file.IsDirectory == 1 {
+			continue
+		}
+		entry, err := zipWriter.Create(model.BundleFilesDir + filePath)
+		if err != nil {
+			return nil, nil, gerror.Wrapf(err, "写入文件 %s 失败", filePath)
+		}
+		if _, err = entry.Write([]byte(file.FileContent)); err != nil {
+			return nil, nil, gerror.Wrapf(err, "写入文件 %s 失败", filePath)
+		}
+	}
+
+	manifestJson, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "生成模板包清单失败")
+	}
+	entry, err := zipWriter.Create(model.BundleManifestName)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "写入模板包清单失败")
+	}
+	if _, err = entry.Write(manifestJson); err != nil {
+		return nil, nil, gerror.Wrap(err, "写入模板包清单失败")
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, nil, gerror.Wrap(err, "生成模板包失败")
+	}
+	return buf.Bytes(), manifest, nil
+}
+
+// Import 从上传的模板包导入模板，模板包通过表单字段 bundle 上传
+func (s *sTemplateBundle) Import(ctx context.Context, req *api.TemplateBundleImportReq) (res *api.TemplateBundleImportRes, err error) {
+	res = &api.TemplateBundleImportRes{Warnings: make([]string, 0)}
+	err = g.Try(ctx, func(ctx context.Context) {
+		file := g.RequestFromCtx(ctx).GetUploadFile("bundle")
+		if file == nil {
+			liberr.ErrIsNil(ctx, gerror.New("请上传模板包"))
+		}
+		maxSize := g.Cfg().MustGet(ctx, "server.bundleMaxSize", defaultBundleMaxSize).Int64()
+		if file.Size > maxSize {
+			liberr.ErrIsNil(ctx, gerror.Newf("模板包大小不能超过 %d 字节", maxSize))
+		}
+		src, err := file.Open()
+		liberr.ErrIsNil(ctx, err, "读取模板包失败")
+		defer src.Close()
+		data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+		liberr.ErrIsNil(ctx, err, "读取模板包失败")
+
+		maxUncompressedSize := g.Cfg().MustGet(ctx, "server.bundleMaxUncompressedSize", defaultBundleMaxUncompressedSize).Int64()
+		manifest, contents, err := parseBundle(data, maxUncompressedSize)
+		liberr.ErrIsNil(ctx, err)
+
+		onConflict := req.OnConflict
+		if onConflict == "" {
+			onConflict = model.BundleConflictError
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = manifest.Template.Name
+		}
+
+		var existing *entity.Templates
+		err = dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Name, name).Scan(&existing)
+		liberr.ErrIsNil(ctx, err, "模板名称判重失败")
+		if existing != nil {
+			switch onConflict {
+			case model.BundleConflictRename:
+				name, err = s.availableName(ctx, name)
+				liberr.ErrIsNil(ctx, err, "模板名称判重失败")
+				existing = nil
+			case model.BundleConflictOverwrite:
+			default:
+				liberr.ErrIsNil(ctx, gerror.Newf("模板名称 %s 已存在，可选择重命名(rename)或覆盖(overwrite)导入", name))
+			}
+		}
+
+		err = dao.Templates.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			categoryId, err := s.importCategory(ctx, manifest.Category)
+			if err != nil {
+				return err
+			}
+
+			typeConfig := manifest.Template.TypeConfig
+			if strings.TrimSpace(typeConfig) == "" {
+				typeConfig = "{}"
+			}
+			data := do.Templates{
+				Name:         name,
+				Description:  manifest.Template.Description,
+				Introduction: manifest.Template.Introduction,
+				CategoryId:   categoryId,
+				TemplateType: manifest.Template.TemplateType,
+				TypeConfig:   typeConfig,
+				IsFeatured:   manifest.Template.IsFeatured,
+				Logo:         manifest.Template.Logo,
+				Icon:         manifest.Template.Icon,
+			}
+
+			var templateId int64
+			if existing == nil {
+				if templateId, err = dao.Templates.Ctx(ctx).InsertAndGetId(data); err != nil {
+					return gerror.Wrap(err, "新增模板失败")
+				}
+				res.Created = true
+			} else {
+				templateId = existing.Id
+				if _, err = dao.Templates.Ctx(ctx).WherePri(templateId).Update(data); err != nil {
+					return gerror.Wrap(err, "修改模板失败")
+				}
+				if err = s.clearTemplate(ctx, templateId); err != nil {
+					return err
+				}
+			}
+
+			if err = s.importLanguages(ctx, templateId, manifest.Languages, res); err != nil {
+				return err
+			}
+			if err = s.importTags(ctx, templateId, manifest.Tags); err != nil {
+				return err
+			}
+			if manifest.ExposeFields != nil {
+				_, err = dao.TemplateExposeFields.Ctx(ctx).Insert(do.TemplateExposeFields{
+					TemplateId:      templateId,
+					FieldSchemaJson: manifest.ExposeFields.FieldSchemaJson,
+					Version:         manifest.ExposeFields.Version,
+					Description:     manifest.ExposeFields.Description,
+				})
+				if err != nil {
+					return gerror.Wrap(err, "导入模板暴露字段失败")
+				}
+			}
+			if err = s.importPresets(ctx, templateId, manifest.Presets); err != nil {
+				return err
+			}
+			if res.FileCount, err = s.importFiles(ctx, templateId, manifest.Files, contents); err != nil {
+				return err
+			}
+
+			res.TemplateId = templateId
+			res.Name = name
+			return nil
+		})
+		liberr.ErrIsNil(ctx, err, "导入模板包失败")
+	})
+	return
+}
+
+// parseBundle 解析模板包，返回清单和按路径索引的文件内容，
+// 解压后的总大小超过 maxUncompressedSize 时返回错误，防止压缩率极高的ZIP耗尽内存
+func parseBundle(data []byte, maxUncompressedSize int64) (*model.TemplateBundleManifest, map[string]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "模板包不是有效的ZIP文件")
+	}
+
+	var manifest *model.TemplateBundleManifest
+	contents := make(map[string]string)
+	remaining := maxUncompressedSize
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		switch {
+		case entry.Name == model.BundleManifestName:
+			content, err := readZipEntry(entry, remaining, maxUncompressedSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			remaining -= int64(len(content))
+			if err = json.Unmarshal(content, &manifest); err != nil {
+				return nil, nil, gerror.Wrap(err, "模板包清单格式错误")
+			}
+		case strings.HasPrefix(entry.Name, model.BundleFilesDir):
+			filePath, err := validateBundlePath(strings.TrimPrefix(entry.Name, model.BundleFilesDir))
+			if err != nil {
+				return nil, nil, err
+			}
+			content, err := readZipEntry(entry, remaining, maxUncompressedSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			remaining -= int64(len(content))
+			contents[filePath] = string(content)
+		}
+	}
+
+	if manifest == nil || manifest.Template == nil {
+		return nil, nil, gerror.Newf("模板包缺少 %s", model.BundleManifestName)
+	}
+	if manifest.FormatVersion > model.BundleFormatVersion {
+		return nil, nil, gerror.Newf("模板包格式版本 %d 高于当前支持的版本 %d，请升级服务后再导入", manifest.FormatVersion, model.BundleFormatVersion)
+	}
+	if strings.TrimSpace(manifest.Template.Name) == "" {
+		return nil, nil, gerror.New("模板包中的模板名称为空")
+	}
+	for _, file := range manifest.Files {
+		filePath, err := validateBundlePath(file.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		file.Path = filePath
+		if _, ok := contents[filePath]; file.IsDirectory == 0 && !ok {
+			return nil, nil, gerror.Newf("模板包缺少文件 %s 的内容", filePath)
+		}
+	}
+	return manifest, contents, nil
+}
+
+// readZipEntry 读取ZIP中的一个文件，内容超过剩余的解压大小额度时返回错误
+func readZipEntry(entry *zip.File, remaining, maxUncompressedSize int64) ([]byte, error) {
+	exceeded := gerror.Newf("模板包解压后的大小超过上限 %d 字节", maxUncompressedSize)
+	if entry.UncompressedSize64 > uint64(remaining) {
+		return nil, exceeded
+	}
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, gerror.Wrapf(err, "读取 %s 失败", entry.Name)
+	}
+	defer rc.Close()
+	// 头部记录的解压大小可以伪造，读取时同样限制
+	content, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "读取 %s 失败", entry.Name)
+	}
+	if int64(len(content)) > remaining {
+		return nil, exceeded
+	}
+	return content, nil
+}
+
+// availableName 在名称后追加序号，直到找到未被占用的模板名称
+func (s *sTemplateBundle) availableName(ctx context.Context, name string) (string, error) {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		count, err := dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Name, candidate).Count()
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// clearTemplate 覆盖导入前清空模板的文件和关联信息，文件删除会记录修订，可在修订历史中找回
+func (s *sTemplateBundle) clearTemplate(ctx context.Context, templateId int64) error {
+	fileIds, err := dao.TemplateFiles.Ctx(ctx).
+		Fields(dao.TemplateFiles.Columns().Id).
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Array()
+	if err != nil {
+		return gerror.Wrap(err, "获取模板文件失败")
+	}
+	if len(fileIds) > 0 {
+		ids := make([]int64, 0, len(fileIds))
+		for _, id := range fileIds {
+			ids = append(ids, id.Int64())
+		}
+		if err = service.TemplateFiles().BatchDelete(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	if _, err = dao.TemplateLanguages.Ctx(ctx).Where(dao.TemplateLanguages.Columns().TemplateId, templateId).Delete(); err != nil {
+		return gerror.Wrap(err, "删除原有模板语言失败")
+	}
+	if _, err = dao.TemplateTags.Ctx(ctx).Where(dao.TemplateTags.Columns().TemplateId, templateId).Delete(); err != nil {
+		return gerror.Wrap(err, "删除原有模板标签失败")
+	}
+	if _, err = dao.TemplateExposeFields.Ctx(ctx).Where(dao.TemplateExposeFields.Columns().TemplateId, templateId).Delete(); err != nil {
+		return gerror.Wrap(err, "删除原有模板暴露字段失败")
+	}
+	if _, err = dao.TemplateVariablePresets.Ctx(ctx).Where(dao.TemplateVariablePresets.Columns().TemplateId, templateId).Delete(); err != nil {
+		return gerror.Wrap(err, "删除原有预设订阅失败")
+	}
+	return nil
+}
+
+// importCategory 按名称匹配分类，不存在时创建
+func (s *sTemplateBundle) importCategory(ctx context.Context, category *model.TemplateBundleCategory) (int64, error) {
+	if category == nil || category.Name == "" {
+		return 0, nil
+	}
+	id, err := dao.Categories.Ctx(ctx).Where(dao.Categories.Columns().Name, category.Name).Value(dao.Categories.Columns().Id)
+	if err != nil {
+		return 0, gerror.Wrap(err, "获取模板分类失败")
+	}
+	if !id.IsEmpty() {
+		return id.Int64(), nil
+	}
+	newId, err := dao.Categories.Ctx(ctx).InsertAndGetId(do.Categories{
+		Name:        category.Name,
+		Description: category.Description,
+		Icon:        category.Icon,
+		Sort:        category.Sort,
+	})
+	if err != nil {
+		return 0, gerror.Wrap(err, "创建模板分类失败")
+	}
+	return newId, nil
+}
+
+// importLanguages 按语言代码匹配语言，本实例不存在的语言跳过并给出提示
+func (s *sTemplateBundle) importLanguages(ctx context.Context, templateId int64, languages []*model.TemplateBundleLanguage, res *api.TemplateBundleImportRes) error {
+	for _, language := range languages {
+		id, err := dao.Languages.Ctx(ctx).Where(dao.Languages.Columns().Code, language.Code).Value(dao.Languages.Columns().Id)
+		if err != nil {
+			return gerror.Wrap(err, "获取语言失败")
+		}
+		if id.IsEmpty() {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("语言 %s (%s) 在本实例中不存在，已跳过", language.Name, language.Code))
+			continue
+		}
+		_, err = dao.TemplateLanguages.Ctx(ctx).Insert(do.TemplateLanguages{
+			TemplateId: templateId,
+			LanguageId: id.Int(),
+			IsPrimary:  language.IsPrimary,
+		})
+		if err != nil {
+			return gerror.Wrap(err, "新增模板语言失败")
+		}
+	}
+	return nil
+}
+
+// importTags 按名称匹配标签，不存在时创建
+func (s *sTemplateBundle) importTags(ctx context.Context, templateId int64, tags []*model.TemplateBundleTag) error {
+	for _, tag := range tags {
+		id, err := dao.Tags.Ctx(ctx).Where("name = ? AND deleted_at IS NULL", tag.Name).Value(dao.Tags.Columns().Id)
+		if err != nil {
+			return gerror.Wrap(err, "获取标签失败")
+		}
+		tagId := id.Int64()
+		if id.IsEmpty() {
+			tagId, err = dao.Tags.Ctx(ctx).InsertAndGetId(do.Tags{
+				Name:        tag.Name,
+				Description: tag.Description,
+			})
+			if err != nil {
+				return gerror.Wrap(err, "创建标签失败")
+			}
+		}
+		_, err = dao.TemplateTags.Ctx(ctx).Insert(do.TemplateTags{
+			TemplateId: templateId,
+			TagId:      tagId,
+		})
+		if err != nil {
+			return gerror.Wrap(err, "新增模板标签失败")
+		}
+	}
+	return nil
+}
+
+// importPresets 按名称匹配变量预设，不存在时作为自定义预设创建，并订阅到模板
+func (s *sTemplateBundle) importPresets(ctx context.Context, templateId int64, presets []*model.TemplateBundlePreset) error {
+	for _, preset := range presets {
+		id, err := dao.VarPreset.Ctx(ctx).Where(dao.VarPreset.Columns().Name, preset.Name).Value(dao.VarPreset.Columns().Id)
+		if err != nil {
+			return gerror.Wrap(err, "获取变量预设失败")
+		}
+		presetId := id.Uint64()
+		if id.IsEmpty() {
+			newId, err := dao.VarPreset.Ctx(ctx).InsertAndGetId(do.VarPreset{
+				Name:            preset.Name,
+				DisplayName:     preset.DisplayName,
+				Description:     preset.Description,
+				Category:        "custom",
+				SchemaJson:      preset.SchemaJson,
+				DefaultDataJson: preset.DefaultDataJson,
+				Icon:            preset.Icon,
+				Version:         preset.Version,
+				IsEnabled:       1,
+			})
+			if err != nil {
+				return gerror.Wrap(err, "创建变量预设失败")
+			}
+			presetId = uint64(newId)
+		}
+		_, err = dao.TemplateVariablePresets.Insert(ctx, &do.TemplateVariablePresets{
+			TemplateId: templateId,
+			PresetId:   presetId,
+		})
+		if err != nil {
+			return gerror.Wrap(err, "订阅变量预设失败")
+		}
+	}
+	return nil
+}
+
+// importFiles 按路径层级依次创建目录和文件，返回导入的文件数（不含目录）
+func (s *sTemplateBundle) importFiles(ctx context.Context, templateId int64, files []*model.TemplateBundleFile, contents map[string]string) (int, error) {
+	sorted := make([]*model.TemplateBundleFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i].Path, "/") < strings.Count(sorted[j].Path, "/")
+	})
+
+	ids := make(map[string]int64)
+	fileCount := 0
+	for _, file := range sorted {
+		var parentId int64
+		if dir := path.Dir(file.Path); dir != "." {
+			var ok bool
+			if parentId, ok = ids[dir]; !ok {
+				// 清单中缺少上级目录时补建
+				var err error
+				if parentId, err = s.ensureDirectory(ctx, templateId, dir, ids); err != nil {
+					return 0, err
+				}
+			}
+		}
+		content := contents[file.Path]
+		md5 := ""
+		if file.IsDirectory == 0 {
+			md5 = gmd5.MustEncryptString(content)
+			fileCount++
+		}
+		id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
+			TemplateId:        templateId,
+			FilePath:          file.Path,
+			FileName:          path.Base(file.Path),
+			FileContent:       content,
+			FileSize:          len(content),
+			IsDirectory:       file.IsDirectory,
+			Md5:               md5,
+			Sort:              file.Sort,
+			ParentId:          parentId,
+			GenerateCondition: emptyJsonObject(file.GenerateCondition),
+			RepeatConfig:      file.RepeatConfig,
+		})
+		if err != nil {
+			return 0, gerror.Wrapf(err, "导入文件 %s 失败", file.Path)
+		}
+		ids[file.Path] = id
+	}
+	return fileCount, nil
+}
+
+// ensureDirectory 创建目录及其缺失的上级目录
+func (s *sTemplateBundle) ensureDirectory(ctx context.Context, templateId int64, dir string, ids map[string]int64) (int64, error) {
+	if id, ok := ids[dir]; ok {
+		return id, nil
+	}
+	var parentId int64
+	if parent := path.Dir(dir); parent != "." {
+		var err error
+		if parentId, err = s.ensureDirectory(ctx, templateId, parent, ids); err != nil {
+			return 0, err
+		}
+	}
+	id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
+		TemplateId:        templateId,
+		FilePath:          dir,
+		FileName:          path.Base(dir),
+		IsDirectory:       1,
+		ParentId:          parentId,
+		GenerateCondition: "{}",
+	})
+	if err != nil {
+		return 0, gerror.Wrapf(err, "创建目录 %s 失败", dir)
+	}
+	ids[dir] = id
+	return id, nil
+}
+
+// validateBundlePath 校验模板包中的文件路径，拒绝绝对路径和跳出根目录的路径
+func validateBundlePath(filePath string) (string, error) {
+	normalized := normalizeBundlePath(filePath)
+	if normalized == "" || normalized == "." || strings.HasPrefix(filePath, "/") ||
+		normalized == ".." || strings.HasPrefix(normalized, "../") {
+		return "", gerror.Newf("模板包中的文件路径 %q 无效", filePath)
+	}
+	return normalized, nil
+}
+
+func normalizeBundlePath(filePath string) string {
+	return strings.TrimPrefix(path.Clean(strings.ReplaceAll(filePath, "\\", "/")), "/")
+}
+
+// emptyJsonObject 生成条件字段为JSON类型，与 Fork 一致，空值保存为 {}
+func emptyJsonObject(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "{}"
+	}
+	return value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
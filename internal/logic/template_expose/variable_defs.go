@@ -0,0 +1,82 @@
+package template_expose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+)
+
+// GetVariableDefs 获取模板最新版本暴露字段中的变量定义，按定义顺序返回；
+// 模板未设置暴露字段时返回空切片
+func (s *sTemplateExpose) GetVariableDefs(ctx context.Context, templateId int64) ([]*model.TemplateVariableDef, error) {
+	var expose *entity.TemplateExposeFields
+	err := dao.TemplateExposeFields.Ctx(ctx).
+		Where(dao.TemplateExposeFields.Columns().TemplateId, templateId).
+		Order(dao.TemplateExposeFields.Columns().Id + " DESC").
+		Scan(&expose)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询模板暴露字段失败")
+	}
+	if expose == nil || expose.FieldSchemaJson == "" {
+		return []*model.TemplateVariableDef{}, nil
+	}
+	defs, err := parseVariableDefs(expose.FieldSchemaJson)
+	if err != nil {
+		return nil, gerror.Wrap(err, "变量定义格式不正确")
+	}
+	return defs, nil
+}
+
+// parseVariableDefs 解析 FieldSchemaJson，保留顶层变量在 JSON 中的书写顺序
+func parseVariableDefs(schemaJson string) ([]*model.TemplateVariableDef, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(schemaJson)))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, gerror.New("变量定义必须是以变量名为键的JSON对象")
+	}
+
+	defs := make([]*model.TemplateVariableDef, 0)
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		name := token.(string)
+		var def model.TemplateVariableDef
+		if err = decoder.Decode(&def); err != nil {
+			return nil, gerror.Wrapf(err, "变量 %s 的定义不正确", name)
+		}
+		def.Name = name
+		def.Sort = len(defs)
+		fillVariableDef(&def)
+		defs = append(defs, &def)
+	}
+	return defs, nil
+}
+
+// fillVariableDef 补全嵌套字段的名称和缺省类型
+func fillVariableDef(def *model.TemplateVariableDef) {
+	if def.Type == "" {
+		def.Type = model.VariableTypeString
+	}
+	if def.Items != nil {
+		fillVariableDef(def.Items)
+	}
+	for name, prop := range def.Properties {
+		if prop == nil {
+			delete(def.Properties, name)
+			continue
+		}
+		prop.Name = name
+		fillVariableDef(prop)
+	}
+}
@@ -0,0 +1,206 @@
+package template_files
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_files"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// computedVariable 待计算的变量
+type computedVariable struct {
+	def  *model.TemplateVariableDef
+	tmpl *template.Template
+	deps []string // 依赖的其他计算变量
+}
+
+// computeVariables 按依赖顺序计算变量定义中的计算变量并写入 variables，
+// 表达式错误、循环依赖和计算结果不符合定义时返回字段级错误
+func (s sTemplateFiles) computeVariables(ctx context.Context, delims *model.TemplateDelimiters, defs []*model.TemplateVariableDef, variables map[string]interface{}) ([]*api.VariableValidationError, error) {
+	computed := make(map[string]*computedVariable)
+	var order []string
+	for _, def := range defs {
+		if def.Computed != "" {
+			computed[def.Name] = &computedVariable{def: def}
+			order = append(order, def.Name)
+		}
+	}
+	if len(computed) == 0 {
+		return nil, nil
+	}
+
+	funcs := s.getTemplateFuncs(nil, "")
+
+	c := &variableCoercer{patterns: make(map[string]*regexp.Regexp)}
+	failed := make(map[string]bool)
+
+	// 1. 解析表达式并找出依赖的计算变量
+	for _, name := range order {
+		cv := computed[name]
+		var err error
+		cv.tmpl, err = template.New(name).Delims(delims.Left, delims.Right).Funcs(funcs).Parse(cv.def.Computed)
+		if err != nil {
+			c.fail(name, "计算表达式解析失败: %s", err)
+			failed[name] = true
+			continue
+		}
+		for dep := range referencedRoots(cv.tmpl.Tree.Root) {
+			if _, ok := computed[dep]; ok {
+				cv.deps = append(cv.deps, dep)
+			}
+		}
+	}
+
+	// 2. 按依赖关系排序，循环中的变量全部报错
+	sorted, cycles := sortComputedVariables(order, computed)
+	for _, cycle := range cycles {
+		for _, name := range cycle[:len(cycle)-1] {
+			if !failed[name] {
+				c.fail(name, "计算变量存在循环依赖: %s", strings.Join(cycle, " -> "))
+				failed[name] = true
+			}
+		}
+	}
+
+	// 3. 在沙箱中依次计算，结果按变量定义转换类型
+	sandbox, cancel := newRenderSandbox(ctx)
+	defer cancel()
+	for _, name := range sorted {
+		cv := computed[name]
+		if failed[name] {
+			continue
+		}
+		if dep := firstFailed(cv.deps, failed); dep != "" {
+			c.fail(name, "依赖的计算变量 %s 计算失败", dep)
+			failed[name] = true
+			continue
+		}
+		run := sandbox.Begin(name)
+		output, err := run.Execute(cv.tmpl.Funcs(run.Funcs(funcs)), variables)
+		run.Close()
+		if err != nil {
+			c.fail(name, "计算失败: %s", err)
+			failed[name] = true
+			continue
+		}
+		errCount := len(c.errors)
+		value, ok := c.value(name, cv.def, output)
+		if len(c.errors) > errCount {
+			failed[name] = true
+			continue
+		}
+		if ok {
+			variables[name] = value
+		} else {
+			delete(variables, name)
+		}
+	}
+	return c.errors, nil
+}
+
+// sortComputedVariables 按依赖关系对计算变量做拓扑排序，依赖在前；
+// 同时返回发现的循环，每个循环以起点结尾，如 [A B A]
+func sortComputedVariables(order []string, computed map[string]*computedVariable) (sorted []string, cycles [][]string) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(name string)
+	visit = func(name string) {
+		switch state[name] {
+		case visited:
+			return
+		case visiting:
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] == name {
+					cycle := append(append([]string{}, stack[i:]...), name)
+					cycles = append(cycles, cycle)
+					break
+				}
+			}
+			return
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range computed[name].deps {
+			visit(dep)
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		sorted = append(sorted, name)
+	}
+	for _, name := range order {
+		visit(name)
+	}
+	return
+}
+
+func firstFailed(names []string, failed map[string]bool) string {
+	for _, name := range names {
+		if failed[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// referencedRoots 收集表达式中引用的顶层变量名，如 .ProjectName、$.Org；
+// with、range 块内的 . 不再指向根变量，其中的字段引用不计入
+func referencedRoots(node parse.Node) map[string]bool {
+	roots := make(map[string]bool)
+	var walk func(node parse.Node, atRoot bool)
+	walk = func(node parse.Node, atRoot bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, atRoot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, atRoot)
+		case *parse.IfNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, atRoot)
+			walk(n.ElseList, atRoot)
+		case *parse.WithNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.RangeNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, atRoot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				for _, arg := range cmd.Args {
+					walk(arg, atRoot)
+				}
+			}
+		case *parse.ChainNode:
+			walk(n.Node, atRoot)
+		case *parse.FieldNode:
+			if atRoot {
+				roots[n.Ident[0]] = true
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				roots[n.Ident[1]] = true
+			}
+		}
+	}
+	walk(node, true)
+	return roots
+}
@@ -0,0 +1,68 @@
+package template_files
+
+import (
+	"strings"
+	"text/template"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_files"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+)
+
+// partialsDir 模板根目录下的公共片段目录，其中文件的 {{define}} 定义对同一模板的所有文件可用，
+// 通过 {{template "name" .}} 引用，片段文件本身不出现在渲染结果和ZIP中
+const partialsDir = "_partials"
+
+// isPartialFile 判断文件是否为公共片段目录或其中的文件
+func isPartialFile(file *entity.TemplateFiles) bool {
+	name := normalizeTemplatePath(file.FilePath)
+	return name == partialsDir || strings.HasPrefix(name, partialsDir+"/")
+}
+
+// splitPartialFiles 将模板文件拆分为普通文件和公共片段文件
+func splitPartialFiles(files []*entity.TemplateFiles) (regular, partials []*entity.TemplateFiles) {
+	for _, file := range files {
+		if isPartialFile(file) {
+			partials = append(partials, file)
+		} else {
+			regular = append(regular, file)
+		}
+	}
+	return
+}
+
+// parsePartials 解析公共片段，没有片段时返回 nil；
+// 解析失败的片段记录诊断信息后跳过，不影响其他文件渲染
+func (s sTemplateFiles) parsePartials(partials []*entity.TemplateFiles, delims *model.TemplateDelimiters, funcs template.FuncMap) (*template.Template, []*api.RenderFileDiagnostic) {
+	var base *template.Template
+	var diagnostics []*api.RenderFileDiagnostic
+	for _, file := range partials {
+		if file.IsDirectory == 1 || file.FileContent == "" {
+			continue
+		}
+		content := s.fixLegacyVariableSyntax(file.FileContent, delims)
+
+		// 先单独解析，避免有语法错误的片段污染公共模板集合
+		if _, err := template.New(file.FilePath).Delims(delims.Left, delims.Right).Funcs(funcs).Parse(content); err != nil {
+			diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileContent", content, err))
+			continue
+		}
+		if base == nil {
+			base = template.New(partialsDir).Delims(delims.Left, delims.Right).Funcs(funcs)
+		}
+		if _, err := base.New(file.FilePath).Parse(content); err != nil {
+			diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileContent", content, err))
+		}
+	}
+	return base, diagnostics
+}
+
+// newContentTemplate 创建用于渲染文件内容的模板，存在公共片段时在片段集合的副本上创建，
+// 使文件内容可以通过 {{template}} 引用片段中的定义
+func (s sTemplateFiles) newContentTemplate(partials *template.Template, name string, delims *model.TemplateDelimiters, funcs template.FuncMap) *template.Template {
+	if partials == nil {
+		return template.New(name).Delims(delims.Left, delims.Right).Funcs(funcs)
+	}
+	// 片段集合从不执行，Clone 不会失败
+	return template.Must(partials.Clone()).New(name).Delims(delims.Left, delims.Right).Funcs(funcs)
+}
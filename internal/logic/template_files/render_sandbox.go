@@ -0,0 +1,412 @@
+package template_files
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 渲染沙箱默认限制，可通过配置文件 render 节覆盖
+const (
+	defaultRenderFileTimeout    = 5 * time.Second
+	defaultRenderTreeTimeout    = 30 * time.Second
+	defaultRenderMaxOutputBytes = 50 * 1024 * 1024
+	defaultRenderMaxLoopItems   = 100000
+)
+
+// renderLimits 模板渲染限制
+type renderLimits struct {
+	FileTimeout    time.Duration // 单个文件的渲染时长上限
+	TreeTimeout    time.Duration // 整棵文件树的渲染时长上限
+	MaxOutputBytes int64         // 一次请求累计输出的字节数上限
+	MaxLoopItems   int           // until、untilStep 生成的序列长度以及 repeat 的次数上限
+}
+
+// loadRenderLimits 读取渲染限制配置，未配置或配置非法时使用默认值
+//
+//	render:
+//	  fileTimeout: "5s"
+//	  treeTimeout: "30s"
+//	  maxOutputBytes: 52428800
+//	  maxLoopItems: 100000
+func loadRenderLimits(ctx context.Context) renderLimits {
+	limits := renderLimits{
+		FileTimeout:    g.Cfg().MustGet(ctx, "render.fileTimeout", defaultRenderFileTimeout).Duration(),
+		TreeTimeout:    g.Cfg().MustGet(ctx, "render.treeTimeout", defaultRenderTreeTimeout).Duration(),
+		MaxOutputBytes: g.Cfg().MustGet(ctx, "render.maxOutputBytes", defaultRenderMaxOutputBytes).Int64(),
+		MaxLoopItems:   g.Cfg().MustGet(ctx, "render.maxLoopItems", defaultRenderMaxLoopItems).Int(),
+	}
+	if limits.FileTimeout <= 0 {
+		limits.FileTimeout = defaultRenderFileTimeout
+	}
+	if limits.TreeTimeout <= 0 {
+		limits.TreeTimeout = defaultRenderTreeTimeout
+	}
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = defaultRenderMaxOutputBytes
+	}
+	if limits.MaxLoopItems <= 0 {
+		limits.MaxLoopItems = defaultRenderMaxLoopItems
+	}
+	return limits
+}
+
+// renderLimitError 渲染超出沙箱限制时返回的错误
+type renderLimitError struct {
+	Message string
+}
+
+func (e *renderLimitError) Error() string {
+	return e.Message
+}
+
+// asRenderLimitError 判断错误是否由沙箱限制触发
+func asRenderLimitError(err error) (*renderLimitError, bool) {
+	var limitErr *renderLimitError
+	if errors.As(err, &limitErr) {
+		return limitErr, true
+	}
+	return nil, false
+}
+
+// renderSandbox 模板渲染沙箱，一次请求共享同一个沙箱，
+// 统一控制整棵树的渲染时长和累计输出大小，并跟随请求上下文取消
+type renderSandbox struct {
+	ctx     context.Context
+	limits  renderLimits
+	written int64
+}
+
+// newRenderSandbox 创建渲染沙箱，调用方需在渲染结束后调用返回的 cancel
+func newRenderSandbox(ctx context.Context) (*renderSandbox, context.CancelFunc) {
+	limits := loadRenderLimits(ctx)
+	treeCtx, cancel := context.WithTimeout(ctx, limits.TreeTimeout)
+	return &renderSandbox{ctx: treeCtx, limits: limits}, cancel
+}
+
+// Err 返回沙箱当前的中止原因，未中止时返回 nil
+func (sb *renderSandbox) Err() error {
+	return sb.contextError(sb.ctx, "")
+}
+
+// Begin 开始渲染一个文件，单文件时长从此刻开始计算，包含解析和执行，
+// 调用方需在该文件渲染结束后调用 Close
+func (sb *renderSandbox) Begin(name string) *renderRun {
+	ctx, cancel := context.WithTimeout(sb.ctx, sb.limits.FileTimeout)
+	return &renderRun{sb: sb, ctx: ctx, cancel: cancel, name: name}
+}
+
+// contextError 将上下文结束原因转换为渲染限制错误
+func (sb *renderSandbox) contextError(ctx context.Context, name string) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(sb.ctx.Err(), context.Canceled):
+		return &renderLimitError{Message: "渲染请求已取消"}
+	case errors.Is(sb.ctx.Err(), context.DeadlineExceeded):
+		return &renderLimitError{Message: fmt.Sprintf("渲染总时长超过上限 %s", sb.limits.TreeTimeout)}
+	}
+	if name == "" {
+		return &renderLimitError{Message: fmt.Sprintf("渲染时长超过上限 %s", sb.limits.FileTimeout)}
+	}
+	return &renderLimitError{Message: fmt.Sprintf("文件 %s 渲染时长超过上限 %s", name, sb.limits.FileTimeout)}
+}
+
+// renderRun 单个文件的一次渲染
+type renderRun struct {
+	sb     *renderSandbox
+	ctx    context.Context
+	cancel context.CancelFunc
+	name   string
+}
+
+// Close 结束本次渲染，释放计时器
+func (r *renderRun) Close() {
+	r.cancel()
+}
+
+// Err 返回本次渲染的中止原因，未中止时返回 nil
+func (r *renderRun) Err() error {
+	return r.sb.contextError(r.ctx, r.name)
+}
+
+// Funcs 包装模板函数：每次调用前检查是否已超时或取消，并限制会生成大量数据的函数
+func (r *renderRun) Funcs(funcs template.FuncMap) template.FuncMap {
+	wrapped := make(template.FuncMap, len(funcs))
+	for name, fn := range funcs {
+		wrapped[name] = r.guard(fn)
+	}
+
+	wrapped["until"] = r.guard(func(count int) []int {
+		r.checkLoopItems("until", count)
+		step := 1
+		if count < 0 {
+			step = -1
+		}
+		return untilStep(0, count, step)
+	})
+	wrapped["untilStep"] = r.guard(func(start, stop, step int) []int {
+		if step != 0 {
+			r.checkLoopItems("untilStep", (stop-start)/step)
+		}
+		return untilStep(start, stop, step)
+	})
+	wrapped["repeat"] = r.guard(func(count int, str string) string {
+		r.checkLoopItems("repeat", count)
+		if int64(count)*int64(len(str)) > r.sb.limits.MaxOutputBytes {
+			panic(&renderLimitError{Message: fmt.Sprintf("repeat 生成的内容超过输出上限 %d 字节", r.sb.limits.MaxOutputBytes)})
+		}
+		return strings.Repeat(str, count)
+	})
+
+	// seq 和随机字符串函数一次调用就能生成任意大小的内容，调用前按参数检查长度
+	if fn, ok := funcs["seq"].(func(...int) string); ok {
+		wrapped["seq"] = r.guard(func(params ...int) string {
+			count, digits := seqSize(params)
+			r.checkLoopItems("seq", clampInt(count))
+			r.checkOutputBytes("seq", count*float64(digits+1))
+			return fn(params...)
+		})
+	}
+	for _, name := range []string{"randAlphaNum", "randAlpha", "randAscii", "randNumeric"} {
+		if fn, ok := funcs[name].(func(int) string); ok {
+			name := name
+			wrapped[name] = r.guard(func(count int) string {
+				r.checkLoopItems(name, count)
+				r.checkOutputBytes(name, float64(count))
+				return fn(count)
+			})
+		}
+	}
+	if fn, ok := funcs["randBytes"].(func(int) (string, error)); ok {
+		wrapped["randBytes"] = r.guard(func(count int) (string, error) {
+			r.checkLoopItems("randBytes", count)
+			// 结果为 base64 编码
+			r.checkOutputBytes("randBytes", math.Ceil(float64(count)/3)*4)
+			return fn(count)
+		})
+	}
+	return wrapped
+}
+
+// checkOutputBytes 函数生成的内容超过输出上限时中止渲染
+func (r *renderRun) checkOutputBytes(name string, size float64) {
+	if size > float64(r.sb.limits.MaxOutputBytes) {
+		panic(&renderLimitError{Message: fmt.Sprintf("%s 生成的内容超过输出上限 %d 字节", name, r.sb.limits.MaxOutputBytes)})
+	}
+}
+
+// seqSize 按 sprig seq 的参数规则估算生成的数字个数和单个数字的最大位数，使用浮点数避免极端参数溢出
+func seqSize(params []int) (count float64, digits int) {
+	start, end, step := 1, 0, 1
+	switch len(params) {
+	case 1:
+		end = params[0]
+	case 2:
+		start, end = params[0], params[1]
+	case 3:
+		start, step, end = params[0], params[1], params[2]
+	default:
+		return 0, 0
+	}
+	if step == 0 {
+		return 0, 0
+	}
+	count = math.Abs(float64(end)-float64(start))/math.Abs(float64(step)) + 1
+	digits = len(strconv.Itoa(start))
+	if n := len(strconv.Itoa(end)); n > digits {
+		digits = n
+	}
+	return count, digits
+}
+
+// clampInt 将浮点数转换为 int，超出范围时取 int 的最大值
+func clampInt(f float64) int {
+	if f >= math.MaxInt {
+		return math.MaxInt
+	}
+	return int(f)
+}
+
+// checkLoopItems 序列长度超过上限时中止渲染
+func (r *renderRun) checkLoopItems(name string, count int) {
+	if count < 0 {
+		count = -count
+	}
+	if count > r.sb.limits.MaxLoopItems {
+		panic(&renderLimitError{Message: fmt.Sprintf("%s 的次数 %d 超过上限 %d", name, count, r.sb.limits.MaxLoopItems)})
+	}
+}
+
+// guard 通过反射包装任意模板函数，调用前检查上下文；
+// text/template 会捕获函数中的 panic 并作为执行错误返回
+func (r *renderRun) guard(fn interface{}) interface{} {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return fn
+	}
+	return reflect.MakeFunc(fnValue.Type(), func(args []reflect.Value) []reflect.Value {
+		if err := r.Err(); err != nil {
+			panic(err)
+		}
+		if fnValue.Type().IsVariadic() {
+			return fnValue.CallSlice(args)
+		}
+		return fnValue.Call(args)
+	}).Interface()
+}
+
+// Execute 在沙箱中执行模板，超出单文件时长、整树时长或输出上限时返回 renderLimitError
+func (r *renderRun) Execute(tmpl *template.Template, data interface{}) (string, error) {
+	if err := r.Err(); err != nil {
+		return "", err
+	}
+	tmpl, err := r.instrument(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	writer := &sandboxWriter{run: r}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				if err, ok := p.(error); ok {
+					done <- err
+					return
+				}
+				done <- fmt.Errorf("%v", p)
+			}
+		}()
+		done <- tmpl.Execute(writer, data)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			// 超时或取消时的位置指向注入的检查，没有参考价值
+			if ctxErr := r.Err(); ctxErr != nil {
+				return "", ctxErr
+			}
+			// 其他限制错误保留模板包装的位置信息，便于定位触发限制的行
+			return "", err
+		}
+		return writer.buf.String(), nil
+	case <-r.ctx.Done():
+		// 模板仍在后台执行时直接返回，执行到下一次注入的检查、函数调用或写入时中止
+		return "", r.Err()
+	}
+}
+
+// sandboxCheckFunc 注入到模板中检查上下文的函数名
+const sandboxCheckFunc = "__sandboxCheck"
+
+// instrument 在模板副本的每个模板体（包括 define 定义的模板）和每个 range 循环体开头注入一次上下文检查。
+// 没有函数调用和输出的模板只会在 {{template}} 嵌套调用和 range 循环中无限制地消耗CPU，
+// 注入检查后超时或取消时模板执行本身随之中止，而不只是停止等待。
+// 模板树可能与其他请求共享（公共模板通过 Clone 复用），因此只修改复制后的模板树
+func (r *renderRun) instrument(tmpl *template.Template) (*template.Template, error) {
+	check := func() (string, error) {
+		return "", r.Err()
+	}
+	trees, err := parse.Parse("sandbox", "{{"+sandboxCheckFunc+"}}", "{{", "}}", map[string]interface{}{sandboxCheckFunc: check})
+	if err != nil {
+		return nil, err
+	}
+	checkNode := trees["sandbox"].Root.Nodes[0]
+
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	clone.Funcs(template.FuncMap{sandboxCheckFunc: check})
+	for _, t := range clone.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		tree := t.Tree.Copy()
+		instrumentList(tree.Root, checkNode, true)
+		if _, err := clone.AddParseTree(t.Name(), tree); err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// instrumentList 在所有 range 循环体开头插入检查节点，prepend 为 true 时同时在列表开头插入
+func instrumentList(list *parse.ListNode, check parse.Node, prepend bool) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.IfNode:
+			instrumentList(n.List, check, false)
+			instrumentList(n.ElseList, check, false)
+		case *parse.WithNode:
+			instrumentList(n.List, check, false)
+			instrumentList(n.ElseList, check, false)
+		case *parse.RangeNode:
+			instrumentList(n.List, check, true)
+			instrumentList(n.ElseList, check, false)
+		case *parse.ListNode:
+			instrumentList(n, check, false)
+		}
+	}
+	if prepend {
+		list.Nodes = append([]parse.Node{check}, list.Nodes...)
+	}
+}
+
+// sandboxWriter 累计输出字节数并在渲染中止后拒绝写入
+type sandboxWriter struct {
+	run *renderRun
+	buf bytes.Buffer
+}
+
+func (w *sandboxWriter) Write(p []byte) (int, error) {
+	if err := w.run.Err(); err != nil {
+		return 0, err
+	}
+	sb := w.run.sb
+	if atomic.AddInt64(&sb.written, int64(len(p))) > sb.limits.MaxOutputBytes {
+		return 0, &renderLimitError{Message: fmt.Sprintf("渲染输出超过上限 %d 字节", sb.limits.MaxOutputBytes)}
+	}
+	return w.buf.Write(p)
+}
+
+// untilStep 与 sprig 的 untilStep 行为一致
+func untilStep(start, stop, step int) []int {
+	var result []int
+	if step == 0 {
+		return result
+	}
+	if stop < start {
+		if step >= 0 {
+			return result
+		}
+		for i := start; i > stop; i += step {
+			result = append(result, i)
+		}
+		return result
+	}
+	if step <= 0 {
+		return result
+	}
+	for i := start; i < stop; i += step {
+		result = append(result, i)
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package template_files
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// newTestSandbox 创建不读取配置文件的渲染沙箱
+func newTestSandbox(fileTimeout time.Duration) *renderSandbox {
+	return &renderSandbox{
+		ctx: context.Background(),
+		limits: renderLimits{
+			FileTimeout:    fileTimeout,
+			TreeTimeout:    time.Minute,
+			MaxOutputBytes: 1 << 20,
+			MaxLoopItems:   1000,
+		},
+	}
+}
+
+// nestedTemplateCalls 生成 depth 层 define，每层调用下一层 fanOut 次，不调用函数也没有输出
+func nestedTemplateCalls(depth, fanOut int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		fmt.Fprintf(&b, `{{define "t%d"}}`, i)
+		if i < depth-1 {
+			for j := 0; j < fanOut; j++ {
+				fmt.Fprintf(&b, `{{template "t%d"}}`, i+1)
+			}
+		}
+		b.WriteString(`{{end}}`)
+	}
+	b.WriteString(`{{template "t0"}}`)
+	return b.String()
+}
+
+// waitGoroutines 等待协程数回落到 baseline，超时返回 false
+func waitGoroutines(baseline int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+func TestRenderRunStopsExecutionAfterTimeout(t *testing.T) {
+	items := make([]int, 2000)
+	cases := []struct {
+		name   string
+		source string
+		data   interface{}
+	}{
+		{name: "nested template calls", source: nestedTemplateCalls(10, 10)},
+		{name: "nested range", source: `{{range .}}{{range $}}{{range $}}{{end}}{{end}}{{end}}`, data: items},
+		{name: "range inside if", source: `{{if .}}{{range .}}{{range $}}{{range $}}{{end}}{{end}}{{end}}{{end}}`, data: items},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("main").Parse(c.source))
+			baseline := runtime.NumGoroutine()
+
+			run := newTestSandbox(100 * time.Millisecond).Begin("main.tmpl")
+			start := time.Now()
+			_, err := run.Execute(tmpl, c.data)
+			run.Close()
+
+			if _, ok := asRenderLimitError(err); !ok {
+				t.Fatalf("expected render limit error, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("execute returned after %s", elapsed)
+			}
+			if !waitGoroutines(baseline, 2*time.Second) {
+				t.Fatalf("template execution goroutine still running after timeout")
+			}
+		})
+	}
+}
+
+func TestRenderRunKeepsOriginalTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("main").Parse(`{{define "a"}}x{{end}}{{range .}}{{template "a"}}{{end}}`))
+	sandbox := newTestSandbox(time.Second)
+	for i := 0; i < 2; i++ {
+		run := sandbox.Begin("main.tmpl")
+		output, err := run.Execute(tmpl, []int{1, 2, 3})
+		run.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output != "xxx" {
+			t.Fatalf("unexpected output %q", output)
+		}
+	}
+	if tree := tmpl.Lookup("a").Tree; len(tree.Root.Nodes) != 1 {
+		t.Fatalf("original template tree was modified: %s", tree.Root)
+	}
+}
+
+func TestRenderRunLimitsGeneratingFuncs(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		limit  bool
+	}{
+		{name: "seq within limit", source: `{{ seq 5 }}`},
+		{name: "seq one argument", source: `{{ seq 1000000000 }}`, limit: true},
+		{name: "seq two arguments", source: `{{ seq -1000000000 1000000000 }}`, limit: true},
+		{name: "seq with step", source: `{{ seq 0 1 2000000000 }}`, limit: true},
+		{name: "seq extreme arguments", source: `{{ seq -9000000000000000000 9000000000000000000 }}`, limit: true},
+		{name: "randAlphaNum within limit", source: `{{ randAlphaNum 8 }}`},
+		{name: "randAlphaNum", source: `{{ randAlphaNum 2000000000 }}`, limit: true},
+		{name: "randAlpha", source: `{{ randAlpha 2000000000 }}`, limit: true},
+		{name: "randAscii", source: `{{ randAscii 2000000000 }}`, limit: true},
+		{name: "randNumeric", source: `{{ randNumeric 2000000000 }}`, limit: true},
+		{name: "randBytes within limit", source: `{{ randBytes 16 }}`},
+		{name: "randBytes", source: `{{ randBytes 2000000000 }}`, limit: true},
+	}
+	sandbox := newTestSandbox(5 * time.Second)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			run := sandbox.Begin("main.tmpl")
+			defer run.Close()
+			tmpl := template.Must(template.New("main").Funcs(run.Funcs(sprig.FuncMap())).Parse(c.source))
+			output, err := run.Execute(tmpl, nil)
+			if !c.limit {
+				if err != nil || output == "" {
+					t.Fatalf("unexpected result %q, %v", output, err)
+				}
+				return
+			}
+			if _, ok := asRenderLimitError(err); !ok {
+				t.Fatalf("expected render limit error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package template_files
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	dao "github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	service "github.com/ciclebyte/template_starter/internal/service"
+)
+
+// renderSource 一次渲染所使用的模板内容，来自某个发布版本的快照或模板当前的工作区
+type renderSource struct {
+	TemplateId int64
+	Release    *model.TemplateReleaseInfo // 渲染的发布版本，为 nil 时表示工作区
+	Files      []*entity.TemplateFiles
+	Variables  []*model.TemplateVariableDef
+	Delims     *model.TemplateDelimiters
+}
+
+// Version 返回渲染的版本号，工作区为 draft
+func (src *renderSource) Version() string {
+	if src.Release == nil {
+		return model.ReleaseVersionDraft
+	}
+	return src.Release.Version
+}
+
+// loadRenderSource 按版本加载渲染内容：version 为空或 latest 时使用最新发布版本，
+// 为 draft 或模板尚未发布任何版本时使用工作区。与 templates.GetById 一致，回收站中的模板视为不存在
+func (s sTemplateFiles) loadRenderSource(ctx context.Context, templateId int64, version string) (*renderSource, error) {
+	count, err := dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Id, templateId).Count()
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板失败")
+	}
+	if count == 0 {
+		return nil, gerror.Newf("模板ID %d 不存在", templateId)
+	}
+
+	release, snapshot, err := service.TemplateReleases().LoadSnapshot(ctx, templateId, version)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot != nil {
+		return &renderSource{
+			TemplateId: templateId,
+			Release:    release,
+			Files:      snapshot.Files,
+			Variables:  snapshot.Variables,
+			Delims:     snapshot.Delimiters,
+		}, nil
+	}
+
+	src := &renderSource{TemplateId: templateId}
+	err = dao.TemplateFiles.Ctx(ctx).Where("template_id = ?", templateId).Scan(&src.Files)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板文件列表失败")
+	}
+	if src.Variables, err = service.TemplateExpose().GetVariableDefs(ctx, templateId); err != nil {
+		return nil, err
+	}
+	if src.Delims, err = service.Templates().GetDelimiters(ctx, templateId); err != nil {
+		return nil, gerror.Wrap(err, "获取模板定界符失败")
+	}
+	return src, nil
+}
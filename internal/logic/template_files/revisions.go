@@ -0,0 +1,354 @@
+package template_files
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/pmezard/go-difflib/difflib"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_files"
+	dao "github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	do "github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	liberr "github.com/ciclebyte/template_starter/library/liberr"
+)
+
+// trackRevisions 在事务中执行文件变更并为每个文件记录一条修订。
+// 删除操作记录删除前的内容，其他操作记录变更后的内容；
+// 没有任何修订的历史文件会先补一条变更前的 create 修订作为基线
+func (s sTemplateFiles) trackRevisions(ctx context.Context, action string, fileIds []int64, fn func(ctx context.Context) error) error {
+	return dao.TemplateFileRevisions.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if err := s.saveBaselineRevisions(ctx, fileIds); err != nil {
+			return err
+		}
+		if action == model.FileRevisionActionDelete {
+			if err := s.recordRevisions(ctx, action, fileIds...); err != nil {
+				return err
+			}
+			return fn(ctx)
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return s.recordRevisions(ctx, action, fileIds...)
+	})
+}
+
+// saveBaselineRevisions 为尚无修订记录的文件记录当前内容，保证第一次修改前的内容可以恢复
+func (s sTemplateFiles) saveBaselineRevisions(ctx context.Context, fileIds []int64) error {
+	if len(fileIds) == 0 {
+		return nil
+	}
+	tracked, err := dao.TemplateFileRevisions.Ctx(ctx).
+		Fields(dao.TemplateFileRevisions.Columns().FileId).
+		WhereIn(dao.TemplateFileRevisions.Columns().FileId, fileIds).
+		Distinct().
+		Array()
+	if err != nil {
+		return err
+	}
+	trackedIds := make(map[int64]bool, len(tracked))
+	for _, id := range tracked {
+		trackedIds[id.Int64()] = true
+	}
+	var untracked []int64
+	for _, id := range fileIds {
+		if !trackedIds[id] {
+			untracked = append(untracked, id)
+		}
+	}
+	return s.recordRevisions(ctx, model.FileRevisionActionCreate, untracked...)
+}
+
+// recordRevisions 以文件当前的内容为每个文件追加一条修订，不存在的文件跳过
+func (s sTemplateFiles) recordRevisions(ctx context.Context, action string, fileIds ...int64) error {
+	if len(fileIds) == 0 {
+		return nil
+	}
+	var files []*entity.TemplateFiles
+	err := dao.TemplateFiles.Ctx(ctx).WhereIn(dao.TemplateFiles.Columns().Id, fileIds).Scan(&files)
+	if err != nil {
+		return err
+	}
+	authorId, authorName := revisionAuthor(ctx)
+	for _, file := range files {
+		maxRevision, err := dao.TemplateFileRevisions.Ctx(ctx).
+			Where(dao.TemplateFileRevisions.Columns().FileId, file.Id).
+			Max(dao.TemplateFileRevisions.Columns().Revision)
+		if err != nil {
+			return err
+		}
+		_, err = dao.TemplateFileRevisions.Ctx(ctx).Insert(do.TemplateFileRevisions{
+			FileId:            file.Id,
+			TemplateId:        file.TemplateId,
+			Revision:          int(maxRevision) + 1,
+			Action:            action,
+			FilePath:          file.FilePath,
+			FileName:          file.FileName,
+			FileContent:       file.FileContent,
+			FileSize:          file.FileSize,
+			IsDirectory:       file.IsDirectory,
+			Md5:               file.Md5,
+			Sort:              file.Sort,
+			ParentId:          file.ParentId,
+			GenerateCondition: file.GenerateCondition,
+			RepeatConfig:      file.RepeatConfig,
+			AuthorId:          authorId,
+			AuthorName:        authorName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revisionAuthor 获取当前登录用户作为修订的作者，匿名请求返回 0 和空用户名
+func revisionAuthor(ctx context.Context) (uint64, string) {
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return 0, ""
+	}
+	return gconv.Uint64(r.GetCtxVar("user_id")), r.GetCtxVar("username").String()
+}
+
+// Revisions 获取文件的修订历史，按修订号从新到旧排列，不含文件内容
+func (s sTemplateFiles) Revisions(ctx context.Context, fileId int64) (revisions []*model.TemplateFileRevisionInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		var list []*entity.TemplateFileRevisions
+		err = dao.TemplateFileRevisions.Ctx(ctx).
+			FieldsEx(dao.TemplateFileRevisions.Columns().FileContent).
+			Where(dao.TemplateFileRevisions.Columns().FileId, fileId).
+			OrderDesc(dao.TemplateFileRevisions.Columns().Revision).
+			Scan(&list)
+		liberr.ErrIsNil(ctx, err, "获取文件修订历史失败")
+
+		revisions = make([]*model.TemplateFileRevisionInfo, 0, len(list))
+		for _, revision := range list {
+			revisions = append(revisions, toRevisionInfo(revision))
+		}
+	})
+	return
+}
+
+// RevisionDetail 获取文件某个修订的完整内容
+func (s sTemplateFiles) RevisionDetail(ctx context.Context, fileId int64, revision int) (res *model.TemplateFileRevisionInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		found, err := s.findRevision(ctx, fileId, revision)
+		liberr.ErrIsNil(ctx, err)
+		res = toRevisionInfo(found)
+	})
+	return
+}
+
+// RevisionDiff 比较文件的两个修订，返回文件内容的统一差异和其他属性的变更说明
+func (s sTemplateFiles) RevisionDiff(ctx context.Context, req *api.TemplateFilesRevisionDiffReq) (res *api.TemplateFilesRevisionDiffRes, err error) {
+	res = new(api.TemplateFilesRevisionDiffRes)
+	err = g.Try(ctx, func(ctx context.Context) {
+		fileId := gconv.Int64(req.Id)
+		from, err := s.findRevision(ctx, fileId, req.From)
+		liberr.ErrIsNil(ctx, err)
+
+		toRevision := req.To
+		if toRevision == 0 {
+			maxRevision, err := dao.TemplateFileRevisions.Ctx(ctx).
+				Where(dao.TemplateFileRevisions.Columns().FileId, fileId).
+				Max(dao.TemplateFileRevisions.Columns().Revision)
+			liberr.ErrIsNil(ctx, err, "获取文件修订历史失败")
+			toRevision = int(maxRevision)
+		}
+		to, err := s.findRevision(ctx, fileId, toRevision)
+		liberr.ErrIsNil(ctx, err)
+
+		res.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(from.FileContent),
+			B:        difflib.SplitLines(to.FileContent),
+			FromFile: fmt.Sprintf("a/%s", from.FilePath),
+			ToFile:   fmt.Sprintf("b/%s", to.FilePath),
+			FromDate: fmt.Sprintf("r%d", from.Revision),
+			ToDate:   fmt.Sprintf("r%d", to.Revision),
+			Context:  3,
+		})
+		liberr.ErrIsNil(ctx, err, "生成修订差异失败")
+
+		res.Changes = revisionChanges(from, to)
+		res.From = toRevisionInfo(from)
+		res.To = toRevisionInfo(to)
+		res.From.FileContent = ""
+		res.To.FileContent = ""
+	})
+	return
+}
+
+// RestoreRevision 将文件恢复到指定修订的内容、位置和生成条件，已删除的文件按原ID重新创建
+func (s sTemplateFiles) RestoreRevision(ctx context.Context, req *api.TemplateFilesRevisionRestoreReq) (res *model.TemplateFileRevisionInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		fileId := gconv.Int64(req.Id)
+		target, err := s.findRevision(ctx, fileId, req.Revision)
+		liberr.ErrIsNil(ctx, err)
+
+		var current *entity.TemplateFiles
+		err = dao.TemplateFiles.Ctx(ctx).WherePri(fileId).Scan(&current)
+		liberr.ErrIsNil(ctx, err, "获取模板文件失败")
+
+		// 原父目录已被删除时无法恢复到原位置
+		if target.ParentId != 0 {
+			count, err := dao.TemplateFiles.Ctx(ctx).
+				Where(dao.TemplateFiles.Columns().Id, target.ParentId).
+				Where(dao.TemplateFiles.Columns().TemplateId, target.TemplateId).
+				Where(dao.TemplateFiles.Columns().IsDirectory, 1).
+				Count()
+			liberr.ErrIsNil(ctx, err, "获取父目录信息失败")
+			if count == 0 {
+				liberr.ErrIsNil(ctx, gerror.New("修订所在的父目录已不存在，请先恢复父目录"))
+			}
+		}
+
+		count, err := dao.TemplateFiles.Ctx(ctx).Where(
+			"template_id = ? AND parent_id = ? AND file_name = ? AND id != ?",
+			target.TemplateId, target.ParentId, target.FileName, fileId,
+		).Count()
+		liberr.ErrIsNil(ctx, err, "检查文件名冲突失败")
+		if count > 0 {
+			liberr.ErrIsNil(ctx, gerror.Newf("同级目录下已存在同名文件 %s", target.FileName))
+		}
+
+		restore := func(ctx context.Context) error {
+			filePath := s.generateFilePath(ctx, target.TemplateId, int(target.ParentId), target.FileName)
+			data := do.TemplateFiles{
+				FilePath:          filePath,
+				FileName:          target.FileName,
+				FileContent:       target.FileContent,
+				FileSize:          target.FileSize,
+				Md5:               target.Md5,
+				ParentId:          target.ParentId,
+				GenerateCondition: target.GenerateCondition,
+				RepeatConfig:      target.RepeatConfig,
+			}
+			if current == nil {
+				data.Id = fileId
+				data.TemplateId = target.TemplateId
+				data.IsDirectory = target.IsDirectory
+				data.Sort = target.Sort
+				_, err := dao.TemplateFiles.Ctx(ctx).Insert(data)
+				return err
+			}
+			if _, err := dao.TemplateFiles.Ctx(ctx).WherePri(fileId).Update(data); err != nil {
+				return err
+			}
+			if current.IsDirectory == 1 && current.FilePath != filePath {
+				return s.updateChildrenPaths(ctx, fileId, current.FilePath, filePath)
+			}
+			return nil
+		}
+		err = s.trackRevisions(ctx, model.FileRevisionActionRestore, []int64{fileId}, restore)
+		liberr.ErrIsNil(ctx, err, "恢复文件修订失败")
+
+		var latest *entity.TemplateFileRevisions
+		err = dao.TemplateFileRevisions.Ctx(ctx).
+			FieldsEx(dao.TemplateFileRevisions.Columns().FileContent).
+			Where(dao.TemplateFileRevisions.Columns().FileId, fileId).
+			OrderDesc(dao.TemplateFileRevisions.Columns().Revision).
+			Limit(1).
+			Scan(&latest)
+		liberr.ErrIsNil(ctx, err, "获取文件修订历史失败")
+		res = toRevisionInfo(latest)
+	})
+	return
+}
+
+// DeletedFiles 获取模板中已删除且尚未恢复的文件，返回每个文件删除前的最后一个修订
+func (s sTemplateFiles) DeletedFiles(ctx context.Context, templateId int64) (files []*model.TemplateFileRevisionInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		var deleted []*entity.TemplateFileRevisions
+		err = dao.TemplateFileRevisions.Ctx(ctx).
+			FieldsEx(dao.TemplateFileRevisions.Columns().FileContent).
+			Where(dao.TemplateFileRevisions.Columns().TemplateId, templateId).
+			Where(dao.TemplateFileRevisions.Columns().Action, model.FileRevisionActionDelete).
+			WhereNotIn(dao.TemplateFileRevisions.Columns().FileId,
+				dao.TemplateFiles.Ctx(ctx).Fields(dao.TemplateFiles.Columns().Id).Where(dao.TemplateFiles.Columns().TemplateId, templateId)).
+			OrderDesc(dao.TemplateFileRevisions.Columns().Id).
+			Scan(&deleted)
+		liberr.ErrIsNil(ctx, err, "获取已删除文件失败")
+
+		// 同一文件可能被恢复后再次删除，只保留最近一次删除
+		seen := make(map[int64]bool)
+		files = make([]*model.TemplateFileRevisionInfo, 0, len(deleted))
+		for _, revision := range deleted {
+			if seen[revision.FileId] {
+				continue
+			}
+			seen[revision.FileId] = true
+			files = append(files, toRevisionInfo(revision))
+		}
+	})
+	return
+}
+
+// findRevision 按修订号查找文件修订
+func (s sTemplateFiles) findRevision(ctx context.Context, fileId int64, revision int) (*entity.TemplateFileRevisions, error) {
+	var found *entity.TemplateFileRevisions
+	err := dao.TemplateFileRevisions.Ctx(ctx).
+		Where(dao.TemplateFileRevisions.Columns().FileId, fileId).
+		Where(dao.TemplateFileRevisions.Columns().Revision, revision).
+		Scan(&found)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取文件修订失败")
+	}
+	if found == nil {
+		return nil, gerror.Newf("文件修订 r%d 不存在", revision)
+	}
+	return found, nil
+}
+
+// revisionChanges 描述两个修订之间除文件内容以外的变更
+func revisionChanges(from, to *entity.TemplateFileRevisions) []string {
+	changes := make([]string, 0)
+	if from.FilePath != to.FilePath {
+		changes = append(changes, fmt.Sprintf("路径: %s -> %s", from.FilePath, to.FilePath))
+	}
+	if from.GenerateCondition != to.GenerateCondition {
+		changes = append(changes, fmt.Sprintf("生成条件: %s -> %s", orNone(from.GenerateCondition), orNone(to.GenerateCondition)))
+	}
+	if from.RepeatConfig != to.RepeatConfig {
+		changes = append(changes, fmt.Sprintf("批量生成配置: %s -> %s", orNone(from.RepeatConfig), orNone(to.RepeatConfig)))
+	}
+	return changes
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "无"
+	}
+	return value
+}
+
+func toRevisionInfo(revision *entity.TemplateFileRevisions) *model.TemplateFileRevisionInfo {
+	info := &model.TemplateFileRevisionInfo{
+		Id:                revision.Id,
+		FileId:            revision.FileId,
+		TemplateId:        revision.TemplateId,
+		Revision:          revision.Revision,
+		Action:            revision.Action,
+		FilePath:          revision.FilePath,
+		FileName:          revision.FileName,
+		FileContent:       revision.FileContent,
+		FileSize:          revision.FileSize,
+		IsDirectory:       revision.IsDirectory,
+		Md5:               revision.Md5,
+		ParentId:          revision.ParentId,
+		GenerateCondition: revision.GenerateCondition,
+		RepeatConfig:      revision.RepeatConfig,
+		AuthorId:          revision.AuthorId,
+		AuthorName:        revision.AuthorName,
+	}
+	if revision.CreatedAt != nil {
+		info.CreatedAt = revision.CreatedAt.Format("Y-m-d H:i:s")
+	}
+	return info
+}
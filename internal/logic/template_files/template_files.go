@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
@@ -22,6 +24,8 @@ import (
 	do "github.com/ciclebyte/template_starter/internal/model/do"
 	"github.com/ciclebyte/template_starter/internal/model/entity"
 	service "github.com/ciclebyte/template_starter/internal/service"
+	libExpr "github.com/ciclebyte/template_starter/library/libExpr"
+	libResponse "github.com/ciclebyte/template_starter/library/libResponse"
 	liberr "github.com/ciclebyte/template_starter/library/liberr"
 	"github.com/gogf/gf/v2/crypto/gmd5"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -69,7 +73,7 @@ func (s sTemplateFiles) Add(ctx context.Context, req *api.TemplateFilesAddReq) (
 		filePath := s.generateFilePath(ctx, req.TemplateId, req.ParentId, req.FileName)
 
 		// add
-		_, err = dao.TemplateFiles.Ctx(ctx).Insert(do.TemplateFiles{
+		id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
 			TemplateId:  req.TemplateId,  // 所属模板ID
 			FilePath:    filePath,        // 文件路径（相对路径）
 			FileName:    req.FileName,    // 文件名
@@ -81,6 +85,9 @@ func (s sTemplateFiles) Add(ctx context.Context, req *api.TemplateFilesAddReq) (
 			ParentId:    req.ParentId,    // 父目录ID，如果是文件则指向所属目录
 		})
 		liberr.ErrIsNil(ctx, err, "新增模板文件失败")
+
+		err = s.recordRevisions(ctx, model.FileRevisionActionCreate, id)
+		liberr.ErrIsNil(ctx, err, "记录文件修订失败")
 	})
 	return
 }
@@ -88,14 +95,24 @@ func (s sTemplateFiles) Add(ctx context.Context, req *api.TemplateFilesAddReq) (
 func (s sTemplateFiles) Edit(ctx context.Context, req *api.TemplateFilesEditReq) (err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
 		// 检查文件是否存在
-		_, err = s.GetById(ctx, gconv.Int64(req.Id))
+		fileInfo, err := s.GetById(ctx, gconv.Int64(req.Id))
 		liberr.ErrIsNil(ctx, err, "获取模板文件失败")
+		liberr.ValueIsNil(fileInfo, "文件不存在")
+
+		// 内容未变化时不产生新的修订
+		md5 := gmd5.MustEncryptString(req.FileContent)
+		if md5 == fileInfo.Md5 && req.FileContent == fileInfo.FileContent {
+			return
+		}
 
 		// 只更新文件内容和相关字段
-		_, err = dao.TemplateFiles.Ctx(ctx).WherePri(req.Id).Update(do.TemplateFiles{
-			FileContent: req.FileContent,                         // 文件内容
-			FileSize:    len(req.FileContent),                    // 重新计算文件大小
-			Md5:         gmd5.MustEncryptString(req.FileContent), // 重新计算MD5
+		err = s.trackRevisions(ctx, model.FileRevisionActionEdit, []int64{fileInfo.Id}, func(ctx context.Context) error {
+			_, err := dao.TemplateFiles.Ctx(ctx).WherePri(fileInfo.Id).Update(do.TemplateFiles{
+				FileContent: req.FileContent,      // 文件内容
+				FileSize:    len(req.FileContent), // 重新计算文件大小
+				Md5:         md5,                  // 重新计算MD5
+			})
+			return err
 		})
 		liberr.ErrIsNil(ctx, err, "修改模板文件失败")
 	})
@@ -131,16 +148,20 @@ func (s sTemplateFiles) Rename(ctx context.Context, req *api.TemplateFilesRename
 		// 生成新的文件路径
 		newFilePath := s.generateFilePath(ctx, fileInfo.TemplateId, fileInfo.ParentId, req.FileName)
 
-		// 如果是目录，需要更新所有子文件的路径
-		if fileInfo.IsDirectory == 1 {
-			err = s.updateChildrenPaths(ctx, fileInfo.Id, fileInfo.FilePath, newFilePath)
-			liberr.ErrIsNil(ctx, err, "更新子文件路径失败")
-		}
+		err = s.trackRevisions(ctx, model.FileRevisionActionRename, []int64{fileInfo.Id}, func(ctx context.Context) error {
+			// 如果是目录，需要更新所有子文件的路径
+			if fileInfo.IsDirectory == 1 {
+				if err := s.updateChildrenPaths(ctx, fileInfo.Id, fileInfo.FilePath, newFilePath); err != nil {
+					return gerror.Wrap(err, "更新子文件路径失败")
+				}
+			}
 
-		// 更新文件名和路径
-		_, err = dao.TemplateFiles.Ctx(ctx).WherePri(req.Id).Update(do.TemplateFiles{
-			FileName: req.FileName,
-			FilePath: newFilePath,
+			// 更新文件名和路径
+			_, err := dao.TemplateFiles.Ctx(ctx).WherePri(fileInfo.Id).Update(do.TemplateFiles{
+				FileName: req.FileName,
+				FilePath: newFilePath,
+			})
+			return err
 		})
 		liberr.ErrIsNil(ctx, err, "重命名文件失败")
 	})
@@ -149,7 +170,10 @@ func (s sTemplateFiles) Rename(ctx context.Context, req *api.TemplateFilesRename
 
 func (s sTemplateFiles) Delete(ctx context.Context, id int64) (err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
-		_, err = dao.TemplateFiles.Ctx(ctx).WherePri(id).Delete()
+		err = s.trackRevisions(ctx, model.FileRevisionActionDelete, []int64{id}, func(ctx context.Context) error {
+			_, err := dao.TemplateFiles.Ctx(ctx).WherePri(id).Delete()
+			return err
+		})
 		liberr.ErrIsNil(ctx, err, "删除模板文件失败")
 	})
 	return
@@ -157,7 +181,10 @@ func (s sTemplateFiles) Delete(ctx context.Context, id int64) (err error) {
 
 func (s sTemplateFiles) BatchDelete(ctx context.Context, ids []int64) (err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
-		_, err = dao.TemplateFiles.Ctx(ctx).Where(dao.TemplateFiles.Columns().Id+" in(?)", ids).Delete()
+		err = s.trackRevisions(ctx, model.FileRevisionActionDelete, ids, func(ctx context.Context) error {
+			_, err := dao.TemplateFiles.Ctx(ctx).Where(dao.TemplateFiles.Columns().Id+" in(?)", ids).Delete()
+			return err
+		})
 		liberr.ErrIsNil(ctx, err, "批量删除模板文件失败")
 	})
 	return
@@ -473,7 +500,7 @@ func (s *sTemplateFiles) createDirectoryRecord(ctx context.Context, templateId i
 	fmt.Printf("创建目录记录: filePath=%s, dirName=%s, parentId=%d\n", filePath, dirName, parentId)
 
 	// 创建目录记录
-	_, err = dao.TemplateFiles.Ctx(ctx).Insert(do.TemplateFiles{
+	id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
 		TemplateId:  templateId,
 		FilePath:    filePath,
 		FileName:    dirName,
@@ -484,7 +511,10 @@ func (s *sTemplateFiles) createDirectoryRecord(ctx context.Context, templateId i
 		Sort:        0,
 		ParentId:    parentId,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	return s.recordRevisions(ctx, model.FileRevisionActionCreate, id)
 }
 
 func (s *sTemplateFiles) createFileRecord(ctx context.Context, templateId int64, filePath, content string) error {
@@ -513,7 +543,7 @@ func (s *sTemplateFiles) createFileRecord(ctx context.Context, templateId int64,
 	md5 := gmd5.MustEncrypt(content)
 
 	// 创建文件记录
-	_, err = dao.TemplateFiles.Ctx(ctx).Insert(do.TemplateFiles{
+	id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
 		TemplateId:  templateId,
 		FilePath:    filePath,
 		FileName:    fileName,
@@ -524,7 +554,10 @@ func (s *sTemplateFiles) createFileRecord(ctx context.Context, templateId int64,
 		Sort:        0,
 		ParentId:    parentId,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	return s.recordRevisions(ctx, model.FileRevisionActionCreate, id)
 }
 
 func (s *sTemplateFiles) getParentId(ctx context.Context, templateId int64, filePath string) int64 {
@@ -565,7 +598,8 @@ func (s *sTemplateFiles) generateFilePath(ctx context.Context, templateId interf
 	return filepath.Join(parent.FilePath, fileName)
 }
 
-// updateChildrenPaths 更新目录下所有子文件的路径
+// updateChildrenPaths 更新目录下所有子文件的路径，路径变化的子文件各记录一条 move 修订。
+// 需要在修改父目录的 trackRevisions 事务中调用
 func (s *sTemplateFiles) updateChildrenPaths(ctx context.Context, parentId int64, oldParentPath, newParentPath string) error {
 	// 获取所有子文件
 	var children []*entity.TemplateFiles
@@ -583,12 +617,20 @@ func (s *sTemplateFiles) updateChildrenPaths(ctx context.Context, parentId int64
 			newChildPath = filepath.Join(newParentPath, child.FileName)
 		}
 
-		// 更新子文件的路径
-		_, err = dao.TemplateFiles.Ctx(ctx).WherePri(child.Id).Update(do.TemplateFiles{
-			FilePath: newChildPath,
-		})
-		if err != nil {
-			return err
+		// 更新子文件的路径，修改前为没有修订的子文件补基线修订
+		if newChildPath != child.FilePath {
+			if err = s.saveBaselineRevisions(ctx, []int64{child.Id}); err != nil {
+				return err
+			}
+			_, err = dao.TemplateFiles.Ctx(ctx).WherePri(child.Id).Update(do.TemplateFiles{
+				FilePath: newChildPath,
+			})
+			if err != nil {
+				return err
+			}
+			if err = s.recordRevisions(ctx, model.FileRevisionActionMove, child.Id); err != nil {
+				return err
+			}
 		}
 
 		// 如果子文件也是目录，递归更新其子文件
@@ -664,7 +706,7 @@ func (s *sTemplateFiles) UploadCode(ctx context.Context, req *api.TemplateFilesU
 		md5 := gmd5.MustEncrypt(fileContent)
 
 		// 创建文件记录
-		_, err = dao.TemplateFiles.Ctx(ctx).Insert(do.TemplateFiles{
+		id, err := dao.TemplateFiles.Ctx(ctx).InsertAndGetId(do.TemplateFiles{
 			TemplateId:  req.TemplateId,
 			FilePath:    filePath,
 			FileName:    fileName,
@@ -677,6 +719,9 @@ func (s *sTemplateFiles) UploadCode(ctx context.Context, req *api.TemplateFilesU
 		})
 		liberr.ErrIsNil(ctx, err, "保存文件记录失败")
 
+		err = s.recordRevisions(ctx, model.FileRevisionActionCreate, id)
+		liberr.ErrIsNil(ctx, err, "记录文件修订失败")
+
 		// 设置返回结果
 		res.FileName = fileName
 		res.FileSize = len(fileContent)
@@ -723,8 +768,19 @@ func (s sTemplateFiles) isTextFile(filename string, content []byte) bool {
 	return float64(printableCount)/float64(totalCount) > 0.9
 }
 
-// 获取模板函数映射
-func (s sTemplateFiles) getTemplateFuncs() template.FuncMap {
+// fixLegacyVariableSyntax 兼容旧版变量写法，将 {{/var}} 转换为 {{.var}}，使用模板自定义的定界符
+func (s sTemplateFiles) fixLegacyVariableSyntax(content string, delims *model.TemplateDelimiters) string {
+	return strings.ReplaceAll(content, delims.Left+"/", delims.Left+".")
+}
+
+// TemplateFuncMap 返回渲染使用的完整函数映射，供变量分析等只需解析模板的场景使用，
+// 其中的文件操作函数不可调用
+func (s sTemplateFiles) TemplateFuncMap() template.FuncMap {
+	return s.getTemplateFuncs(nil, "")
+}
+
+// 获取模板函数映射，fsys 和 currentPath 供文件操作类内置函数访问当前模板内的文件
+func (s sTemplateFiles) getTemplateFuncs(fsys fs.FS, currentPath string) template.FuncMap {
 	funcs := sprig.FuncMap()
 
 	// 添加自定义函数
@@ -805,7 +861,7 @@ func (s sTemplateFiles) getTemplateFuncs() template.FuncMap {
 	}
 
 	// 合并内置函数
-	builtinFuncs := service.BuiltinFunctions().GetTemplateFuncMap()
+	builtinFuncs := service.BuiltinFunctions().GetTemplateFuncMap(fsys, currentPath)
 	for name, fn := range builtinFuncs {
 		funcs[name] = fn
 	}
@@ -827,9 +883,22 @@ func (s sTemplateFiles) parseTemplateError(err error, templateContent string) *a
 		Column:  0,
 	}
 
+	// 超出渲染沙箱限制
+	if limitErr, ok := asRenderLimitError(err); ok {
+		templateError.Type = "limit_exceeded"
+		templateError.Message = limitErr.Message
+		if matches := regexp.MustCompile(`template: [^:\s]+:(\d+):(\d+):`).FindStringSubmatch(errorMessage); len(matches) > 2 {
+			templateError.Line, _ = strconv.Atoi(matches[1])
+			templateError.Column, _ = strconv.Atoi(matches[2])
+			templateError.Context = s.getErrorContext(templateContent, templateError.Line)
+		}
+		templateError.Suggestion = "模板渲染超出了服务器限制，请检查是否存在过大的循环或输出，必要时缩小数据规模"
+		return templateError
+	}
+
 	// 解析模板解析错误
 	// 示例: template: template:5: function "package_info" not defined
-	parseErrorRegex := regexp.MustCompile(`template: template:(\d+):(.*)`)
+	parseErrorRegex := regexp.MustCompile(`template: [^:\s]+:(\d+): (.*)`)
 	if matches := parseErrorRegex.FindStringSubmatch(errorMessage); len(matches) > 2 {
 		templateError.Type = "parse_error"
 		if line, err := strconv.Atoi(matches[1]); err == nil {
@@ -847,7 +916,7 @@ func (s sTemplateFiles) parseTemplateError(err error, templateContent string) *a
 
 	// 解析执行错误
 	// 示例: template: template:5:10: executing "template" at <.SomeVar>: map has no entry for key "SomeVar"
-	executeErrorRegex := regexp.MustCompile(`template: template:(\d+):(\d+):(.*)`)
+	executeErrorRegex := regexp.MustCompile(`template: [^:\s]+:(\d+):(\d+):(.*)`)
 	if matches := executeErrorRegex.FindStringSubmatch(errorMessage); len(matches) > 3 {
 		templateError.Type = "execute_error"
 		if line, err := strconv.Atoi(matches[1]); err == nil {
@@ -947,31 +1016,57 @@ func (s sTemplateFiles) Render(ctx context.Context, req *api.TemplateFilesRender
 		return nil, gerror.Wrap(err, "获取文件信息失败")
 	}
 
-	// 3. 转换变量类型
-	convertedVariables, err := s.convertVariableTypes(ctx, fileInfo.TemplateId, req.Variables)
-	if err != nil {
-		return nil, gerror.Wrap(err, "转换变量类型失败")
-	}
-
 	// 初始化响应结构
 	res = &api.TemplateFilesRenderRes{
 		FileId:    gconv.Int64(req.FileId),
 		FileName:  fileInfo.FileName,
-		Variables: convertedVariables,
+		Variables: req.Variables,
 		Success:   false,
 	}
 
-	// 4. 创建模板
-	tmpl, err := template.New("template").Funcs(s.getTemplateFuncs()).Parse(fileContent)
+	// 3. 加载模板工作区内容，按变量定义转换变量类型
+	src, err := s.loadRenderSource(ctx, fileInfo.TemplateId, model.ReleaseVersionDraft)
+	if err != nil {
+		return nil, err
+	}
+	convertedVariables, err := s.convertVariableTypes(ctx, src, req.Variables)
+	if validationErr, ok := asVariableValidationError(err); ok {
+		res.Error = s.newVariableRenderError(validationErr)
+		res.ValidationErrors = validationErr.Errors
+		return res, nil
+	}
+	if err != nil {
+		return nil, gerror.Wrap(err, "转换变量类型失败")
+	}
+
+	// 批量生成的文件以数组的第一个元素预览
+	if config := s.parseRepeatConfig(fileInfo.RepeatConfig); config != nil {
+		if items, err := s.resolveRepeatItems(config, convertedVariables); err == nil && len(items) > 0 {
+			convertedVariables = s.repeatScope(convertedVariables, config, items[0], 0)
+		}
+	}
+	res.Variables = convertedVariables
+
+	// 4. 构建模板虚拟文件系统和公共片段，供 readFile 等函数和 {{template}} 使用
+	sandbox, cancel := newRenderSandbox(ctx)
+	defer cancel()
+	run := sandbox.Begin(fileInfo.FilePath)
+	defer run.Close()
+	funcs := run.Funcs(s.getTemplateFuncs(newTemplateFS(src.Files), fileInfo.FilePath))
+	_, partialFiles := splitPartialFiles(src.Files)
+	delims := src.Delims
+	partials, _ := s.parsePartials(partialFiles, delims, funcs)
+
+	// 5. 创建模板
+	tmpl, err := s.newContentTemplate(partials, "template", delims, funcs).Parse(s.fixLegacyVariableSyntax(fileContent, delims))
 	if err != nil {
 		// 解析错误，返回详细错误信息
 		res.Error = s.parseTemplateError(err, fileContent)
 		return res, nil
 	}
 
-	// 5. 渲染模板
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, convertedVariables)
+	// 6. 在沙箱中渲染模板
+	content, err := run.Execute(tmpl, convertedVariables)
 	if err != nil {
 		// 执行错误，返回详细错误信息
 		res.Error = s.parseTemplateError(err, fileContent)
@@ -980,30 +1075,18 @@ func (s sTemplateFiles) Render(ctx context.Context, req *api.TemplateFilesRender
 
 	// 渲染成功
 	res.Success = true
-	res.FileContent = buf.String()
+	res.FileContent = content
 	return res, nil
 }
 
 // RenderFileTree 渲染整个文件树
-// renderTemplateFiles 通用模板文件渲染函数
-func (s sTemplateFiles) renderTemplateFiles(ctx context.Context, templateId int64, variables map[string]interface{}) ([]*api.RenderFileInfo, error) {
-	// 1. 转换变量类型
-	convertedVariables, err := s.convertVariableTypes(ctx, templateId, variables)
-	if err != nil {
-		return nil, err
-	}
-
-	// 2. 获取模板下的所有文件
-	var files []*entity.TemplateFiles
-	err = dao.TemplateFiles.Ctx(ctx).Where("template_id = ?", templateId).Scan(&files)
-	if err != nil {
-		return nil, err
-	}
-
-	// 3. 渲染并重建文件树
-	renderedFiles := s.renderAndRebuildTree(files, convertedVariables)
-
-	return renderedFiles, nil
+// renderTemplateFiles 通用模板文件渲染函数，variables 为 convertVariableTypes 转换后的变量，
+// 返回渲染结果和各文件的渲染诊断信息
+func (s sTemplateFiles) renderTemplateFiles(ctx context.Context, src *renderSource, variables map[string]interface{}) ([]*api.RenderFileInfo, []*api.RenderFileDiagnostic, error) {
+	// 在沙箱中渲染并重建文件树
+	sandbox, cancel := newRenderSandbox(ctx)
+	defer cancel()
+	return s.renderAndRebuildTree(sandbox, src.Files, src.Delims, variables)
 }
 
 func (s sTemplateFiles) RenderFileTree(ctx context.Context, req *api.TemplateFilesRenderFileTreeReq) (res *api.TemplateFilesRenderFileTreeRes, err error) {
@@ -1011,16 +1094,42 @@ func (s sTemplateFiles) RenderFileTree(ctx context.Context, req *api.TemplateFil
 		templateId := gconv.Int64(req.TemplateId)
 
 		res = &api.TemplateFilesRenderFileTreeRes{
-			TemplateId: templateId,
-			Tree:       []*api.RenderFileInfo{},
-			Variables:  req.Variables,
-			TotalFiles: 0,
-			TotalSize:  0,
-		}
+			TemplateId:  templateId,
+			Tree:        []*api.RenderFileInfo{},
+			Variables:   req.Variables,
+			TotalFiles:  0,
+			TotalSize:   0,
+			Diagnostics: []*api.RenderFileDiagnostic{},
+		}
+
+		// 加载要渲染的版本，默认为最新发布版本
+		src, err := s.loadRenderSource(ctx, templateId, req.Version)
+		liberr.ErrIsNil(ctx, err)
+		res.Version = src.Version()
+		res.Deprecated = src.Release != nil && src.Release.Status == model.ReleaseStatusDeprecated
+
+		// 按变量定义转换变量类型并计算计算变量
+		variables, err := s.convertVariableTypes(ctx, src, req.Variables)
+		if validationErr, ok := asVariableValidationError(err); ok {
+			// 变量不符合定义时返回各字段的校验错误
+			res.Error = s.newVariableRenderError(validationErr)
+			res.ValidationErrors = validationErr.Errors
+			return
+		}
+		liberr.ErrIsNil(ctx, err, "转换变量类型失败")
+		res.Variables = variables
 
 		// 使用通用渲染函数
-		renderedFiles, err := s.renderTemplateFiles(ctx, templateId, req.Variables)
+		renderedFiles, diagnostics, err := s.renderTemplateFiles(ctx, src, variables)
+		if _, ok := asRenderLimitError(err); ok {
+			// 超出渲染限制时返回结构化错误，不返回部分结果
+			res.Error = s.parseTemplateError(err, "")
+			return
+		}
 		liberr.ErrIsNil(ctx, err, "渲染模板文件失败")
+		if len(diagnostics) > 0 {
+			res.Diagnostics = diagnostics
+		}
 
 		// 3. 构建树形结构
 		res.Tree = s.buildTree(renderedFiles)
@@ -1034,16 +1143,29 @@ func (s sTemplateFiles) RenderFileTree(ctx context.Context, req *api.TemplateFil
 	return
 }
 
-// renderAndRebuildTree 渲染文件并重建树结构
-func (s sTemplateFiles) renderAndRebuildTree(files []*entity.TemplateFiles, variables map[string]interface{}) []*api.RenderFileInfo {
+// renderAndRebuildTree 渲染文件并重建树结构，文件名、路径或内容渲染失败时保留原始内容并记录诊断信息，
+// 超出沙箱限制时中止并返回 renderLimitError
+func (s sTemplateFiles) renderAndRebuildTree(sandbox *renderSandbox, files []*entity.TemplateFiles, delims *model.TemplateDelimiters, variables map[string]interface{}) ([]*api.RenderFileInfo, []*api.RenderFileDiagnostic, error) {
 	fmt.Printf("=== 开始渲染和重建树形结构 ===\n")
 	fmt.Printf("总文件数: %d\n", len(files))
 	fmt.Printf("变量数据: %+v\n", variables)
 
+	// 模板虚拟文件系统包含全部文件的原始内容，不受条件过滤影响
+	vfs := newTemplateFS(files)
+
+	// 公共片段只提供 {{define}} 定义，不参与输出
+	regularFiles, partialFiles := splitPartialFiles(files)
+	partials, diagnostics := s.parsePartials(partialFiles, delims, s.getTemplateFuncs(vfs, ""))
+	fmt.Printf("公共片段文件数: %d\n", len(partialFiles))
+
 	// 第一步：根据条件过滤文件
-	filteredFiles := s.filterFilesByCondition(files, variables)
+	filteredFiles := s.filterFilesByCondition(regularFiles, variables)
 	fmt.Printf("条件过滤后文件数: %d\n", len(filteredFiles))
 
+	// 按批量生成配置展开为渲染单元
+	units := s.expandRepeatFiles(filteredFiles, variables)
+	fmt.Printf("批量展开后文件数: %d\n", len(units))
+
 	var result []*api.RenderFileInfo
 	var nextId int64 = 10000
 	pathToNode := make(map[string]*api.RenderFileInfo)
@@ -1051,21 +1173,25 @@ func (s sTemplateFiles) renderAndRebuildTree(files []*entity.TemplateFiles, vari
 
 	// 第二步：处理过滤后的文件，渲染并创建基础节点
 	fmt.Printf("\n=== 第二步：处理过滤后的文件 ===\n")
-	for i, file := range filteredFiles {
-		fmt.Printf("\n--- 处理文件 %d/%d ---\n", i+1, len(filteredFiles))
+	for i, unit := range units {
+		file := unit.file
+		if err := sandbox.Err(); err != nil {
+			return nil, nil, err
+		}
+		run := sandbox.Begin(file.FilePath)
+		funcs := run.Funcs(s.getTemplateFuncs(vfs, file.FilePath))
+		fmt.Printf("\n--- 处理文件 %d/%d ---\n", i+1, len(units))
 		fmt.Printf("原始数据: ID=%d, 名称=%s, 路径=%s, 是否目录=%d, 父ID=%d\n",
 			file.Id, file.FileName, file.FilePath, file.IsDirectory, file.ParentId)
 
 		// 修复变量格式：将 {{/var}} 转换为 {{.var}}
-		fileNameToRender := file.FileName
-		filePathToRender := file.FilePath
+		fileNameToRender := s.fixLegacyVariableSyntax(file.FileName, delims)
+		filePathToRender := s.fixLegacyVariableSyntax(file.FilePath, delims)
 
-		if strings.Contains(fileNameToRender, "{{/") {
-			fileNameToRender = strings.ReplaceAll(fileNameToRender, "{{/", "{{.")
+		if fileNameToRender != file.FileName {
 			fmt.Printf("  修复文件名变量格式: %s\n", fileNameToRender)
 		}
-		if strings.Contains(filePathToRender, "{{/") {
-			filePathToRender = strings.ReplaceAll(filePathToRender, "{{/", "{{.")
+		if filePathToRender != file.FilePath {
 			fmt.Printf("  修复文件路径变量格式: %s\n", filePathToRender)
 		}
 
@@ -1074,48 +1200,62 @@ func (s sTemplateFiles) renderAndRebuildTree(files []*entity.TemplateFiles, vari
 		renderedPath := file.FilePath
 
 		if fileNameToRender != "" {
-			if tmpl, err := template.New("fileName").Funcs(s.getTemplateFuncs()).Parse(fileNameToRender); err == nil {
-				var buf bytes.Buffer
-				if tmpl.Execute(&buf, variables) == nil {
-					renderedName = buf.String()
+			if tmpl, err := template.New("fileName").Delims(delims.Left, delims.Right).Funcs(funcs).Parse(fileNameToRender); err == nil {
+				if output, err := run.Execute(tmpl, unit.variables); err == nil {
+					renderedName = output
 					fmt.Printf("  文件名渲染: %s -> %s\n", fileNameToRender, renderedName)
+				} else if _, ok := asRenderLimitError(err); ok {
+					run.Close()
+					return nil, nil, err
 				} else {
 					fmt.Printf("  文件名渲染失败: %v\n", err)
+					diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileName", fileNameToRender, err))
 				}
 			} else {
 				fmt.Printf("  文件名模板解析失败: %v\n", err)
+				diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileName", fileNameToRender, err))
 			}
 		}
 
 		if filePathToRender != "" {
-			if tmpl, err := template.New("filePath").Funcs(s.getTemplateFuncs()).Parse(filePathToRender); err == nil {
-				var buf bytes.Buffer
-				if tmpl.Execute(&buf, variables) == nil {
-					renderedPath = buf.String()
+			if tmpl, err := template.New("filePath").Delims(delims.Left, delims.Right).Funcs(funcs).Parse(filePathToRender); err == nil {
+				if output, err := run.Execute(tmpl, unit.variables); err == nil {
+					renderedPath = output
 					fmt.Printf("  文件路径渲染: %s -> %s\n", filePathToRender, renderedPath)
+				} else if _, ok := asRenderLimitError(err); ok {
+					run.Close()
+					return nil, nil, err
 				} else {
 					fmt.Printf("  文件路径渲染失败: %v\n", err)
+					diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "filePath", filePathToRender, err))
 				}
 			} else {
 				fmt.Printf("  文件路径模板解析失败: %v\n", err)
+				diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "filePath", filePathToRender, err))
 			}
 		}
 
 		// 渲染文件内容
 		renderedContent := file.FileContent
 		if file.IsDirectory == 0 && file.FileContent != "" {
-			contentToRender := file.FileContent
-			if strings.Contains(contentToRender, "{{/") {
-				contentToRender = strings.ReplaceAll(contentToRender, "{{/", "{{.")
-			}
-
-			if tmpl, err := template.New("fileContent").Funcs(s.getTemplateFuncs()).Parse(contentToRender); err == nil {
-				var buf bytes.Buffer
-				if tmpl.Execute(&buf, variables) == nil {
-					renderedContent = buf.String()
+			contentToRender := s.fixLegacyVariableSyntax(file.FileContent, delims)
+
+			if tmpl, err := s.newContentTemplate(partials, "fileContent", delims, funcs).Parse(contentToRender); err == nil {
+				if output, err := run.Execute(tmpl, unit.variables); err == nil {
+					renderedContent = output
+				} else if _, ok := asRenderLimitError(err); ok {
+					run.Close()
+					return nil, nil, err
+				} else {
+					fmt.Printf("  文件内容渲染失败: %v\n", err)
+					diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileContent", contentToRender, err))
 				}
+			} else {
+				fmt.Printf("  文件内容模板解析失败: %v\n", err)
+				diagnostics = append(diagnostics, s.newRenderDiagnostic(file, "fileContent", contentToRender, err))
 			}
 		}
+		run.Close()
 
 		// 检查是否需要分割目录名
 		fmt.Printf("  检查是否需要分割目录名: 是否目录=%d, 渲染后名称=%s, 包含点号=%t\n",
@@ -1289,7 +1429,17 @@ func (s sTemplateFiles) renderAndRebuildTree(files []*entity.TemplateFiles, vari
 	}
 
 	fmt.Printf("\n=== 渲染和重建树形结构完成 ===\n")
-	return result
+	return result, diagnostics, nil
+}
+
+// newRenderDiagnostic 为渲染失败的文件生成诊断信息
+func (s sTemplateFiles) newRenderDiagnostic(file *entity.TemplateFiles, part, source string, err error) *api.RenderFileDiagnostic {
+	return &api.RenderFileDiagnostic{
+		FileId:   file.Id,
+		FilePath: file.FilePath,
+		Part:     part,
+		Error:    s.parseTemplateError(err, source),
+	}
 }
 
 // DownloadZip 下载ZIP包
@@ -1302,14 +1452,44 @@ func (s sTemplateFiles) DownloadZip(ctx context.Context, req *api.TemplateFilesD
 		err := dao.Templates.Ctx(ctx).Where("id = ?", templateId).Scan(&templateInfo)
 		liberr.ErrIsNil(ctx, err, "获取模板信息失败")
 
-		// 2. 使用通用渲染函数获取渲染后的文件
-		renderedFiles, err := s.renderTemplateFiles(ctx, templateId, req.Variables)
+		// 2. 加载要渲染的版本，默认为最新发布版本，再按变量定义转换变量类型并计算计算变量
+		src, err := s.loadRenderSource(ctx, templateId, req.Version)
+		liberr.ErrIsNil(ctx, err)
+		variables, err := s.convertVariableTypes(ctx, src, req.Variables)
+		if validationErr, ok := asVariableValidationError(err); ok {
+			// 变量不符合定义时不生成ZIP，以JSON返回各字段的校验错误
+			libResponse.RJson(g.RequestFromCtx(ctx), libResponse.ErrorCode, validationErr.Error(), g.Map{
+				"error":            s.newVariableRenderError(validationErr),
+				"validationErrors": validationErr.Errors,
+			})
+			return
+		}
+		liberr.ErrIsNil(ctx, err, "转换变量类型失败")
+
+		// 使用通用渲染函数获取渲染后的文件
+		renderedFiles, diagnostics, err := s.renderTemplateFiles(ctx, src, variables)
+		if _, ok := asRenderLimitError(err); ok {
+			// 超出渲染限制时不生成ZIP，以JSON返回结构化错误
+			renderError := s.parseTemplateError(err, "")
+			libResponse.RJson(g.RequestFromCtx(ctx), libResponse.ErrorCode, renderError.Message, g.Map{"error": renderError})
+			return
+		}
 		liberr.ErrIsNil(ctx, err, "渲染模板文件失败")
 
+		// 严格模式下任一文件渲染失败都不生成ZIP，以JSON返回诊断信息
+		if req.Strict && len(diagnostics) > 0 {
+			message := fmt.Sprintf("%d 处模板渲染失败，已取消下载", len(diagnostics))
+			libResponse.RJson(g.RequestFromCtx(ctx), libResponse.ErrorCode, message, g.Map{"diagnostics": diagnostics})
+			return
+		}
+
 		// 3. 确定ZIP文件名
 		zipFileName := req.FileName
 		if zipFileName == "" {
 			zipFileName = templateInfo.Name
+			if src.Release != nil {
+				zipFileName += "-" + src.Release.Version
+			}
 		}
 		if !strings.HasSuffix(zipFileName, ".zip") {
 			zipFileName += ".zip"
@@ -1536,45 +1716,47 @@ func (s *sTemplateFiles) Move(ctx context.Context, req *api.TemplateFilesMoveReq
 		}
 	}
 
-	// 4. 更新文件的父目录ID
-	_, err = dao.TemplateFiles.Ctx(ctx).
-		Where(dao.TemplateFiles.Columns().Id, fileId).
-		Update(g.Map{
-			dao.TemplateFiles.Columns().ParentId:  newParentId,
-			dao.TemplateFiles.Columns().UpdatedAt: gtime.Now(),
-		})
+	return s.trackRevisions(ctx, model.FileRevisionActionMove, []int64{fileId}, func(ctx context.Context) error {
+		// 4. 更新文件的父目录ID
+		_, err := dao.TemplateFiles.Ctx(ctx).
+			Where(dao.TemplateFiles.Columns().Id, fileId).
+			Update(g.Map{
+				dao.TemplateFiles.Columns().ParentId:  newParentId,
+				dao.TemplateFiles.Columns().UpdatedAt: gtime.Now(),
+			})
 
-	if err != nil {
-		return gerror.Wrap(err, "移动文件失败")
-	}
+		if err != nil {
+			return gerror.Wrap(err, "移动文件失败")
+		}
 
-	// 5. 重新生成文件路径
-	newFilePath, err := s.buildFilePath(ctx, fileId, gconv.Int64(fileInfo["template_id"]))
-	if err != nil {
-		return gerror.Wrap(err, "生成新文件路径失败")
-	}
+		// 5. 重新生成文件路径
+		newFilePath, err := s.buildFilePath(ctx, fileId, gconv.Int64(fileInfo["template_id"]))
+		if err != nil {
+			return gerror.Wrap(err, "生成新文件路径失败")
+		}
 
-	// 6. 更新文件路径
-	_, err = dao.TemplateFiles.Ctx(ctx).
-		Where(dao.TemplateFiles.Columns().Id, fileId).
-		Update(g.Map{
-			dao.TemplateFiles.Columns().FilePath:  newFilePath,
-			dao.TemplateFiles.Columns().UpdatedAt: gtime.Now(),
-		})
+		// 6. 更新文件路径
+		_, err = dao.TemplateFiles.Ctx(ctx).
+			Where(dao.TemplateFiles.Columns().Id, fileId).
+			Update(g.Map{
+				dao.TemplateFiles.Columns().FilePath:  newFilePath,
+				dao.TemplateFiles.Columns().UpdatedAt: gtime.Now(),
+			})
 
-	if err != nil {
-		return gerror.Wrap(err, "更新文件路径失败")
-	}
+		if err != nil {
+			return gerror.Wrap(err, "更新文件路径失败")
+		}
 
-	// 7. 如果移动的是目录，需要更新其所有子项的文件路径
-	if fileInfo["is_directory"].Int() == 1 {
-		oldFilePath := fileInfo["file_path"].String()
-		if err := s.updateChildrenPaths(ctx, fileId, oldFilePath, newFilePath); err != nil {
-			return gerror.Wrap(err, "更新子项路径失败")
+		// 7. 如果移动的是目录，需要更新其所有子项的文件路径
+		if fileInfo["is_directory"].Int() == 1 {
+			oldFilePath := fileInfo["file_path"].String()
+			if err := s.updateChildrenPaths(ctx, fileId, oldFilePath, newFilePath); err != nil {
+				return gerror.Wrap(err, "更新子项路径失败")
+			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 // 验证不能移动到自己的子目录
@@ -1669,12 +1851,28 @@ func (s *sTemplateFiles) SetCondition(ctx context.Context, req *api.TemplateFile
 		// 构造生成条件JSON
 		var conditionJson string
 		if req.Enabled {
+			expression := strings.TrimSpace(req.Expression)
+			variableNames := []string{req.VariableName}
+			if expression != "" {
+				// 解析并校验条件表达式
+				program, err := libExpr.Compile(expression)
+				if err != nil {
+					liberr.ErrIsNil(ctx, gerror.Wrap(err, "条件表达式语法错误"))
+				}
+				variableNames = program.Variables()
+			} else if req.VariableName == "" {
+				liberr.ErrIsNil(ctx, fmt.Errorf("关联变量名不能为空"), "请填写关联变量名或条件表达式")
+			}
+
 			// 检查变量是否存在
-			err = s.checkVariableExists(ctx, fileId, req.VariableName)
-			liberr.ErrIsNil(ctx, err, "关联的变量不存在")
+			for _, variableName := range variableNames {
+				err = s.checkVariableExists(ctx, fileId, variableName)
+				liberr.ErrIsNil(ctx, err, "关联的变量不存在")
+			}
 
 			condition := model.GenerateCondition{
 				Enabled:       req.Enabled,
+				Expression:    expression,
 				VariableName:  req.VariableName,
 				ExpectedValue: req.ExpectedValue,
 				Description:   req.Description,
@@ -1689,8 +1887,11 @@ func (s *sTemplateFiles) SetCondition(ctx context.Context, req *api.TemplateFile
 		}
 
 		// 更新数据库
-		_, err = dao.TemplateFiles.Ctx(ctx).WherePri(fileId).Update(do.TemplateFiles{
-			GenerateCondition: conditionJson,
+		err = s.trackRevisions(ctx, model.FileRevisionActionCondition, []int64{fileId}, func(ctx context.Context) error {
+			_, err := dao.TemplateFiles.Ctx(ctx).WherePri(fileId).Update(do.TemplateFiles{
+				GenerateCondition: conditionJson,
+			})
+			return err
 		})
 		liberr.ErrIsNil(ctx, err, "设置生成条件失败")
 	})
@@ -1719,6 +1920,13 @@ func (s *sTemplateFiles) GetCondition(ctx context.Context, req *api.TemplateFile
 			err = json.Unmarshal([]byte(fileInfo.GenerateCondition), &condition)
 			if err == nil {
 				res.Enabled = condition.Enabled
+				res.Expression = condition.Expression
+				res.Variables = []string{condition.VariableName}
+				if condition.Expression != "" {
+					if program, err := libExpr.Compile(condition.Expression); err == nil {
+						res.Variables = program.Variables()
+					}
+				}
 				res.VariableName = condition.VariableName
 				res.ExpectedValue = condition.ExpectedValue
 				res.Description = condition.Description
@@ -1741,16 +1949,32 @@ func (s *sTemplateFiles) checkVariableExists(ctx context.Context, fileId int64,
 	return nil
 }
 
-// convertVariableTypes 根据变量定义将变量值转换为正确的数据类型
-func (s sTemplateFiles) convertVariableTypes(ctx context.Context, templateId int64, variables map[string]interface{}) (map[string]interface{}, error) {
-	// 模板变量功能已移除，直接返回原始变量
-	return variables, nil
+// convertVariableTypes 根据模板变量定义补全默认值、转换类型并校验约束，再按依赖顺序计算计算变量，
+// 校验或计算不通过时返回 variableValidationError
+func (s sTemplateFiles) convertVariableTypes(ctx context.Context, src *renderSource, variables map[string]interface{}) (map[string]interface{}, error) {
+	defs := src.Variables
+	if len(defs) == 0 {
+		return variables, nil
+	}
+	converted, fieldErrors := coerceVariables(defs, variables)
+	if len(fieldErrors) > 0 {
+		return nil, &variableValidationError{Errors: fieldErrors}
+	}
+	fieldErrors, err := s.computeVariables(ctx, src.Delims, defs, converted)
+	if err != nil {
+		return nil, err
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &variableValidationError{Errors: fieldErrors}
+	}
+	return converted, nil
 }
 
 // filterFilesByCondition 根据生成条件过滤文件
 func (s sTemplateFiles) filterFilesByCondition(files []*entity.TemplateFiles, variables map[string]interface{}) []*entity.TemplateFiles {
 	var result []*entity.TemplateFiles
-	skippedPaths := make(map[string]bool) // 记录被跳过的路径
+	skippedPaths := make(map[string]bool)         // 记录被跳过的路径
+	programs := make(map[string]*libExpr.Program) // 按表达式缓存编译结果
 
 	// 按路径排序，确保父目录在子目录之前处理
 	sort.Slice(files, func(i, j int) bool {
@@ -1766,7 +1990,7 @@ func (s sTemplateFiles) filterFilesByCondition(files []*entity.TemplateFiles, va
 		}
 
 		// 检查文件自身的生成条件
-		if s.shouldGenerateFile(file, variables) {
+		if s.shouldGenerateFile(file, variables, programs) {
 			result = append(result, file)
 			fmt.Printf("文件 %s 通过条件检查，将被生成\n", file.FilePath)
 		} else {
@@ -1779,7 +2003,7 @@ func (s sTemplateFiles) filterFilesByCondition(files []*entity.TemplateFiles, va
 }
 
 // shouldGenerateFile 检查文件是否应该生成
-func (s sTemplateFiles) shouldGenerateFile(file *entity.TemplateFiles, variables map[string]interface{}) bool {
+func (s sTemplateFiles) shouldGenerateFile(file *entity.TemplateFiles, variables map[string]interface{}, programs map[string]*libExpr.Program) bool {
 	// 没有生成条件的文件默认生成
 	if file.GenerateCondition == "" {
 		return true
@@ -1797,6 +2021,24 @@ func (s sTemplateFiles) shouldGenerateFile(file *entity.TemplateFiles, variables
 		return true
 	}
 
+	// 表达式条件
+	if condition.Expression != "" {
+		program, ok := programs[condition.Expression]
+		if !ok {
+			var err error
+			program, err = libExpr.Compile(condition.Expression)
+			if err != nil {
+				return true // 解析失败时默认生成，错误由模板检查报告
+			}
+			programs[condition.Expression] = program
+		}
+		shouldGenerate, err := program.EvalBool(variables)
+		if err != nil {
+			return true
+		}
+		return shouldGenerate
+	}
+
 	// 获取变量值
 	variableValue, exists := variables[condition.VariableName]
 	if !exists {
@@ -1837,3 +2079,173 @@ func (s sTemplateFiles) isParentPathSkipped(filePath string, skippedPaths map[st
 	}
 	return false
 }
+
+// repeatAliasRegex 批量生成别名必须是合法的模板变量名
+var repeatAliasRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SetRepeat 设置文件批量生成配置
+func (s *sTemplateFiles) SetRepeat(ctx context.Context, req *api.TemplateFilesSetRepeatReq) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		fileId := gconv.Int64(req.Id)
+
+		// 检查文件是否存在
+		fileInfo, err := s.GetById(ctx, fileId)
+		liberr.ErrIsNil(ctx, err, "文件不存在")
+		liberr.ValueIsNil(fileInfo, "文件不存在")
+
+		var repeatJson string
+		if req.Enabled {
+			if fileInfo.IsDirectory == 1 {
+				liberr.ErrIsNil(ctx, fmt.Errorf("目录不支持批量生成"), "目录不支持批量生成")
+			}
+
+			over := strings.TrimSpace(req.Over)
+			if over == "" {
+				liberr.ErrIsNil(ctx, fmt.Errorf("数组变量不能为空"), "请填写要遍历的数组变量")
+			}
+			program, err := libExpr.Compile(over)
+			if err != nil {
+				liberr.ErrIsNil(ctx, gerror.Wrap(err, "数组变量路径格式错误"))
+			}
+			for _, variableName := range program.Variables() {
+				err = s.checkVariableExists(ctx, fileId, variableName)
+				liberr.ErrIsNil(ctx, err, "关联的变量不存在")
+			}
+
+			config := model.RepeatConfig{
+				Enabled:    true,
+				Over:       over,
+				ItemAlias:  strings.TrimSpace(req.ItemAlias),
+				IndexAlias: strings.TrimSpace(req.IndexAlias),
+			}
+			if config.ItemAlias == "" {
+				config.ItemAlias = model.DefaultRepeatItemAlias
+			}
+			if config.IndexAlias == "" {
+				config.IndexAlias = model.DefaultRepeatIndexAlias
+			}
+			if !repeatAliasRegex.MatchString(config.ItemAlias) || !repeatAliasRegex.MatchString(config.IndexAlias) {
+				liberr.ErrIsNil(ctx, fmt.Errorf("别名格式错误"), "元素和下标别名只能包含字母、数字和下划线，且不能以数字开头")
+			}
+			if config.ItemAlias == config.IndexAlias {
+				liberr.ErrIsNil(ctx, fmt.Errorf("别名重复"), "元素别名和下标别名不能相同")
+			}
+
+			configBytes, err := json.Marshal(config)
+			liberr.ErrIsNil(ctx, err, "批量生成配置序列化失败")
+			repeatJson = string(configBytes)
+		}
+
+		// 更新数据库
+		err = s.trackRevisions(ctx, model.FileRevisionActionRepeat, []int64{fileId}, func(ctx context.Context) error {
+			_, err := dao.TemplateFiles.Ctx(ctx).WherePri(fileId).Update(do.TemplateFiles{
+				RepeatConfig: repeatJson,
+			})
+			return err
+		})
+		liberr.ErrIsNil(ctx, err, "设置批量生成配置失败")
+	})
+	return
+}
+
+// GetRepeat 获取文件批量生成配置
+func (s *sTemplateFiles) GetRepeat(ctx context.Context, req *api.TemplateFilesGetRepeatReq) (res *api.TemplateFilesGetRepeatRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		fileInfo, err := s.GetById(ctx, gconv.Int64(req.Id))
+		liberr.ErrIsNil(ctx, err, "文件不存在")
+		liberr.ValueIsNil(fileInfo, "文件不存在")
+
+		res = &api.TemplateFilesGetRepeatRes{
+			ItemAlias:  model.DefaultRepeatItemAlias,
+			IndexAlias: model.DefaultRepeatIndexAlias,
+		}
+		if config := s.parseRepeatConfig(fileInfo.RepeatConfig); config != nil {
+			res.Enabled = config.Enabled
+			res.Over = config.Over
+			res.ItemAlias = config.ItemAlias
+			res.IndexAlias = config.IndexAlias
+		}
+	})
+	return
+}
+
+// renderUnit 渲染单元：模板文件及其渲染变量，批量生成的文件会展开为多个单元
+type renderUnit struct {
+	file      *entity.TemplateFiles
+	variables map[string]interface{}
+}
+
+// expandRepeatFiles 按批量生成配置展开文件，未配置的文件原样保留
+func (s sTemplateFiles) expandRepeatFiles(files []*entity.TemplateFiles, variables map[string]interface{}) []renderUnit {
+	units := make([]renderUnit, 0, len(files))
+	for _, file := range files {
+		config := s.parseRepeatConfig(file.RepeatConfig)
+		if config == nil || file.IsDirectory == 1 {
+			units = append(units, renderUnit{file: file, variables: variables})
+			continue
+		}
+
+		items, err := s.resolveRepeatItems(config, variables)
+		if err != nil {
+			// 配置无效的文件跳过，错误由模板检查报告
+			continue
+		}
+		for i, item := range items {
+			units = append(units, renderUnit{file: file, variables: s.repeatScope(variables, config, item, i)})
+		}
+	}
+	return units
+}
+
+// parseRepeatConfig 解析批量生成配置，未配置或未启用时返回 nil
+func (s sTemplateFiles) parseRepeatConfig(repeatConfig string) *model.RepeatConfig {
+	if repeatConfig == "" {
+		return nil
+	}
+	var config model.RepeatConfig
+	if err := json.Unmarshal([]byte(repeatConfig), &config); err != nil || !config.Enabled || config.Over == "" {
+		return nil
+	}
+	if config.ItemAlias == "" {
+		config.ItemAlias = model.DefaultRepeatItemAlias
+	}
+	if config.IndexAlias == "" {
+		config.IndexAlias = model.DefaultRepeatIndexAlias
+	}
+	return &config
+}
+
+// resolveRepeatItems 取出要遍历的数组，变量不存在时返回空数组
+func (s sTemplateFiles) resolveRepeatItems(config *model.RepeatConfig, variables map[string]interface{}) ([]interface{}, error) {
+	program, err := libExpr.Compile(config.Over)
+	if err != nil {
+		return nil, err
+	}
+	value, err := program.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("变量 %s 不是数组", config.Over)
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// repeatScope 复制渲染变量并注入当前元素和下标
+func (s sTemplateFiles) repeatScope(variables map[string]interface{}, config *model.RepeatConfig, item interface{}, index int) map[string]interface{} {
+	scope := make(map[string]interface{}, len(variables)+2)
+	for k, v := range variables {
+		scope[k] = v
+	}
+	scope[config.ItemAlias] = item
+	scope[config.IndexAlias] = index
+	return scope
+}
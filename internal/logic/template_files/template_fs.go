@@ -0,0 +1,133 @@
+package template_files
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+)
+
+// templateFS 由模板自身的 template_files 记录构成的只读虚拟文件系统，
+// 供 readFile、fileExists、dirExists 等内置函数使用，内容为未渲染的模板源码。
+type templateFS struct {
+	files map[string]*entity.TemplateFiles // 规范化路径 -> 文件记录
+	dirs  map[string]bool                  // 所有目录路径，包括文件的上级目录
+}
+
+// newTemplateFS 根据模板文件记录构建虚拟文件系统
+func newTemplateFS(files []*entity.TemplateFiles) *templateFS {
+	fsys := &templateFS{
+		files: make(map[string]*entity.TemplateFiles, len(files)),
+		dirs:  map[string]bool{".": true},
+	}
+	for _, file := range files {
+		name := normalizeTemplatePath(file.FilePath)
+		if name == "." {
+			continue
+		}
+		if file.IsDirectory == 1 {
+			fsys.dirs[name] = true
+		} else {
+			fsys.files[name] = file
+		}
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			fsys.dirs[dir] = true
+		}
+	}
+	return fsys
+}
+
+// normalizeTemplatePath 统一使用正斜杠并去掉首尾多余的分隔符
+func normalizeTemplatePath(filePath string) string {
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	return path.Clean(strings.Trim(filePath, "/"))
+}
+
+// Open 实现 fs.FS
+func (t *templateFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if file, ok := t.files[name]; ok {
+		return &templateFile{
+			info:   templateFileInfo{name: path.Base(name), size: int64(len(file.FileContent)), modTime: modTimeOf(file)},
+			reader: bytes.NewReader([]byte(file.FileContent)),
+		}, nil
+	}
+	if t.dirs[name] {
+		return &templateFile{info: templateFileInfo{name: path.Base(name), isDir: true}}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadFile 实现 fs.ReadFileFS
+func (t *templateFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	file, ok := t.files[name]
+	if !ok {
+		if t.dirs[name] {
+			return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+		}
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return []byte(file.FileContent), nil
+}
+
+// Stat 实现 fs.StatFS
+func (t *templateFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := t.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f.Stat()
+}
+
+func modTimeOf(file *entity.TemplateFiles) time.Time {
+	if file.UpdatedAt != nil {
+		return file.UpdatedAt.Time
+	}
+	return time.Time{}
+}
+
+// templateFile 虚拟文件句柄
+type templateFile struct {
+	info   templateFileInfo
+	reader *bytes.Reader
+}
+
+func (f *templateFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *templateFile) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: fs.ErrInvalid}
+	}
+	return f.reader.Read(p)
+}
+
+func (f *templateFile) Close() error { return nil }
+
+// templateFileInfo 虚拟文件信息
+type templateFileInfo struct {
+	name    string
+	size    int64
+	isDir   bool
+	modTime time.Time
+}
+
+func (i templateFileInfo) Name() string       { return i.name }
+func (i templateFileInfo) Size() int64        { return i.size }
+func (i templateFileInfo) ModTime() time.Time { return i.modTime }
+func (i templateFileInfo) IsDir() bool        { return i.isDir }
+func (i templateFileInfo) Sys() interface{}   { return nil }
+
+func (i templateFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
@@ -0,0 +1,470 @@
+package template_files
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_files"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	libExpr "github.com/ciclebyte/template_starter/library/libExpr"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+// templateBuiltinFuncs text/template 内置的函数，不在函数映射中也可调用
+var templateBuiltinFuncs = map[string]bool{
+	"and": true, "or": true, "not": true, "len": true, "index": true, "slice": true,
+	"print": true, "printf": true, "println": true, "html": true, "js": true, "urlquery": true,
+	"call": true, "eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+}
+
+// parseErrorLineRegex 从解析错误中提取行号，如 template: name:5: unexpected "}" in operand
+var parseErrorLineRegex = regexp.MustCompile(`template: .*?:(\d+):\s*(.*)`)
+
+// templateLinter 一次模板检查的上下文
+type templateLinter struct {
+	s        sTemplateFiles
+	src      *renderSource
+	funcs    template.FuncMap
+	declared map[string]bool // 变量定义中声明的顶层变量
+	defines  map[string]bool // 公共片段中 {{define}} 定义的模板
+	issues   []*model.TemplateLintIssue
+}
+
+// Lint 检查模板的全部文件：按渲染使用的函数映射解析文件内容、文件名和路径，
+// 找出语法错误、未注册的函数、未定义的模板、未声明的变量、无效的生成条件和批量生成配置，
+// 并以变量的示例值渲染路径，找出渲染后为空的文件名和相互冲突的路径
+func (s sTemplateFiles) Lint(ctx context.Context, req *api.TemplateFilesLintReq) (res *api.TemplateFilesLintRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		version := req.Version
+		if version == "" {
+			version = model.ReleaseVersionDraft
+		}
+		src, err := s.loadRenderSource(ctx, req.TemplateId, version)
+		liberr.ErrIsNil(ctx, err)
+
+		l := &templateLinter{
+			s:        s,
+			src:      src,
+			funcs:    s.getTemplateFuncs(nil, ""),
+			declared: make(map[string]bool),
+			defines:  make(map[string]bool),
+		}
+		for _, def := range src.Variables {
+			l.declared[def.Name] = true
+		}
+
+		// 先检查公共片段，收集其中定义的模板
+		regularFiles, partialFiles := splitPartialFiles(src.Files)
+		for _, file := range partialFiles {
+			if file.IsDirectory == 0 {
+				l.lintContent(file, nil)
+			}
+		}
+		// 路径由各级文件名组成，只检查文件名，上级目录名中的问题在目录上报告
+		for _, file := range regularFiles {
+			repeat := l.lintRepeat(file)
+			l.lintCondition(file)
+			l.lintName(file, "fileName", file.FileName, repeat)
+			if file.IsDirectory == 0 {
+				l.lintContent(file, repeat)
+			}
+		}
+		l.lintPaths(ctx, regularFiles)
+
+		fileCount := 0
+		for _, file := range src.Files {
+			if file.IsDirectory == 0 {
+				fileCount++
+			}
+		}
+
+		res = &api.TemplateFilesLintRes{
+			TemplateId: req.TemplateId,
+			Version:    src.Version(),
+			FileCount:  fileCount,
+			Issues:     l.sortedIssues(),
+		}
+		for _, issue := range res.Issues {
+			if issue.Severity == model.LintSeverityError {
+				res.ErrorCount++
+			} else {
+				res.WarningCount++
+			}
+		}
+	})
+	return
+}
+
+func (l *templateLinter) add(severity, rule string, file *entity.TemplateFiles, part string, line int, format string, args ...interface{}) {
+	issue := &model.TemplateLintIssue{
+		Severity: severity,
+		Rule:     rule,
+		Part:     part,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if file != nil {
+		issue.FileId = file.Id
+		issue.File = file.FilePath
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// lintContent 检查文件内容，公共片段中定义的模板在检查普通文件前收集完毕
+func (l *templateLinter) lintContent(file *entity.TemplateFiles, repeat *model.RepeatConfig) {
+	if file.FileContent == "" {
+		return
+	}
+	content := l.s.fixLegacyVariableSyntax(file.FileContent, l.src.Delims)
+	trees, ok := l.parse(file, "fileContent", content)
+	if !ok {
+		return
+	}
+	if isPartialFile(file) {
+		for name := range trees {
+			if name != file.FilePath {
+				l.defines[name] = true
+			}
+		}
+	}
+	l.lintTrees(file, "fileContent", content, trees, repeat)
+}
+
+// lintName 检查文件名中的模板表达式
+func (l *templateLinter) lintName(file *entity.TemplateFiles, part, value string, repeat *model.RepeatConfig) {
+	value = l.s.fixLegacyVariableSyntax(value, l.src.Delims)
+	if !strings.Contains(value, l.src.Delims.Left) {
+		return
+	}
+	if trees, ok := l.parse(file, part, value); ok {
+		l.lintTrees(file, part, value, trees, repeat)
+	}
+}
+
+// parse 跳过函数检查解析模板，以便一次找出全部未注册的函数；返回文件及其中 {{define}} 的语法树
+func (l *templateLinter) parse(file *entity.TemplateFiles, part, content string) (map[string]*parse.Tree, bool) {
+	tree := parse.New(file.FilePath)
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(content, l.src.Delims.Left, l.src.Delims.Right, trees); err != nil {
+		line, message := 0, err.Error()
+		if matches := parseErrorLineRegex.FindStringSubmatch(message); len(matches) > 2 {
+			line, _ = strconv.Atoi(matches[1])
+			message = matches[2]
+		}
+		l.add(model.LintSeverityError, model.LintRuleSyntax, file, part, line, "模板语法错误: %s", message)
+		return nil, false
+	}
+	return trees, true
+}
+
+// lintTrees 检查语法树中的函数、模板引用和变量
+func (l *templateLinter) lintTrees(file *entity.TemplateFiles, part, content string, trees map[string]*parse.Tree, repeat *model.RepeatConfig) {
+	names := make([]string, 0, len(trees))
+	for name := range trees {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	refs := &lintRefs{}
+	for _, name := range names {
+		if trees[name].Root != nil {
+			refs.walk(trees[name].Root, true)
+		}
+	}
+
+	line := func(pos parse.Pos) int {
+		return 1 + strings.Count(content[:min(int(pos), len(content))], "\n")
+	}
+	reported := make(map[string]bool)
+	for _, ref := range refs.funcs {
+		if _, ok := l.funcs[ref.name]; ok || templateBuiltinFuncs[ref.name] || reported["func:"+ref.name] {
+			continue
+		}
+		reported["func:"+ref.name] = true
+		l.add(model.LintSeverityError, model.LintRuleUndefinedFunction, file, part, line(ref.pos), "函数 %s 未定义", ref.name)
+	}
+	for _, ref := range refs.templates {
+		if _, ok := trees[ref.name]; ok || l.defines[ref.name] || reported["template:"+ref.name] {
+			continue
+		}
+		reported["template:"+ref.name] = true
+		l.add(model.LintSeverityError, model.LintRuleUndefinedTemplate, file, part, line(ref.pos), "模板 %q 未定义，请在 %s 目录的文件中使用 define 定义", ref.name, partialsDir)
+	}
+	for _, ref := range refs.vars {
+		if l.declared[ref.name] || reported["var:"+ref.name] {
+			continue
+		}
+		if repeat != nil && (ref.name == repeat.ItemAlias || ref.name == repeat.IndexAlias) {
+			continue
+		}
+		reported["var:"+ref.name] = true
+		l.add(model.LintSeverityWarning, model.LintRuleUndeclaredVar, file, part, line(ref.pos), "变量 %s 未在模板变量中声明", ref.name)
+	}
+}
+
+// lintCondition 检查生成条件能否解析以及引用的变量是否已声明
+func (l *templateLinter) lintCondition(file *entity.TemplateFiles) {
+	if strings.TrimSpace(file.GenerateCondition) == "" {
+		return
+	}
+	var condition model.GenerateCondition
+	if err := json.Unmarshal([]byte(file.GenerateCondition), &condition); err != nil {
+		l.add(model.LintSeverityError, model.LintRuleCondition, file, "condition", 0, "生成条件格式错误，渲染时将忽略条件: %s", err)
+		return
+	}
+	if !condition.Enabled {
+		return
+	}
+	if condition.Expression != "" {
+		program, err := libExpr.Compile(condition.Expression)
+		if err != nil {
+			l.add(model.LintSeverityError, model.LintRuleCondition, file, "condition", 0, "条件表达式 %s 无法解析，渲染时将忽略条件: %s", condition.Expression, err)
+			return
+		}
+		for _, name := range program.Variables() {
+			if !l.declared[name] {
+				l.add(model.LintSeverityWarning, model.LintRuleCondition, file, "condition", 0, "条件表达式引用了未声明的变量 %s", name)
+			}
+		}
+		return
+	}
+	if condition.VariableName == "" {
+		l.add(model.LintSeverityWarning, model.LintRuleCondition, file, "condition", 0, "生成条件已启用但未设置变量或表达式")
+		return
+	}
+	if !l.declared[condition.VariableName] {
+		l.add(model.LintSeverityWarning, model.LintRuleCondition, file, "condition", 0, "生成条件引用了未声明的变量 %s，渲染时将始终生成", condition.VariableName)
+	}
+}
+
+// lintRepeat 检查批量生成配置，返回有效的配置供检查文件内容时识别别名
+func (l *templateLinter) lintRepeat(file *entity.TemplateFiles) *model.RepeatConfig {
+	config := l.s.parseRepeatConfig(file.RepeatConfig)
+	if config == nil {
+		return nil
+	}
+	program, err := libExpr.Compile(config.Over)
+	if err != nil {
+		l.add(model.LintSeverityError, model.LintRuleRepeat, file, "repeat", 0, "批量生成的数组变量 %s 无法解析: %s", config.Over, err)
+		return config
+	}
+	for _, name := range program.Variables() {
+		if !l.declared[name] {
+			l.add(model.LintSeverityWarning, model.LintRuleRepeat, file, "repeat", 0, "批量生成引用了未声明的变量 %s", name)
+		}
+	}
+	return config
+}
+
+// lintPaths 以变量的示例值渲染每个文件的路径，检查空的路径片段和冲突的路径。
+// 两个文件都设置了生成条件时视为互斥的备选文件，不报告冲突
+func (l *templateLinter) lintPaths(ctx context.Context, files []*entity.TemplateFiles) {
+	variables := make(map[string]interface{}, len(l.src.Variables))
+	for _, def := range l.src.Variables {
+		if def.Computed == "" {
+			variables[def.Name] = lintSampleValue(def)
+		}
+	}
+	validationErrors, err := l.s.computeVariables(ctx, l.src.Delims, l.src.Variables, variables)
+	if err != nil {
+		return
+	}
+	for _, validationErr := range validationErrors {
+		issue := &model.TemplateLintIssue{
+			Severity: model.LintSeverityError,
+			Rule:     model.LintRuleComputedVariable,
+			Part:     "variables",
+			Message:  fmt.Sprintf("%s: %s", validationErr.Field, validationErr.Message),
+		}
+		l.issues = append(l.issues, issue)
+	}
+
+	sandbox, cancel := newRenderSandbox(ctx)
+	defer cancel()
+	funcs := l.s.getTemplateFuncs(newTemplateFS(l.src.Files), "")
+	rendered := make(map[string]*entity.TemplateFiles)
+	for _, file := range files {
+		scope := variables
+		if config := l.s.parseRepeatConfig(file.RepeatConfig); config != nil {
+			var item interface{}
+			if items, err := l.s.resolveRepeatItems(config, variables); err == nil && len(items) > 0 {
+				item = items[0]
+			}
+			scope = l.s.repeatScope(variables, config, item, 0)
+		}
+
+		source := l.s.fixLegacyVariableSyntax(file.FilePath, l.src.Delims)
+		renderedPath := source
+		if strings.Contains(source, l.src.Delims.Left) {
+			tmpl, err := template.New("filePath").Delims(l.src.Delims.Left, l.src.Delims.Right).Funcs(funcs).Parse(source)
+			if err != nil {
+				// 语法错误已在检查文件名时报告
+				continue
+			}
+			run := sandbox.Begin(file.FilePath)
+			renderedPath, err = run.Execute(tmpl.Funcs(run.Funcs(funcs)), scope)
+			run.Close()
+			if err != nil {
+				continue
+			}
+		}
+
+		renderedPath = strings.ReplaceAll(renderedPath, "\\", "/")
+		if empty := emptyPathSegment(renderedPath); empty != "" {
+			l.add(model.LintSeverityError, model.LintRuleEmptyPath, file, "filePath", 0, "路径以示例变量渲染为 %q，%s", renderedPath, empty)
+			continue
+		}
+		if other, ok := rendered[renderedPath]; ok {
+			if !hasEnabledCondition(other) || !hasEnabledCondition(file) {
+				l.add(model.LintSeverityWarning, model.LintRulePathCollision, file, "filePath", 0, "路径以示例变量渲染为 %s，与 %s 冲突", renderedPath, other.FilePath)
+			}
+			continue
+		}
+		rendered[renderedPath] = file
+	}
+}
+
+// sortedIssues 按文件路径、行号排序，变量定义的问题排在最前
+func (l *templateLinter) sortedIssues() []*model.TemplateLintIssue {
+	issues := l.issues
+	if issues == nil {
+		issues = []*model.TemplateLintIssue{}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// emptyPathSegment 检查渲染后的路径中是否有空的或指向上级的片段，返回问题说明
+func emptyPathSegment(renderedPath string) string {
+	if strings.TrimSpace(renderedPath) == "" {
+		return "路径为空"
+	}
+	for _, segment := range strings.Split(renderedPath, "/") {
+		switch strings.TrimSpace(segment) {
+		case "":
+			return "其中包含空的文件名或目录名"
+		case ".", "..":
+			return "其中包含 . 或 .. 目录"
+		}
+	}
+	return ""
+}
+
+func hasEnabledCondition(file *entity.TemplateFiles) bool {
+	var condition model.GenerateCondition
+	return json.Unmarshal([]byte(file.GenerateCondition), &condition) == nil && condition.Enabled
+}
+
+// lintSampleValue 生成变量的示例值：优先使用默认值和第一个可选值，否则按类型生成非空的值
+func lintSampleValue(def *model.TemplateVariableDef) interface{} {
+	if def.Default != nil {
+		return def.Default
+	}
+	if len(def.Enum) > 0 {
+		return def.Enum[0]
+	}
+	switch def.Type {
+	case model.VariableTypeInteger, model.VariableTypeNumber:
+		return 1
+	case model.VariableTypeBoolean:
+		return true
+	case model.VariableTypeArray, model.VariableTypeObjectArr:
+		if def.Items != nil {
+			return []interface{}{lintSampleValue(def.Items)}
+		}
+		if def.Type == model.VariableTypeObjectArr {
+			return []interface{}{map[string]interface{}{}}
+		}
+		return []interface{}{"item"}
+	case model.VariableTypeObject:
+		value := make(map[string]interface{}, len(def.Properties))
+		for name, property := range def.Properties {
+			value[name] = lintSampleValue(property)
+		}
+		return value
+	}
+	if def.Name == "" {
+		return "sample"
+	}
+	return def.Name
+}
+
+// lintRef 模板中的一处引用
+type lintRef struct {
+	name string
+	pos  parse.Pos
+}
+
+// lintRefs 语法树中调用的函数、引用的模板和使用的顶层变量
+type lintRefs struct {
+	funcs     []lintRef
+	templates []lintRef
+	vars      []lintRef
+}
+
+// walk 遍历语法树，atRoot 表示 . 是否指向根变量，with、range 块内的 . 不再指向根变量
+func (r *lintRefs) walk(node parse.Node, atRoot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			r.walk(child, atRoot)
+		}
+	case *parse.ActionNode:
+		r.walk(n.Pipe, atRoot)
+	case *parse.IfNode:
+		r.walk(n.Pipe, atRoot)
+		r.walk(n.List, atRoot)
+		r.walk(n.ElseList, atRoot)
+	case *parse.WithNode:
+		r.walk(n.Pipe, atRoot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, atRoot)
+	case *parse.RangeNode:
+		r.walk(n.Pipe, atRoot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, atRoot)
+	case *parse.TemplateNode:
+		r.templates = append(r.templates, lintRef{name: n.Name, pos: n.Position()})
+		r.walk(n.Pipe, atRoot)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				r.walk(arg, atRoot)
+			}
+		}
+	case *parse.ChainNode:
+		r.walk(n.Node, atRoot)
+	case *parse.IdentifierNode:
+		r.funcs = append(r.funcs, lintRef{name: n.Ident, pos: n.Position()})
+	case *parse.FieldNode:
+		if atRoot {
+			r.vars = append(r.vars, lintRef{name: n.Ident[0], pos: n.Position()})
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			r.vars = append(r.vars, lintRef{name: n.Ident[1], pos: n.Position()})
+		}
+	}
+}
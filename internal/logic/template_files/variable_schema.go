@@ -0,0 +1,347 @@
+package template_files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_files"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// variableValidationError 变量不符合模板变量定义时返回的错误，包含每个字段的校验结果
+type variableValidationError struct {
+	Errors []*api.VariableValidationError
+}
+
+func (e *variableValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return "变量校验失败: " + strings.Join(messages, "; ")
+}
+
+// asVariableValidationError 判断错误是否为变量校验错误
+func asVariableValidationError(err error) (*variableValidationError, bool) {
+	var validationErr *variableValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
+	}
+	return nil, false
+}
+
+// newVariableRenderError 将变量校验错误转换为渲染错误详情
+func (s sTemplateFiles) newVariableRenderError(err *variableValidationError) *api.TemplateRenderError {
+	return &api.TemplateRenderError{
+		Type:       "variable_error",
+		Message:    err.Error(),
+		Suggestion: "请根据模板变量定义检查变量的类型、取值范围和必填项",
+	}
+}
+
+// variableCoercer 按变量定义转换变量类型并收集校验错误
+type variableCoercer struct {
+	errors   []*api.VariableValidationError
+	patterns map[string]*regexp.Regexp
+}
+
+// coerceVariables 按模板变量定义补全默认值、转换类型并校验约束，返回新的变量集合；
+// 未定义的变量原样保留，计算变量不接受输入，由 computeVariables 计算
+func coerceVariables(defs []*model.TemplateVariableDef, variables map[string]interface{}) (map[string]interface{}, []*api.VariableValidationError) {
+	inputs := make([]*model.TemplateVariableDef, 0, len(defs))
+	for _, def := range defs {
+		if def.Computed == "" {
+			inputs = append(inputs, def)
+		}
+	}
+	c := &variableCoercer{patterns: make(map[string]*regexp.Regexp)}
+	result := c.object("", inputs, variables)
+	for _, def := range defs {
+		if def.Computed != "" {
+			delete(result, def.Name)
+		}
+	}
+	return result, c.errors
+}
+
+func (c *variableCoercer) fail(field, format string, args ...interface{}) {
+	c.errors = append(c.errors, &api.VariableValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// object 按字段定义转换对象，prefix 为对象自身的路径
+func (c *variableCoercer) object(prefix string, defs []*model.TemplateVariableDef, values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values)+len(defs))
+	for k, v := range values {
+		result[k] = v
+	}
+	for _, def := range defs {
+		field := def.Name
+		if prefix != "" {
+			field = prefix + "." + def.Name
+		}
+		value, ok := c.value(field, def, result[def.Name])
+		if ok {
+			result[def.Name] = value
+		} else {
+			delete(result, def.Name)
+		}
+	}
+	return result
+}
+
+// value 转换单个变量值，返回 false 表示该变量没有取值
+func (c *variableCoercer) value(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	if isBlankValue(value) && def.Default != nil {
+		value = def.Default
+	}
+	if isBlankValue(value) {
+		if def.Required {
+			c.fail(field, "为必填项")
+			return value, value != nil
+		}
+		// 非字符串类型的空值视为未传入，避免空字符串参与数值比较
+		if value == nil || !isStringType(def.Type) {
+			return nil, false
+		}
+		return value, true
+	}
+
+	var ok bool
+	switch def.Type {
+	case model.VariableTypeString, model.VariableTypeSecret:
+		value, ok = c.string(field, def, value)
+	case model.VariableTypeInteger, model.VariableTypeNumber:
+		value, ok = c.number(field, def, value)
+	case model.VariableTypeBoolean:
+		value, ok = c.boolean(field, value)
+	case model.VariableTypeEnum:
+		value, ok = c.enum(field, def, value)
+	case model.VariableTypeArray, model.VariableTypeObjectArr:
+		value, ok = c.array(field, def, value)
+	case model.VariableTypeObject:
+		value, ok = c.objectValue(field, def, value)
+	default:
+		return value, true
+	}
+	if ok && def.Type != model.VariableTypeEnum && len(def.Enum) > 0 {
+		c.enum(field, def, value)
+	}
+	return value, true
+}
+
+func (c *variableCoercer) string(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		c.fail(field, "应为字符串")
+		return value, false
+	}
+	str := gconv.String(value)
+	if def.Pattern != "" {
+		re, ok := c.patterns[def.Pattern]
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(def.Pattern); err != nil {
+				c.fail(field, "变量定义中的正则表达式 %q 无效", def.Pattern)
+				return str, false
+			}
+			c.patterns[def.Pattern] = re
+		}
+		if !re.MatchString(str) {
+			c.fail(field, "值 %q 不匹配格式 %s", str, def.Pattern)
+			return str, false
+		}
+	}
+	return str, true
+}
+
+func (c *variableCoercer) number(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	num, ok := toFloat(value)
+	if !ok {
+		if def.Type == model.VariableTypeInteger {
+			c.fail(field, "应为整数，实际为 %v", value)
+		} else {
+			c.fail(field, "应为数字，实际为 %v", value)
+		}
+		return value, false
+	}
+	var result interface{} = num
+	if def.Type == model.VariableTypeInteger {
+		if num != math.Trunc(num) {
+			c.fail(field, "应为整数，实际为 %v", value)
+			return value, false
+		}
+		result = int64(num)
+	}
+	if def.Min != nil && num < *def.Min {
+		c.fail(field, "不能小于 %v", *def.Min)
+		return result, false
+	}
+	if def.Max != nil && num > *def.Max {
+		c.fail(field, "不能大于 %v", *def.Max)
+		return result, false
+	}
+	return result, true
+}
+
+func (c *variableCoercer) boolean(field string, value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "1", "yes", "y", "on":
+			return true, true
+		case "false", "0", "no", "n", "off":
+			return false, true
+		}
+	default:
+		if num, ok := toFloat(v); ok && (num == 0 || num == 1) {
+			return num == 1, true
+		}
+	}
+	c.fail(field, "应为布尔值，实际为 %v", value)
+	return value, false
+}
+
+// enum 校验取值是否在可选值中，返回可选值中的原始值以保留其类型
+func (c *variableCoercer) enum(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	str := gconv.String(value)
+	options := make([]string, 0, len(def.Enum))
+	for _, option := range def.Enum {
+		if gconv.String(option) == str {
+			return option, true
+		}
+		options = append(options, gconv.String(option))
+	}
+	c.fail(field, "值 %q 不在可选值 [%s] 中", str, strings.Join(options, ", "))
+	return value, false
+}
+
+func (c *variableCoercer) array(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	items, ok := toSlice(value)
+	if !ok {
+		c.fail(field, "应为数组")
+		return value, false
+	}
+	if def.MinItems != nil && len(items) < *def.MinItems {
+		c.fail(field, "至少需要 %d 项，实际为 %d 项", *def.MinItems, len(items))
+	}
+	if def.MaxItems != nil && len(items) > *def.MaxItems {
+		c.fail(field, "最多允许 %d 项，实际为 %d 项", *def.MaxItems, len(items))
+	}
+
+	itemDef := def.Items
+	if def.Type == model.VariableTypeObjectArr {
+		// 对象数组的元素结构可以定义在 items.properties 或 properties 中
+		itemDef = &model.TemplateVariableDef{Type: model.VariableTypeObject, Properties: def.Properties}
+		if def.Items != nil && len(def.Items.Properties) > 0 {
+			itemDef.Properties = def.Items.Properties
+		}
+	}
+	if itemDef == nil {
+		return items, true
+	}
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[i], _ = c.value(fmt.Sprintf("%s[%d]", field, i), itemDef, item)
+	}
+	return result, true
+}
+
+func (c *variableCoercer) objectValue(field string, def *model.TemplateVariableDef, value interface{}) (interface{}, bool) {
+	values, ok := toMap(value)
+	if !ok {
+		c.fail(field, "应为对象")
+		return value, false
+	}
+	return c.object(field, sortedProperties(def.Properties), values), true
+}
+
+// sortedProperties 按字段名排序对象字段定义，保证校验错误的顺序稳定
+func sortedProperties(properties map[string]*model.TemplateVariableDef) []*model.TemplateVariableDef {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	defs := make([]*model.TemplateVariableDef, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, properties[name])
+	}
+	return defs
+}
+
+func isStringType(typ string) bool {
+	return typ == model.VariableTypeString || typ == model.VariableTypeSecret
+}
+
+func isBlankValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	str, ok := value.(string)
+	return ok && strings.TrimSpace(str) == ""
+}
+
+// toFloat 将数字或数字字符串转换为 float64
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return num, err == nil
+	}
+	return 0, false
+}
+
+// toSlice 将数组或 JSON 数组字符串转换为 []interface{}
+func toSlice(value interface{}) ([]interface{}, bool) {
+	if str, ok := value.(string); ok {
+		var items []interface{}
+		if err := json.Unmarshal([]byte(str), &items); err != nil {
+			return nil, false
+		}
+		return items, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, true
+}
+
+// toMap 将对象或 JSON 对象字符串转换为 map[string]interface{}
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case string:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &m); err != nil || m == nil {
+			return nil, false
+		}
+		return m, true
+	}
+	if reflect.ValueOf(value).Kind() != reflect.Map {
+		return nil, false
+	}
+	return gconv.Map(value), true
+}
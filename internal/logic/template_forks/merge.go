@@ -0,0 +1,131 @@
+package template_forks
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// 冲突标记，与 git 的 diff3 风格一致，同时列出双方内容和合并基准
+const (
+	markerLocal    = "<<<<<<< "
+	markerBase     = "||||||| "
+	markerSplit    = "======="
+	markerUpstream = ">>>>>>> "
+)
+
+// mergeLabels 冲突标记中双方内容和合并基准的标签
+type mergeLabels struct {
+	Local    string
+	Base     string
+	Upstream string
+}
+
+// merge3 以 base 为基准对 local 和 upstream 的修改做逐行三方合并，
+// 返回合并后的内容和冲突块的数量，存在冲突时内容中已写入冲突标记
+func merge3(base, local, upstream string, labels mergeLabels) (string, int) {
+	o, a, b := splitLines(base), splitLines(local), splitLines(upstream)
+	ma, mb := matchLines(o, a), matchLines(o, b)
+
+	var out strings.Builder
+	conflicts := 0
+	i, ia, ib := 0, 0, 0
+	for {
+		// 双方都与基准一致的部分原样保留
+		for i < len(o) && ma[i] == ia && mb[i] == ib {
+			out.WriteString(o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+		}
+		if i == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		// 找到下一个双方都与基准匹配的行，其间为一个修改块
+		j := i
+		for j < len(o) && (ma[j] < 0 || mb[j] < 0) {
+			j++
+		}
+		ea, eb := len(a), len(b)
+		if j < len(o) {
+			ea, eb = ma[j], mb[j]
+		}
+		chunkO, chunkA, chunkB := o[i:j], a[ia:ea], b[ib:eb]
+		switch {
+		case equalLines(chunkA, chunkO):
+			writeLines(&out, chunkB)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			writeLines(&out, chunkA)
+		default:
+			conflicts++
+			writeConflict(&out, chunkO, chunkA, chunkB, labels)
+		}
+		i, ia, ib = j, ea, eb
+	}
+	return out.String(), conflicts
+}
+
+// matchLines 返回 base 中每一行在 other 中匹配的行号，没有匹配的为 -1，匹配关系单调递增
+func matchLines(base, other []string) []int {
+	matches := make([]int, len(base))
+	for i := range matches {
+		matches[i] = -1
+	}
+	// 关闭 autojunk：超过200行时 difflib 会把高频行（空行、右括号等）当作垃圾行不参与匹配，造成虚假冲突
+	matcher := difflib.NewMatcherWithJunk(base, other, false, nil)
+	for _, block := range matcher.GetMatchingBlocks() {
+		for k := 0; k < block.Size; k++ {
+			matches[block.A+k] = block.B + k
+		}
+	}
+	return matches
+}
+
+func writeConflict(out *strings.Builder, base, local, upstream []string, labels mergeLabels) {
+	out.WriteString(markerLocal + labels.Local + "\n")
+	writeLines(out, ensureTrailingNewline(local))
+	out.WriteString(markerBase + labels.Base + "\n")
+	writeLines(out, ensureTrailingNewline(base))
+	out.WriteString(markerSplit + "\n")
+	writeLines(out, ensureTrailingNewline(upstream))
+	out.WriteString(markerUpstream + labels.Upstream + "\n")
+}
+
+// splitLines 按行拆分并保留换行符，拼接后与原内容完全一致
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ensureTrailingNewline 冲突块的最后一行没有换行符时补上，保证冲突标记独占一行
+func ensureTrailingNewline(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	fixed := append([]string(nil), lines...)
+	fixed[len(fixed)-1] += "\n"
+	return fixed
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package template_forks
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// bigFile 生成一个240行的文件，大量重复的空行和右括号；中间一段只由重复行隔开 a、mid、b 三行
+func bigFile(a, mid, b string) string {
+	var s strings.Builder
+	for i := 0; i < 60; i++ {
+		if i == 30 {
+			fmt.Fprintf(&s, "%s\n}\n\n}\n%s\n}\n\n}\n%s\n", a, mid, b)
+			continue
+		}
+		fmt.Fprintf(&s, "line %d\n}\n\n}\n", i)
+	}
+	return s.String()
+}
+
+func TestMerge3(t *testing.T) {
+	labels := mergeLabels{Local: "local", Base: "base", Upstream: "upstream"}
+	cases := []struct {
+		name      string
+		base      string
+		local     string
+		upstream  string
+		want      string
+		conflicts int
+	}{
+		{
+			name:     "clean merge of separate edits",
+			base:     "a\nb\nc\nd\ne\n",
+			local:    "A\nb\nc\nd\ne\n",
+			upstream: "a\nb\nc\nd\nE\n",
+			want:     "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:     "edit on local only",
+			base:     "a\nb\nc\n",
+			local:    "a\nB\nc\n",
+			upstream: "a\nb\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "edit on upstream only",
+			base:     "a\nb\nc\n",
+			local:    "a\nb\nc\n",
+			upstream: "a\nb\nc\nd\n",
+			want:     "a\nb\nc\nd\n",
+		},
+		{
+			name:     "same edit on both sides",
+			base:     "a\nb\nc\n",
+			local:    "a\nX\nc\n",
+			upstream: "a\nX\nc\n",
+			want:     "a\nX\nc\n",
+		},
+		{
+			name:      "conflicting edits on both sides",
+			base:      "a\nb\nc\n",
+			local:     "a\nL\nc\n",
+			upstream:  "a\nU\nc\n",
+			want:      "a\n<<<<<<< local\nL\n||||||| base\nb\n=======\nU\n>>>>>>> upstream\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "conflict without trailing newline",
+			base:      "a\nb",
+			local:     "a\nL",
+			upstream:  "a\nU",
+			want:      "a\n<<<<<<< local\nL\n||||||| base\nb\n=======\nU\n>>>>>>> upstream\n",
+			conflicts: 1,
+		},
+		{
+			name:     "file over 200 lines with repeated lines",
+			base:     bigFile("a", "", "b"),
+			local:    bigFile("A", "", "B"),
+			upstream: bigFile("a", "mid", "b"),
+			want:     bigFile("A", "mid", "B"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, conflicts := merge3(c.base, c.local, c.upstream, labels)
+			if conflicts != c.conflicts {
+				t.Fatalf("conflicts = %d, want %d\n%s", conflicts, c.conflicts, got)
+			}
+			if got != c.want {
+				t.Fatalf("merged content mismatch\ngot:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package template_forks
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	api "github.com/ciclebyte/template_starter/api/v1/template_forks"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+type sTemplateForks struct{}
+
+func init() {
+	service.RegisterTemplateForks(New())
+}
+
+func New() *sTemplateForks {
+	return &sTemplateForks{}
+}
+
+// mergePlan 拉取上游修改时对当前模板的改动
+type mergePlan struct {
+	files   []*model.TemplateMergeFile
+	deletes []int64           // 要删除的文件ID
+	edits   map[int64]string  // 文件ID到新内容
+	adds    map[string]string // 要新增的文件路径到内容
+}
+
+// Track 记录 Fork 出的模板的上游来源，以上游当前未发布的内容作为之后三方合并的基准，
+// 在 Fork 的事务中调用
+func (s *sTemplateForks) Track(ctx context.Context, templateId, upstreamTemplateId int64) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		base, err := s.draftFiles(ctx, upstreamTemplateId)
+		liberr.ErrIsNil(ctx, err)
+		baseJson, err := json.Marshal(base)
+		liberr.ErrIsNil(ctx, err, "记录上游文件失败")
+
+		_, err = dao.TemplateForks.Ctx(ctx).Data(do.TemplateForks{
+			TemplateId:         templateId,
+			UpstreamTemplateId: upstreamTemplateId,
+			BaseVersion:        model.ReleaseVersionDraft,
+			BaseFiles:          string(baseJson),
+		}).Save()
+		liberr.ErrIsNil(ctx, err, "记录上游来源失败")
+	})
+	return
+}
+
+// Upstream 获取模板的上游来源
+func (s *sTemplateForks) Upstream(ctx context.Context, templateId int64) (upstream *model.TemplateUpstreamInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		fork, err := s.findFork(ctx, templateId)
+		liberr.ErrIsNil(ctx, err)
+		upstream, err = s.toUpstreamInfo(ctx, fork)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// Pull 将上游自上次同步以来的修改逐个文件三方合并到当前模板：只有一方修改的直接采用，
+// 双方修改互不冲突的自动合并，存在冲突的写入冲突标记等待手动解决。合并后以本次上游内容作为新的基准
+func (s *sTemplateForks) Pull(ctx context.Context, req *api.TemplateForksPullReq) (res *api.TemplateForksPullRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		fork, err := s.findFork(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err)
+		upstreamName, err := dao.Templates.Ctx(ctx).WherePri(fork.UpstreamTemplateId).Value(dao.Templates.Columns().Name)
+		liberr.ErrIsNil(ctx, err, "获取上游模板失败")
+		if upstreamName.IsEmpty() {
+			liberr.ErrIsNil(ctx, gerror.New("上游模板已删除，无法拉取修改"))
+		}
+
+		version, upstream, err := s.upstreamFiles(ctx, int64(fork.UpstreamTemplateId), req.Version)
+		liberr.ErrIsNil(ctx, err)
+		var base map[string]string
+		if err = json.Unmarshal([]byte(fork.BaseFiles), &base); err != nil {
+			liberr.ErrIsNil(ctx, gerror.Wrap(err, "上游同步基准已损坏"))
+		}
+
+		var local []*entity.TemplateFiles
+		err = dao.TemplateFiles.Ctx(ctx).
+			Fields("id, file_path, file_content, is_directory").
+			Where(dao.TemplateFiles.Columns().TemplateId, req.TemplateId).
+			Scan(&local)
+		liberr.ErrIsNil(ctx, err, "获取模板文件失败")
+
+		labels := mergeLabels{
+			Local:    "当前模板",
+			Base:     "基准 " + fork.BaseVersion,
+			Upstream: "上游 " + upstreamName.String() + "@" + version,
+		}
+		plan, unchanged := s.plan(base, upstream, local, labels)
+
+		res = &api.TemplateForksPullRes{
+			Version:   version,
+			DryRun:    req.DryRun,
+			Files:     plan.files,
+			Unchanged: unchanged,
+		}
+		for _, file := range plan.files {
+			if file.Action == model.MergeActionConflict {
+				res.Conflicts++
+			}
+		}
+
+		if !req.DryRun {
+			err = dao.TemplateForks.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				if err := s.apply(ctx, req.TemplateId, local, plan); err != nil {
+					return err
+				}
+				upstreamJson, err := json.Marshal(upstream)
+				if err != nil {
+					return gerror.Wrap(err, "记录上游文件失败")
+				}
+				data := do.TemplateForks{
+					BaseVersion: version,
+					BaseFiles:   string(upstreamJson),
+					SyncedAt:    gtime.Now(),
+				}
+				if r := g.RequestFromCtx(ctx); r != nil {
+					data.SyncedById = gconv.Uint64(r.GetCtxVar("user_id"))
+					data.SyncedBy = r.GetCtxVar("username").String()
+				}
+				_, err = dao.TemplateForks.Ctx(ctx).WherePri(fork.Id).Data(data).Update()
+				return err
+			})
+			liberr.ErrIsNil(ctx, err, "拉取上游修改失败")
+			fork, err = s.findFork(ctx, req.TemplateId)
+			liberr.ErrIsNil(ctx, err)
+		}
+
+		res.Upstream, err = s.toUpstreamInfo(ctx, fork)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// plan 逐个比较基准、当前模板和上游的文件内容，只处理上游有变化的文件，当前模板独有的文件保持不变
+func (s *sTemplateForks) plan(base, upstream map[string]string, local []*entity.TemplateFiles, labels mergeLabels) (*mergePlan, int) {
+	localFiles := make(map[string]*entity.TemplateFiles)
+	for _, file := range local {
+		if file.IsDirectory == 0 {
+			localFiles[file.FilePath] = file
+		}
+	}
+
+	paths := make([]string, 0, len(base)+len(upstream))
+	for filePath := range base {
+		paths = append(paths, filePath)
+	}
+	for filePath := range upstream {
+		if _, ok := base[filePath]; !ok {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	plan := &mergePlan{
+		edits: make(map[int64]string),
+		adds:  make(map[string]string),
+	}
+	unchanged := 0
+	for _, filePath := range paths {
+		b, inBase := base[filePath]
+		u, inUpstream := upstream[filePath]
+		file, inLocal := localFiles[filePath]
+		if inBase && inUpstream && b == u {
+			unchanged++
+			continue
+		}
+
+		switch {
+		case !inBase:
+			// 上游新增的文件
+			switch {
+			case !inLocal:
+				plan.adds[filePath] = u
+				plan.add(filePath, model.MergeActionAdded)
+			case file.FileContent != u:
+				merged, conflicts := merge3("", file.FileContent, u, labels)
+				plan.edits[file.Id] = merged
+				plan.conflict(filePath, model.MergeConflictAddAdd, conflicts, merged)
+			}
+		case !inUpstream:
+			// 上游删除的文件
+			switch {
+			case !inLocal:
+			case file.FileContent == b:
+				plan.deletes = append(plan.deletes, file.Id)
+				plan.add(filePath, model.MergeActionDeleted)
+			default:
+				plan.conflict(filePath, model.MergeConflictDeleteModify, 0, "")
+			}
+		default:
+			// 上游修改的文件
+			switch {
+			case !inLocal:
+				plan.adds[filePath] = u
+				plan.conflict(filePath, model.MergeConflictModifyDelete, 0, "")
+			case file.FileContent == b:
+				plan.edits[file.Id] = u
+				plan.add(filePath, model.MergeActionModified)
+			case file.FileContent == u:
+			default:
+				merged, conflicts := merge3(b, file.FileContent, u, labels)
+				plan.edits[file.Id] = merged
+				if conflicts == 0 {
+					plan.add(filePath, model.MergeActionMerged)
+				} else {
+					plan.conflict(filePath, model.MergeConflictContent, conflicts, merged)
+				}
+			}
+		}
+	}
+	return plan, unchanged
+}
+
+func (p *mergePlan) add(filePath, action string) {
+	p.files = append(p.files, &model.TemplateMergeFile{Path: filePath, Action: action})
+}
+
+func (p *mergePlan) conflict(filePath, conflictType string, conflicts int, content string) {
+	p.files = append(p.files, &model.TemplateMergeFile{
+		Path:         filePath,
+		Action:       model.MergeActionConflict,
+		ConflictType: conflictType,
+		Conflicts:    conflicts,
+		Content:      content,
+	})
+}
+
+// apply 按合并结果修改当前模板的文件，修改会记录文件修订历史
+func (s *sTemplateForks) apply(ctx context.Context, templateId int64, local []*entity.TemplateFiles, plan *mergePlan) error {
+	if len(plan.deletes) > 0 {
+		if err := service.TemplateFiles().BatchDelete(ctx, plan.deletes); err != nil {
+			return err
+		}
+	}
+	for id, content := range plan.edits {
+		err := service.TemplateFiles().Edit(ctx, &filesApi.TemplateFilesEditReq{Id: id, FileContent: content})
+		if err != nil {
+			return err
+		}
+	}
+
+	dirIds := make(map[string]int64)
+	for _, file := range local {
+		if file.IsDirectory == 1 {
+			dirIds[file.FilePath] = file.Id
+		}
+	}
+	paths := make([]string, 0, len(plan.adds))
+	for filePath := range plan.adds {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		content := plan.adds[filePath]
+		parentId, err := s.ensureDirectory(ctx, templateId, path.Dir(filePath), dirIds)
+		if err != nil {
+			return err
+		}
+		err = service.TemplateFiles().Add(ctx, &filesApi.TemplateFilesAddReq{
+			TemplateId:  templateId,
+			FileName:    path.Base(filePath),
+			FileContent: content,
+			FileSize:    len(content),
+			IsDirectory: 0,
+			Md5:         gmd5.MustEncryptString(content),
+			ParentId:    int(parentId),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDirectory 创建目录及其缺失的上级目录，返回目录ID，根目录为 0
+func (s *sTemplateForks) ensureDirectory(ctx context.Context, templateId int64, dir string, ids map[string]int64) (int64, error) {
+	if dir == "." {
+		return 0, nil
+	}
+	if id, ok := ids[dir]; ok {
+		return id, nil
+	}
+	parentId, err := s.ensureDirectory(ctx, templateId, path.Dir(dir), ids)
+	if err != nil {
+		return 0, err
+	}
+	err = service.TemplateFiles().Add(ctx, &filesApi.TemplateFilesAddReq{
+		TemplateId:  templateId,
+		FileName:    path.Base(dir),
+		IsDirectory: 1,
+		ParentId:    int(parentId),
+	})
+	if err != nil {
+		return 0, err
+	}
+	id, err := dao.TemplateFiles.Ctx(ctx).
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Where(dao.TemplateFiles.Columns().FilePath, dir).
+		Where(dao.TemplateFiles.Columns().IsDirectory, 1).
+		Value(dao.TemplateFiles.Columns().Id)
+	if err != nil {
+		return 0, gerror.Wrapf(err, "创建目录 %s 失败", dir)
+	}
+	ids[dir] = id.Int64()
+	return ids[dir], nil
+}
+
+// upstreamFiles 获取上游指定版本的文件内容，draft 为上游当前未发布的内容
+func (s *sTemplateForks) upstreamFiles(ctx context.Context, upstreamTemplateId int64, version string) (string, map[string]string, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || strings.EqualFold(version, model.ReleaseVersionDraft) {
+		files, err := s.draftFiles(ctx, upstreamTemplateId)
+		return model.ReleaseVersionDraft, files, err
+	}
+
+	release, snapshot, err := service.TemplateReleases().LoadSnapshot(ctx, upstreamTemplateId, version)
+	if err != nil {
+		return "", nil, err
+	}
+	if release == nil {
+		return "", nil, gerror.New("上游模板尚未发布任何版本")
+	}
+	files := make(map[string]string)
+	for _, file := range snapshot.Files {
+		if file.IsDirectory == 0 {
+			files[file.FilePath] = file.FileContent
+		}
+	}
+	return release.Version, files, nil
+}
+
+// draftFiles 获取模板当前的文件内容，键为文件路径，不含目录
+func (s *sTemplateForks) draftFiles(ctx context.Context, templateId int64) (map[string]string, error) {
+	var files []*entity.TemplateFiles
+	err := dao.TemplateFiles.Ctx(ctx).
+		Fields("file_path, file_content").
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Where(dao.TemplateFiles.Columns().IsDirectory, 0).
+		Scan(&files)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取上游模板文件失败")
+	}
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		contents[file.FilePath] = file.FileContent
+	}
+	return contents, nil
+}
+
+func (s *sTemplateForks) findFork(ctx context.Context, templateId int64) (*entity.TemplateForks, error) {
+	var fork *entity.TemplateForks
+	err := dao.TemplateForks.Ctx(ctx).Where(dao.TemplateForks.Columns().TemplateId, templateId).Scan(&fork)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板上游来源失败")
+	}
+	if fork == nil {
+		return nil, gerror.New("该模板不是 Fork 出的模板，没有上游来源")
+	}
+	return fork, nil
+}
+
+func (s *sTemplateForks) toUpstreamInfo(ctx context.Context, fork *entity.TemplateForks) (*model.TemplateUpstreamInfo, error) {
+	name, err := dao.Templates.Ctx(ctx).WherePri(fork.UpstreamTemplateId).Value(dao.Templates.Columns().Name)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取上游模板失败")
+	}
+	var base map[string]string
+	_ = json.Unmarshal([]byte(fork.BaseFiles), &base)
+
+	info := &model.TemplateUpstreamInfo{
+		TemplateId:         fork.TemplateId,
+		UpstreamTemplateId: fork.UpstreamTemplateId,
+		UpstreamName:       name.String(),
+		BaseVersion:        fork.BaseVersion,
+		BaseFileCount:      len(base),
+		SyncedBy:           fork.SyncedBy,
+	}
+	if fork.SyncedAt != nil {
+		info.SyncedAt = fork.SyncedAt.Format("Y-m-d H:i:s")
+	}
+	if fork.CreatedAt != nil {
+		info.ForkedAt = fork.CreatedAt.Format("Y-m-d H:i:s")
+	}
+	return info, nil
+}
@@ -0,0 +1,125 @@
+package template_git
+
+import (
+	"path"
+	"strings"
+)
+
+// ignoreRule .gitignore 中的一条规则
+type ignoreRule struct {
+	base     string   // .gitignore 所在目录，仓库根目录为空
+	segments []string // 按 / 拆分的模式
+	negate   bool     // ! 开头，重新包含之前被忽略的路径
+	dirOnly  bool     // / 结尾，只匹配目录
+	anchored bool     // 含有 /，相对 .gitignore 所在目录匹配，否则匹配任意层级的名称
+}
+
+// ignoreMatcher 按 git 的规则判断路径是否被 .gitignore 忽略：
+// 后出现的规则优先，子目录中的 .gitignore 晚于上级目录加载；
+// 目录被忽略后其中的文件都被忽略，不能再用 ! 重新包含
+type ignoreMatcher struct {
+	rules []*ignoreRule
+}
+
+// add 加载 base 目录下 .gitignore 的内容，需按目录层级从浅到深依次加载
+func (m *ignoreMatcher) add(base, content string) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if rule := parseIgnoreRule(base, line); rule != nil {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+func parseIgnoreRule(base, line string) *ignoreRule {
+	// 行尾未转义的空格不属于模式
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimSuffix(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	rule := &ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+	rule.anchored = strings.Contains(line, "/")
+	rule.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	return rule
+}
+
+// ignored 判断仓库中的文件是否被忽略，filePath 为相对仓库根目录的路径
+func (m *ignoreMatcher) ignored(filePath string) bool {
+	if len(m.rules) == 0 {
+		return false
+	}
+	parts := strings.Split(filePath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(filePath, false)
+}
+
+// match 按最后一条匹配的规则判断单个路径是否被忽略
+func (m *ignoreMatcher) match(entryPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.negate == ignored && rule.matches(entryPath, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (r *ignoreRule) matches(entryPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel := entryPath
+	if r.base != "" {
+		if !strings.HasPrefix(entryPath, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(entryPath, r.base+"/")
+	}
+	if !r.anchored {
+		return matchSegment(r.segments[0], path.Base(rel))
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments 逐级匹配路径，** 匹配任意层级（包括零层）
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 || !matchSegment(pattern[0], parts[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+func matchSegment(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
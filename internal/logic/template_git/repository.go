@@ -0,0 +1,245 @@
+package template_git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 仓库树中的特殊文件模式
+const (
+	gitModeSymlink   = "120000"
+	gitModeSubmodule = "160000"
+)
+
+// gitRepository 服务器本地的 git 仓库，通过 git 命令只读访问
+type gitRepository struct {
+	dir     string
+	timeout time.Duration // 单条命令的执行时长上限
+}
+
+// gitTreeEntry 提交的文件树中的一项
+type gitTreeEntry struct {
+	Mode   string
+	Type   string // blob 或 commit（子模块）
+	Object string
+	Size   int64
+	Path   string // 相对仓库根目录的路径
+}
+
+// openRepository 解析仓库地址并校验其位于 git.importRoots 配置的目录下，
+// 只支持本地绝对路径和 file:// 地址
+func openRepository(ctx context.Context, repository string, roots []string, timeout time.Duration) (*gitRepository, error) {
+	if len(roots) == 0 {
+		return nil, gerror.New("未配置允许导入的本地仓库目录 git.importRoots，无法从git仓库导入")
+	}
+	dir := strings.TrimSpace(repository)
+	if strings.HasPrefix(dir, "file://") {
+		u, err := url.Parse(dir)
+		if err != nil || (u.Host != "" && u.Host != "localhost") {
+			return nil, gerror.Newf("仓库地址 %s 无效", repository)
+		}
+		dir = u.Path
+	} else if strings.Contains(dir, "://") {
+		return nil, gerror.Newf("仓库地址 %s 无效，只支持服务器本地路径或 file:// 地址", repository)
+	}
+	if !filepath.IsAbs(dir) {
+		return nil, gerror.Newf("仓库路径 %s 必须为绝对路径", repository)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Clean(dir))
+	if err != nil {
+		return nil, gerror.Newf("仓库 %s 不存在", repository)
+	}
+	if !withinRoots(dir, roots) {
+		return nil, gerror.Newf("仓库 %s 不在允许导入的目录中", repository)
+	}
+
+	repo := &gitRepository{dir: dir, timeout: timeout}
+	if _, err = repo.run(ctx, nil, "rev-parse", "--git-dir"); err != nil {
+		return nil, gerror.Wrapf(err, "%s 不是git仓库", repository)
+	}
+	return repo, nil
+}
+
+func withinRoots(dir string, roots []string) bool {
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			root = resolved
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// run 在仓库目录执行 git 命令，失败时错误信息包含 git 的输出
+func (r *gitRepository) run(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	cmd := r.command(ctx, args...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, gerror.Newf("git %s 执行超时", args[0])
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, gerror.Newf("git %s: %s", args[0], msg)
+		}
+		return nil, gerror.Wrapf(err, "git %s 执行失败", args[0])
+	}
+	return stdout.Bytes(), nil
+}
+
+func (r *gitRepository) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	return cmd
+}
+
+// resolveCommit 将分支、标签或提交解析为完整的提交哈希
+func (r *gitRepository) resolveCommit(ctx context.Context, ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", gerror.Newf("引用 %s 无效", ref)
+	}
+	out, err := r.run(ctx, nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", gerror.Newf("仓库中不存在引用 %s", ref)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// hasCommit 判断提交是否仍在仓库中，如被强制推送覆盖后已不可达的提交可能已被清理
+func (r *gitRepository) hasCommit(ctx context.Context, commit string) bool {
+	_, err := r.run(ctx, nil, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// subject 获取提交说明的首行
+func (r *gitRepository) subject(ctx context.Context, commit string) (string, error) {
+	out, err := r.run(ctx, nil, "log", "-1", "--format=%s", commit)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// commitsBetween 获取 from 之后到 to 为止的提交，从新到旧排列，最多 limit 条
+func (r *gitRepository) commitsBetween(ctx context.Context, from, to string, limit int) ([]*model.TemplateGitCommit, error) {
+	out, err := r.run(ctx, nil, "log", "--max-count="+strconv.Itoa(limit), "--format=%h%x09%s", from+".."+to)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*model.TemplateGitCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		hash, subject, _ := strings.Cut(line, "\t")
+		commits = append(commits, &model.TemplateGitCommit{Commit: hash, Subject: subject})
+	}
+	return commits, nil
+}
+
+// listTree 列出提交中的全部文件，不含目录
+func (r *gitRepository) listTree(ctx context.Context, commit string) ([]*gitTreeEntry, error) {
+	out, err := r.run(ctx, nil, "ls-tree", "-r", "-z", "--long", "--full-tree", commit)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*gitTreeEntry
+	for _, record := range strings.Split(string(out), "\x00") {
+		if record == "" {
+			continue
+		}
+		meta, filePath, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 4 {
+			return nil, gerror.Newf("无法解析 git ls-tree 的输出: %q", record)
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		entries = append(entries, &gitTreeEntry{
+			Mode:   fields[0],
+			Type:   fields[1],
+			Object: fields[2],
+			Size:   size,
+			Path:   filePath,
+		})
+	}
+	return entries, nil
+}
+
+// readBlobs 批量读取文件内容，按对象哈希索引
+func (r *gitRepository) readBlobs(ctx context.Context, objects []string) (map[string][]byte, error) {
+	blobs := make(map[string][]byte, len(objects))
+	var pending []string
+	for _, object := range objects {
+		if _, ok := blobs[object]; !ok {
+			blobs[object] = nil
+			pending = append(pending, object)
+		}
+	}
+	if len(pending) == 0 {
+		return blobs, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	cmd := r.command(ctx, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(pending, "\n") + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, gerror.Wrap(err, "git cat-file 执行失败")
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, gerror.Wrap(err, "git cat-file 执行失败")
+	}
+
+	// 输出格式：<对象> <类型> <大小>\n<内容>\n
+	reader := bufio.NewReader(stdout)
+	for range pending {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, gerror.Wrap(err, "读取git对象失败")
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, gerror.Newf("读取git对象失败: %s", strings.TrimSpace(header))
+		}
+		size, _ := strconv.Atoi(fields[2])
+		content := make([]byte, size+1)
+		if _, err = io.ReadFull(reader, content); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, gerror.Wrapf(err, "读取git对象 %s 失败", fields[0])
+		}
+		blobs[fields[0]] = content[:size]
+	}
+	if err = cmd.Wait(); err != nil {
+		return nil, gerror.Wrapf(err, "git cat-file 执行失败: %s", strings.TrimSpace(stderr.String()))
+	}
+	return blobs, nil
+}
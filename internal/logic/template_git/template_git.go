@@ -0,0 +1,417 @@
+package template_git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/gconv"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	api "github.com/ciclebyte/template_starter/api/v1/template_git"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+const (
+	defaultGitImportMaxSize = 50 * 1024 * 1024
+	defaultGitTimeout       = 60 * time.Second
+	maxReportedCommits      = 100
+	binarySniffLength       = 8000 // 与 git 判断二进制文件时检查的长度一致
+)
+
+type sTemplateGit struct{}
+
+func init() {
+	service.RegisterTemplateGit(New())
+}
+
+func New() *sTemplateGit {
+	return &sTemplateGit{}
+}
+
+// Import 将本地 git 仓库某个引用的文件树同步为模板的文件树：
+// 新增和修改的文件写入模板，仓库中已不存在的文件从模板删除，被 .gitignore 忽略的文件不导入。
+// 已有文件的生成条件等设置保持不变，修改和删除都会记录文件修订。
+// 仓库、引用和子目录未指定时沿用上次导入的设置
+//
+//	git:
+//	  importRoots: ["/srv/scaffolds"]
+//	  importMaxSize: 52428800
+//	  timeout: "60s"
+func (s *sTemplateGit) Import(ctx context.Context, req *api.TemplateGitImportReq) (res *api.TemplateGitImportRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		count, err := dao.Templates.Ctx(ctx).WherePri(req.TemplateId).Count()
+		liberr.ErrIsNil(ctx, err, "获取模板信息失败")
+		if count == 0 {
+			liberr.ErrIsNil(ctx, gerror.New("模板不存在"))
+		}
+		previous, err := s.findSource(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err, "获取模板git来源失败")
+
+		repository, ref, subdir := strings.TrimSpace(req.Repository), strings.TrimSpace(req.Ref), strings.TrimSpace(req.Subdir)
+		if previous != nil {
+			if repository == "" {
+				repository = previous.Repository
+			}
+			if ref == "" {
+				ref = previous.Ref
+			}
+			if subdir == "" {
+				subdir = previous.Subdir
+			}
+		}
+		if repository == "" {
+			liberr.ErrIsNil(ctx, gerror.New("仓库路径不能为空"))
+		}
+		if ref == "" {
+			ref = model.DefaultGitRef
+		}
+		subdir, err = normalizeSubdir(subdir)
+		liberr.ErrIsNil(ctx, err)
+
+		timeout := g.Cfg().MustGet(ctx, "git.timeout", defaultGitTimeout).Duration()
+		if timeout <= 0 {
+			timeout = defaultGitTimeout
+		}
+		repo, err := openRepository(ctx, repository, g.Cfg().MustGet(ctx, "git.importRoots").Strings(), timeout)
+		liberr.ErrIsNil(ctx, err)
+		commit, err := repo.resolveCommit(ctx, ref)
+		liberr.ErrIsNil(ctx, err)
+		subject, err := repo.subject(ctx, commit)
+		liberr.ErrIsNil(ctx, err, "读取提交信息失败")
+
+		res = &api.TemplateGitImportRes{}
+		files, err := s.readFiles(ctx, repo, commit, subdir, res)
+		liberr.ErrIsNil(ctx, err)
+
+		if previous != nil {
+			res.PreviousCommit = previous.CommitSha
+			if previous.CommitSha != commit && repo.hasCommit(ctx, previous.CommitSha) {
+				res.Commits, err = repo.commitsBetween(ctx, previous.CommitSha, commit, maxReportedCommits)
+				liberr.ErrIsNil(ctx, err, "读取提交历史失败")
+			}
+		}
+
+		err = dao.TemplateGitSources.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			if err := s.syncFiles(ctx, req.TemplateId, files, res); err != nil {
+				return err
+			}
+			data := do.TemplateGitSources{
+				TemplateId:    req.TemplateId,
+				Repository:    repository,
+				Ref:           ref,
+				Subdir:        subdir,
+				CommitSha:     commit,
+				CommitMessage: truncate(subject, 500),
+			}
+			if r := g.RequestFromCtx(ctx); r != nil {
+				data.ImportedById = gconv.Uint64(r.GetCtxVar("user_id"))
+				data.ImportedBy = r.GetCtxVar("username").String()
+			}
+			var err error
+			if previous == nil {
+				_, err = dao.TemplateGitSources.Ctx(ctx).Insert(data)
+			} else {
+				_, err = dao.TemplateGitSources.Ctx(ctx).WherePri(previous.Id).Update(data)
+			}
+			return err
+		})
+		liberr.ErrIsNil(ctx, err, "导入git仓库失败")
+
+		res.Source, err = s.Source(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// Source 获取模板最近一次导入的git来源
+func (s *sTemplateGit) Source(ctx context.Context, templateId int64) (source *model.TemplateGitSourceInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		found, err := s.findSource(ctx, templateId)
+		liberr.ErrIsNil(ctx, err, "获取模板git来源失败")
+		liberr.ValueIsNil(found, "该模板尚未从git仓库导入")
+		source = toSourceInfo(found)
+	})
+	return
+}
+
+func (s *sTemplateGit) findSource(ctx context.Context, templateId int64) (source *entity.TemplateGitSources, err error) {
+	err = dao.TemplateGitSources.Ctx(ctx).Where(dao.TemplateGitSources.Columns().TemplateId, templateId).Scan(&source)
+	return
+}
+
+// readFiles 读取提交中子目录下未被 .gitignore 忽略的文本文件，返回相对子目录的路径到内容的映射；
+// 二进制文件、符号链接和子模块跳过并记录提示
+func (s *sTemplateGit) readFiles(ctx context.Context, repo *gitRepository, commit, subdir string, res *api.TemplateGitImportRes) (map[string]string, error) {
+	entries, err := repo.listTree(ctx, commit)
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取仓库文件列表失败")
+	}
+
+	// 按目录层级从浅到深加载 .gitignore，子目录的规则优先
+	var ignoreFiles []*gitTreeEntry
+	for _, entry := range entries {
+		if entry.Type == "blob" && entry.Mode != gitModeSymlink && path.Base(entry.Path) == ".gitignore" {
+			ignoreFiles = append(ignoreFiles, entry)
+		}
+	}
+	sort.SliceStable(ignoreFiles, func(i, j int) bool {
+		return strings.Count(ignoreFiles[i].Path, "/") < strings.Count(ignoreFiles[j].Path, "/")
+	})
+	ignoreObjects := make([]string, 0, len(ignoreFiles))
+	for _, entry := range ignoreFiles {
+		ignoreObjects = append(ignoreObjects, entry.Object)
+	}
+	ignoreContents, err := repo.readBlobs(ctx, ignoreObjects)
+	if err != nil {
+		return nil, err
+	}
+	matcher := &ignoreMatcher{}
+	for _, entry := range ignoreFiles {
+		base := path.Dir(entry.Path)
+		if base == "." {
+			base = ""
+		}
+		matcher.add(base, string(ignoreContents[entry.Object]))
+	}
+
+	maxSize := g.Cfg().MustGet(ctx, "git.importMaxSize", defaultGitImportMaxSize).Int64()
+	if maxSize <= 0 {
+		maxSize = defaultGitImportMaxSize
+	}
+	var selected []*gitTreeEntry
+	var totalSize int64
+	for _, entry := range entries {
+		relPath := entry.Path
+		if subdir != "" {
+			if !strings.HasPrefix(relPath, subdir+"/") {
+				continue
+			}
+			relPath = strings.TrimPrefix(relPath, subdir+"/")
+		}
+		if matcher.ignored(entry.Path) {
+			continue
+		}
+		switch {
+		case entry.Mode == gitModeSubmodule:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("子模块 %s 已跳过", relPath))
+			continue
+		case entry.Mode == gitModeSymlink:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("符号链接 %s 已跳过", relPath))
+			continue
+		case entry.Type != "blob":
+			continue
+		}
+		totalSize += entry.Size
+		if totalSize > maxSize {
+			return nil, gerror.Newf("仓库文件总大小超过 %d 字节的导入上限", maxSize)
+		}
+		selected = append(selected, entry)
+	}
+
+	objects := make([]string, 0, len(selected))
+	for _, entry := range selected {
+		objects = append(objects, entry.Object)
+	}
+	contents, err := repo.readBlobs(ctx, objects)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(selected))
+	for _, entry := range selected {
+		relPath := strings.TrimPrefix(entry.Path, subdir+"/")
+		content := contents[entry.Object]
+		if isBinary(content) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("二进制文件 %s 已跳过", relPath))
+			continue
+		}
+		files[relPath] = string(content)
+	}
+	if len(files) == 0 {
+		if subdir != "" {
+			return nil, gerror.Newf("仓库子目录 %s 中没有可导入的文件", subdir)
+		}
+		return nil, gerror.New("仓库中没有可导入的文件")
+	}
+	return files, nil
+}
+
+// syncFiles 按仓库内容增删改模板文件并在 res 中记录变更，模板中多余的目录一并删除
+func (s *sTemplateGit) syncFiles(ctx context.Context, templateId int64, files map[string]string, res *api.TemplateGitImportRes) error {
+	var existing []*entity.TemplateFiles
+	err := dao.TemplateFiles.Ctx(ctx).
+		Fields("id, file_path, file_content, is_directory").
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Scan(&existing)
+	if err != nil {
+		return gerror.Wrap(err, "获取模板文件失败")
+	}
+
+	dirs := make(map[string]bool)
+	for filePath := range files {
+		for dir := path.Dir(filePath); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	current := make(map[string]*entity.TemplateFiles)
+	dirIds := make(map[string]int64)
+	var deleteIds []int64
+	for _, file := range existing {
+		if file.IsDirectory == 1 {
+			if dirs[file.FilePath] {
+				dirIds[file.FilePath] = file.Id
+				continue
+			}
+		} else if _, ok := files[file.FilePath]; ok {
+			current[file.FilePath] = file
+			continue
+		} else {
+			res.Deleted = append(res.Deleted, file.FilePath)
+		}
+		deleteIds = append(deleteIds, file.Id)
+	}
+	if len(deleteIds) > 0 {
+		if err = service.TemplateFiles().BatchDelete(ctx, deleteIds); err != nil {
+			return err
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		content := files[filePath]
+		if file, ok := current[filePath]; ok {
+			if file.FileContent == content {
+				res.Unchanged++
+				continue
+			}
+			err = service.TemplateFiles().Edit(ctx, &filesApi.TemplateFilesEditReq{Id: file.Id, FileContent: content})
+			if err != nil {
+				return err
+			}
+			res.Modified = append(res.Modified, filePath)
+			continue
+		}
+
+		parentId, err := s.ensureDirectory(ctx, templateId, path.Dir(filePath), dirIds)
+		if err != nil {
+			return err
+		}
+		err = service.TemplateFiles().Add(ctx, &filesApi.TemplateFilesAddReq{
+			TemplateId:  templateId,
+			FileName:    path.Base(filePath),
+			FileContent: content,
+			FileSize:    len(content),
+			IsDirectory: 0,
+			Md5:         gmd5.MustEncryptString(content),
+			ParentId:    int(parentId),
+		})
+		if err != nil {
+			return err
+		}
+		res.Added = append(res.Added, filePath)
+	}
+	sort.Strings(res.Deleted)
+	return nil
+}
+
+// ensureDirectory 创建目录及其缺失的上级目录，返回目录ID，根目录为 0
+func (s *sTemplateGit) ensureDirectory(ctx context.Context, templateId int64, dir string, ids map[string]int64) (int64, error) {
+	if dir == "." {
+		return 0, nil
+	}
+	if id, ok := ids[dir]; ok {
+		return id, nil
+	}
+	parentId, err := s.ensureDirectory(ctx, templateId, path.Dir(dir), ids)
+	if err != nil {
+		return 0, err
+	}
+	err = service.TemplateFiles().Add(ctx, &filesApi.TemplateFilesAddReq{
+		TemplateId:  templateId,
+		FileName:    path.Base(dir),
+		IsDirectory: 1,
+		ParentId:    int(parentId),
+	})
+	if err != nil {
+		return 0, err
+	}
+	id, err := dao.TemplateFiles.Ctx(ctx).
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Where(dao.TemplateFiles.Columns().FilePath, dir).
+		Where(dao.TemplateFiles.Columns().IsDirectory, 1).
+		Value(dao.TemplateFiles.Columns().Id)
+	if err != nil {
+		return 0, gerror.Wrapf(err, "创建目录 %s 失败", dir)
+	}
+	ids[dir] = id.Int64()
+	return ids[dir], nil
+}
+
+// normalizeSubdir 规范化子目录，拒绝绝对路径和跳出仓库根目录的路径
+func normalizeSubdir(subdir string) (string, error) {
+	if subdir == "" {
+		return "", nil
+	}
+	normalized := path.Clean(strings.ReplaceAll(subdir, "\\", "/"))
+	if strings.HasPrefix(normalized, "/") || normalized == ".." || strings.HasPrefix(normalized, "../") {
+		return "", gerror.Newf("子目录 %s 无效", subdir)
+	}
+	if normalized == "." {
+		return "", nil
+	}
+	return normalized, nil
+}
+
+// isBinary 与 git 一致，内容开头包含 NUL 字节的视为二进制文件；非 UTF-8 编码的文件也无法作为模板保存
+func isBinary(content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffLength {
+		sniff = sniff[:binarySniffLength]
+	}
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(content)
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
+
+func toSourceInfo(source *entity.TemplateGitSources) *model.TemplateGitSourceInfo {
+	info := &model.TemplateGitSourceInfo{
+		TemplateId:    source.TemplateId,
+		Repository:    source.Repository,
+		Ref:           source.Ref,
+		Subdir:        source.Subdir,
+		Commit:        source.CommitSha,
+		CommitMessage: source.CommitMessage,
+		ImportedBy:    source.ImportedBy,
+	}
+	if source.UpdatedAt != nil {
+		info.ImportedAt = source.UpdatedAt.Format("Y-m-d H:i:s")
+	} else if source.CreatedAt != nil {
+		info.ImportedAt = source.CreatedAt.Format("Y-m-d H:i:s")
+	}
+	return info
+}
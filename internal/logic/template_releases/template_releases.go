@@ -0,0 +1,285 @@
+package template_releases
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	exposeApi "github.com/ciclebyte/template_starter/api/v1/template_expose"
+	api "github.com/ciclebyte/template_starter/api/v1/template_releases"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+type sTemplateReleases struct{}
+
+func init() {
+	service.RegisterTemplateReleases(New())
+}
+
+func New() *sTemplateReleases {
+	return &sTemplateReleases{}
+}
+
+// List 获取模板发布版本列表，按版本号从高到低排列
+func (s *sTemplateReleases) List(ctx context.Context, req *api.TemplateReleasesListReq) (releases []*model.TemplateReleaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		all, err := s.releases(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err, "获取模板发布版本失败")
+
+		latest := latestRelease(all)
+		releases = make([]*model.TemplateReleaseInfo, 0, len(all))
+		for _, release := range all {
+			if release.Status == model.ReleaseStatusDeprecated && !req.IncludeDeprecated {
+				continue
+			}
+			releases = append(releases, toReleaseInfo(release, release == latest))
+		}
+	})
+	return
+}
+
+// Publish 以模板当前的文件树、生成条件和变量定义发布新版本，版本号不能重复
+func (s *sTemplateReleases) Publish(ctx context.Context, req *api.TemplateReleasesPublishReq) (release *model.TemplateReleaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		version, err := normalizeVersion(req.Version)
+		liberr.ErrIsNil(ctx, err)
+
+		count, err := dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Id, req.TemplateId).Count()
+		liberr.ErrIsNil(ctx, err, "获取模板信息失败")
+		if count == 0 {
+			liberr.ErrIsNil(ctx, gerror.New("模板不存在"))
+		}
+
+		count, err = dao.TemplateReleases.Ctx(ctx).
+			Where(dao.TemplateReleases.Columns().TemplateId, req.TemplateId).
+			Where(dao.TemplateReleases.Columns().Version, version).
+			Count()
+		liberr.ErrIsNil(ctx, err, "查询模板发布版本失败")
+		if count > 0 {
+			liberr.ErrIsNil(ctx, gerror.Newf("版本 %s 已发布，发布后的版本不可修改，请使用新的版本号", version))
+		}
+
+		snapshot, err := s.buildSnapshot(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err, "生成模板快照失败")
+		snapshotJson, err := json.Marshal(snapshot)
+		liberr.ErrIsNil(ctx, err, "生成模板快照失败")
+
+		fileCount := 0
+		for _, file := range snapshot.Files {
+			if file.IsDirectory == 0 {
+				fileCount++
+			}
+		}
+
+		id, err := dao.TemplateReleases.Ctx(ctx).InsertAndGetId(do.TemplateReleases{
+			TemplateId: req.TemplateId,
+			Version:    version,
+			Status:     model.ReleaseStatusPublished,
+			Changelog:  req.Changelog,
+			Snapshot:   string(snapshotJson),
+			FileCount:  fileCount,
+		})
+		liberr.ErrIsNil(ctx, err, "发布模板版本失败")
+
+		var saved *entity.TemplateReleases
+		err = dao.TemplateReleases.Ctx(ctx).FieldsEx(dao.TemplateReleases.Columns().Snapshot).WherePri(id).Scan(&saved)
+		liberr.ErrIsNil(ctx, err, "获取模板发布版本失败")
+
+		all, err := s.releases(ctx, req.TemplateId)
+		liberr.ErrIsNil(ctx, err, "获取模板发布版本失败")
+		latest := latestRelease(all)
+		release = toReleaseInfo(saved, latest != nil && latest.Id == saved.Id)
+	})
+	return
+}
+
+// Deprecate 废弃发布版本，已废弃的版本不再作为默认渲染版本，但仍可按版本号显式渲染
+func (s *sTemplateReleases) Deprecate(ctx context.Context, req *api.TemplateReleasesDeprecateReq) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		release, err := s.findRelease(ctx, req.TemplateId, req.Version)
+		liberr.ErrIsNil(ctx, err)
+		if release.Status == model.ReleaseStatusDeprecated {
+			return
+		}
+
+		_, err = dao.TemplateReleases.Ctx(ctx).WherePri(release.Id).Update(do.TemplateReleases{
+			Status:           model.ReleaseStatusDeprecated,
+			DeprecatedReason: req.Reason,
+			DeprecatedAt:     gtime.Now(),
+		})
+		liberr.ErrIsNil(ctx, err, "废弃模板发布版本失败")
+	})
+	return
+}
+
+// LoadSnapshot 获取渲染所用的发布版本快照。version 为空或 latest 时使用最新发布版本，
+// 模板尚未发布任何版本或 version 为 draft 时返回 nil，表示使用模板当前的工作区内容
+func (s *sTemplateReleases) LoadSnapshot(ctx context.Context, templateId int64, version string) (release *model.TemplateReleaseInfo, snapshot *model.TemplateReleaseSnapshot, err error) {
+	var found *entity.TemplateReleases
+	isLatest := false
+	switch strings.ToLower(strings.TrimSpace(version)) {
+	case model.ReleaseVersionDraft:
+		return nil, nil, nil
+	case "", model.ReleaseVersionLatest:
+		all, err := s.releases(ctx, templateId)
+		if err != nil {
+			return nil, nil, gerror.Wrap(err, "获取模板发布版本失败")
+		}
+		if found = latestRelease(all); found == nil {
+			return nil, nil, nil
+		}
+		isLatest = true
+	default:
+		if found, err = s.findRelease(ctx, templateId, version); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 列表查询不含快照内容，按ID单独读取
+	value, err := dao.TemplateReleases.Ctx(ctx).WherePri(found.Id).Value(dao.TemplateReleases.Columns().Snapshot)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "获取模板发布版本失败")
+	}
+	snapshot = new(model.TemplateReleaseSnapshot)
+	if err = json.Unmarshal([]byte(value.String()), snapshot); err != nil {
+		return nil, nil, gerror.Wrapf(err, "版本 %s 的快照已损坏", found.Version)
+	}
+	if snapshot.Delimiters == nil {
+		snapshot.Delimiters = &model.TemplateDelimiters{Left: model.DefaultLeftDelim, Right: model.DefaultRightDelim}
+	}
+	return toReleaseInfo(found, isLatest), snapshot, nil
+}
+
+// buildSnapshot 读取模板当前的文件树、变量定义和定界符
+func (s *sTemplateReleases) buildSnapshot(ctx context.Context, templateId int64) (*model.TemplateReleaseSnapshot, error) {
+	snapshot := &model.TemplateReleaseSnapshot{}
+	err := dao.TemplateFiles.Ctx(ctx).
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		OrderAsc(dao.TemplateFiles.Columns().Id).
+		Scan(&snapshot.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	expose, err := service.TemplateExpose().Get(ctx, &exposeApi.TemplateExposeGetReq{TemplateId: templateId})
+	if err != nil {
+		return nil, err
+	}
+	if expose != nil {
+		snapshot.FieldSchemaJson = expose.FieldSchemaJson
+	}
+	if snapshot.Variables, err = service.TemplateExpose().GetVariableDefs(ctx, templateId); err != nil {
+		return nil, err
+	}
+	if snapshot.Delimiters, err = service.Templates().GetDelimiters(ctx, templateId); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// releases 获取模板的全部发布版本（不含快照内容），按版本号从高到低排列
+func (s *sTemplateReleases) releases(ctx context.Context, templateId int64) ([]*entity.TemplateReleases, error) {
+	var releases []*entity.TemplateReleases
+	err := dao.TemplateReleases.Ctx(ctx).
+		FieldsEx(dao.TemplateReleases.Columns().Snapshot).
+		Where(dao.TemplateReleases.Columns().TemplateId, templateId).
+		Scan(&releases)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return compareVersions(releases[i].Version, releases[j].Version) > 0
+	})
+	return releases, nil
+}
+
+// findRelease 按版本号查找发布版本（不含快照内容）
+func (s *sTemplateReleases) findRelease(ctx context.Context, templateId int64, version string) (*entity.TemplateReleases, error) {
+	normalized, err := normalizeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	var release *entity.TemplateReleases
+	err = dao.TemplateReleases.Ctx(ctx).
+		FieldsEx(dao.TemplateReleases.Columns().Snapshot).
+		Where(dao.TemplateReleases.Columns().TemplateId, templateId).
+		Where(dao.TemplateReleases.Columns().Version, normalized).
+		Scan(&release)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询模板发布版本失败")
+	}
+	if release == nil {
+		return nil, gerror.Newf("模板版本 %s 不存在", normalized)
+	}
+	return release, nil
+}
+
+// normalizeVersion 校验并规范化语义化版本号，允许带 v 前缀，如 v1.2.0 规范化为 1.2.0
+func normalizeVersion(version string) (string, error) {
+	version = strings.TrimSpace(version)
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return "", gerror.Newf("版本号 %q 不是有效的语义化版本，格式应为 主版本.次版本.修订号，如 1.2.0", version)
+	}
+	return v.String(), nil
+}
+
+// latestRelease 返回默认渲染的版本：已发布的最高正式版本，没有正式版本时为最高预发布版本
+func latestRelease(releases []*entity.TemplateReleases) *entity.TemplateReleases {
+	var latest *entity.TemplateReleases
+	for _, release := range releases {
+		if release.Status != model.ReleaseStatusPublished {
+			continue
+		}
+		if latest == nil || (isPrerelease(latest.Version) && !isPrerelease(release.Version)) {
+			latest = release
+		}
+	}
+	return latest
+}
+
+func isPrerelease(version string) bool {
+	v, err := semver.NewVersion(version)
+	return err == nil && v.Prerelease() != ""
+}
+
+// compareVersions 按语义化版本比较，无法解析的版本按字符串比较
+func compareVersions(a, b string) int {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+func toReleaseInfo(release *entity.TemplateReleases, isLatest bool) *model.TemplateReleaseInfo {
+	info := &model.TemplateReleaseInfo{
+		Id:               release.Id,
+		TemplateId:       release.TemplateId,
+		Version:          release.Version,
+		Status:           release.Status,
+		Changelog:        release.Changelog,
+		DeprecatedReason: release.DeprecatedReason,
+		FileCount:        release.FileCount,
+		IsLatest:         isLatest,
+	}
+	if release.DeprecatedAt != nil {
+		info.DeprecatedAt = release.DeprecatedAt.Format("Y-m-d H:i:s")
+	}
+	if release.CreatedAt != nil {
+		info.CreatedAt = release.CreatedAt.Format("Y-m-d H:i:s")
+	}
+	return info
+}
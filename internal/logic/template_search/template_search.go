@@ -0,0 +1,337 @@
+package template_search
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/pmezard/go-difflib/difflib"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	api "github.com/ciclebyte/template_starter/api/v1/template_search"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+type sTemplateSearch struct{}
+
+func init() {
+	service.RegisterTemplateSearch(New())
+}
+
+func New() *sTemplateSearch {
+	return &sTemplateSearch{}
+}
+
+// searchFile 参与搜索的文件及所属模板名称
+type searchFile struct {
+	*entity.TemplateFiles
+	TemplateName string
+}
+
+// replacePlan 替换对单个文件的修改
+type replacePlan struct {
+	file    *searchFile
+	content string
+	count   int
+}
+
+// Search 按文本或正则表达式搜索模板文件内容，返回每处匹配的行号、列号和上下文
+func (s *sTemplateSearch) Search(ctx context.Context, req *api.TemplateSearchReq) (res *api.TemplateSearchRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		re, err := compileQuery(&req.TemplateSearchQuery)
+		liberr.ErrIsNil(ctx, err)
+		files, err := s.files(ctx, &req.TemplateSearchQuery, nil)
+		liberr.ErrIsNil(ctx, err)
+
+		res = &api.TemplateSearchRes{Files: []*model.TemplateSearchFile{}}
+		for _, file := range files {
+			locations := findMatches(re, file.FileContent)
+			if len(locations) == 0 {
+				continue
+			}
+			if res.Total+len(locations) > req.MaxResults {
+				locations = locations[:req.MaxResults-res.Total]
+				res.Truncated = true
+			}
+			if len(locations) > 0 {
+				res.Files = append(res.Files, &model.TemplateSearchFile{
+					TemplateId:   int64(file.TemplateId),
+					TemplateName: file.TemplateName,
+					FileId:       file.Id,
+					FilePath:     file.FilePath,
+					Matches:      describeMatches(file.Id, file.FileContent, locations, req.ContextLines),
+				})
+				res.Total += len(locations)
+			}
+			if res.Truncated {
+				break
+			}
+		}
+	})
+	return
+}
+
+// Replace 在一个事务中替换搜索结果中选中的匹配，修改会记录文件修订历史。
+// 替换前按相同条件重新搜索，选中的匹配不存在时说明文件已被修改，整体不做替换
+func (s *sTemplateSearch) Replace(ctx context.Context, req *api.TemplateReplaceReq) (res *api.TemplateReplaceRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		re, err := compileQuery(&req.TemplateSearchQuery)
+		liberr.ErrIsNil(ctx, err)
+		selected, fileIds, err := parseMatchIds(req.MatchIds)
+		liberr.ErrIsNil(ctx, err)
+
+		res = &api.TemplateReplaceRes{Files: []*model.TemplateReplaceFile{}}
+		err = dao.TemplateFiles.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			files, err := s.files(ctx, &req.TemplateSearchQuery, fileIds)
+			if err != nil {
+				return err
+			}
+			var plans []*replacePlan
+			for _, file := range files {
+				if plan := replaceSelected(re, file, req.Replacement, req.Regex, selected); plan != nil {
+					plans = append(plans, plan)
+				}
+			}
+			for _, id := range req.MatchIds {
+				if selected[id] {
+					return gerror.Newf("匹配 %s 已不存在，文件内容可能已被修改，请重新搜索", id)
+				}
+			}
+
+			for _, plan := range plans {
+				err := service.TemplateFiles().Edit(ctx, &filesApi.TemplateFilesEditReq{Id: plan.file.Id, FileContent: plan.content})
+				if err != nil {
+					return gerror.Wrapf(err, "修改 %s 失败", plan.file.FilePath)
+				}
+				res.Files = append(res.Files, &model.TemplateReplaceFile{
+					TemplateId:   int64(plan.file.TemplateId),
+					TemplateName: plan.file.TemplateName,
+					FileId:       plan.file.Id,
+					FilePath:     plan.file.FilePath,
+					Replaced:     plan.count,
+					Diff:         unifiedDiff(plan.file.FilePath, plan.file.FileContent, plan.content),
+				})
+				res.Replaced += plan.count
+			}
+			return nil
+		})
+		liberr.ErrIsNil(ctx, err, "替换失败")
+	})
+	return
+}
+
+// files 获取参与搜索的文件，按模板和路径排列。接口需要 template:edit 权限，模板目前没有所有者，
+// 未指定模板时搜索不在回收站中的全部模板，即当前用户可以编辑的全部模板；限定了模板的API Key只搜索限定的模板
+func (s *sTemplateSearch) files(ctx context.Context, query *api.TemplateSearchQuery, fileIds []int64) ([]*searchFile, error) {
+	m := dao.Templates.Ctx(ctx).Fields(dao.Templates.Columns().Id, dao.Templates.Columns().Name)
+	if query.TemplateId > 0 {
+		if err := service.Middleware().CheckApiKeyTemplates(ctx, []int64{query.TemplateId}); err != nil {
+			return nil, err
+		}
+		m = m.Where(dao.Templates.Columns().Id, query.TemplateId)
+	} else if scopeIds, limited := service.Middleware().ApiKeyTemplateIds(ctx); limited {
+		m = m.WhereIn(dao.Templates.Columns().Id, scopeIds)
+	}
+	var templates []*entity.Templates
+	if err := m.Scan(&templates); err != nil {
+		return nil, gerror.Wrap(err, "获取模板失败")
+	}
+	if query.TemplateId > 0 && len(templates) == 0 {
+		return nil, gerror.Newf("模板ID %d 不存在", query.TemplateId)
+	}
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	names := make(map[int64]string, len(templates))
+	templateIds := make([]int64, 0, len(templates))
+	for _, template := range templates {
+		names[template.Id] = template.Name
+		templateIds = append(templateIds, template.Id)
+	}
+
+	columns := dao.TemplateFiles.Columns()
+	fm := dao.TemplateFiles.Ctx(ctx).
+		WhereIn(columns.TemplateId, templateIds).
+		Where(columns.IsDirectory, 0)
+	if fileIds != nil {
+		fm = fm.WhereIn(columns.Id, fileIds)
+	}
+	// 按文本搜索时先用 LIKE 缩小范围，完整单词在读取后判断。不区分大小写时两边都转为小写，
+	// 避免依赖字段的排序规则
+	if !query.Regex {
+		if query.CaseSensitive {
+			fm = fm.WhereLike(columns.FileContent, "%"+escapeLike(query.Query)+"%")
+		} else {
+			fm = fm.Where(fmt.Sprintf("LOWER(%s) LIKE ?", columns.FileContent), "%"+escapeLike(strings.ToLower(query.Query))+"%")
+		}
+	}
+	var files []*entity.TemplateFiles
+	err := fm.OrderAsc(columns.TemplateId).OrderAsc(columns.FilePath).Scan(&files)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板文件失败")
+	}
+	result := make([]*searchFile, 0, len(files))
+	for _, file := range files {
+		result = append(result, &searchFile{TemplateFiles: file, TemplateName: names[int64(file.TemplateId)]})
+	}
+	return result, nil
+}
+
+// compileQuery 将搜索条件编译为正则表达式，按文本搜索时转义其中的特殊字符
+func compileQuery(query *api.TemplateSearchQuery) (*regexp.Regexp, error) {
+	pattern := query.Query
+	if !query.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if query.WholeWord {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	if !query.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, gerror.Wrap(err, "正则表达式无效")
+	}
+	if re.MatchString("") {
+		return nil, gerror.New("搜索内容不能匹配空字符串")
+	}
+	return re, nil
+}
+
+// findMatches 返回内容中全部非空匹配的位置，包含分组的位置以便替换时引用
+func findMatches(re *regexp.Regexp, content string) [][]int {
+	var locations [][]int
+	for _, loc := range re.FindAllStringSubmatchIndex(content, -1) {
+		if loc[1] > loc[0] {
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
+
+// describeMatches 计算每处匹配的行号、列号和上下文行
+func describeMatches(fileId int64, content string, locations [][]int, contextLines int) []*model.TemplateSearchMatch {
+	lines := strings.Split(content, "\n")
+	starts := make([]int, len(lines))
+	offset := 0
+	for i, line := range lines {
+		starts[i] = offset
+		offset += len(line) + 1
+	}
+
+	matches := make([]*model.TemplateSearchMatch, 0, len(locations))
+	for _, loc := range locations {
+		line := sort.Search(len(starts), func(i int) bool { return starts[i] > loc[0] }) - 1
+		match := &model.TemplateSearchMatch{
+			Id:     matchId(fileId, loc[0]),
+			Line:   line + 1,
+			Column: utf8.RuneCountInString(content[starts[line]:loc[0]]) + 1,
+			Text:   trimCR(lines[line]),
+			Match:  content[loc[0]:loc[1]],
+			Before: []string{},
+			After:  []string{},
+		}
+		for i := max(0, line-contextLines); i < line; i++ {
+			match.Before = append(match.Before, trimCR(lines[i]))
+		}
+		for i := line + 1; i < len(lines) && i <= line+contextLines; i++ {
+			match.After = append(match.After, trimCR(lines[i]))
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// replaceSelected 替换文件中选中的匹配，已替换的匹配从 selected 中移除，没有选中的匹配时返回 nil
+func replaceSelected(re *regexp.Regexp, file *searchFile, replacement string, expand bool, selected map[string]bool) *replacePlan {
+	content := file.FileContent
+	var out strings.Builder
+	last, count := 0, 0
+	for _, loc := range findMatches(re, content) {
+		id := matchId(file.Id, loc[0])
+		if !selected[id] {
+			continue
+		}
+		delete(selected, id)
+		out.WriteString(content[last:loc[0]])
+		if expand {
+			out.Write(re.ExpandString(nil, replacement, content, loc))
+		} else {
+			out.WriteString(replacement)
+		}
+		last = loc[1]
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	out.WriteString(content[last:])
+	return &replacePlan{file: file, content: out.String(), count: count}
+}
+
+// matchId 匹配ID由文件ID和匹配在内容中的字节偏移组成
+func matchId(fileId int64, offset int) string {
+	return fmt.Sprintf("%d:%d", fileId, offset)
+}
+
+// parseMatchIds 校验匹配ID，返回待替换的匹配集合和涉及的文件ID
+func parseMatchIds(ids []string) (map[string]bool, []int64, error) {
+	selected := make(map[string]bool, len(ids))
+	var fileIds []int64
+	seenFiles := make(map[int64]bool)
+	for _, id := range ids {
+		parts := strings.Split(id, ":")
+		if len(parts) != 2 {
+			return nil, nil, gerror.Newf("匹配ID %s 格式错误", id)
+		}
+		fileId, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, nil, gerror.Newf("匹配ID %s 格式错误", id)
+		}
+		if _, err := strconv.Atoi(parts[1]); err != nil {
+			return nil, nil, gerror.Newf("匹配ID %s 格式错误", id)
+		}
+		selected[id] = true
+		if !seenFiles[fileId] {
+			seenFiles[fileId] = true
+			fileIds = append(fileIds, fileId)
+		}
+	}
+	return selected, fileIds, nil
+}
+
+// escapeLike 转义 LIKE 中的通配符和转义字符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func trimCR(line string) string {
+	return strings.TrimSuffix(line, "\r")
+}
+
+// unifiedDiff 生成文件内容替换前后的 unified diff
+func unifiedDiff(filePath, from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: "a/" + filePath,
+		ToFile:   "b/" + filePath,
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
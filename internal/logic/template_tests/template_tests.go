@@ -0,0 +1,481 @@
+package template_tests
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/pmezard/go-difflib/difflib"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	api "github.com/ciclebyte/template_starter/api/v1/template_tests"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+type sTemplateTests struct{}
+
+func init() {
+	service.RegisterTemplateTests(New())
+}
+
+func New() *sTemplateTests {
+	return &sTemplateTests{}
+}
+
+// List 获取模板测试用例列表，按名称排列，不返回期望的渲染结果
+func (s *sTemplateTests) List(ctx context.Context, req *api.TemplateTestsListReq) (cases []*model.TemplateTestCaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		all, err := s.testCases(ctx, req.TemplateId, nil)
+		liberr.ErrIsNil(ctx, err, "获取模板测试用例失败")
+
+		cases = make([]*model.TemplateTestCaseInfo, 0, len(all))
+		for _, testCase := range all {
+			info, err := toTestCaseInfo(testCase)
+			liberr.ErrIsNil(ctx, err)
+			info.Expected = nil
+			cases = append(cases, info)
+		}
+	})
+	return
+}
+
+// Detail 获取模板测试用例详情
+func (s *sTemplateTests) Detail(ctx context.Context, req *api.TemplateTestsDetailReq) (testCase *model.TemplateTestCaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		found, err := s.findTestCase(ctx, req.TemplateId, req.Name)
+		liberr.ErrIsNil(ctx, err)
+		testCase, err = toTestCaseInfo(found)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// Add 新增模板测试用例，同一模板内用例名称不能重复
+func (s *sTemplateTests) Add(ctx context.Context, req *api.TemplateTestsAddReq) (testCase *model.TemplateTestCaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		count, err := dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Id, req.TemplateId).Count()
+		liberr.ErrIsNil(ctx, err, "获取模板信息失败")
+		if count == 0 {
+			liberr.ErrIsNil(ctx, gerror.New("模板不存在"))
+		}
+
+		count, err = dao.TemplateTestCases.Ctx(ctx).
+			Where(dao.TemplateTestCases.Columns().TemplateId, req.TemplateId).
+			Where(dao.TemplateTestCases.Columns().Name, req.Name).
+			Count()
+		liberr.ErrIsNil(ctx, err, "查询模板测试用例失败")
+		if count > 0 {
+			liberr.ErrIsNil(ctx, gerror.Newf("测试用例 %s 已存在", req.Name))
+		}
+
+		variables, err := encodeVariables(req.Variables)
+		liberr.ErrIsNil(ctx, err)
+		expected, err := encodeExpected(req.Expected)
+		liberr.ErrIsNil(ctx, err)
+
+		_, err = dao.TemplateTestCases.Ctx(ctx).Data(do.TemplateTestCases{
+			TemplateId:  req.TemplateId,
+			Name:        req.Name,
+			Description: req.Description,
+			Variables:   variables,
+			Expected:    expected,
+			FullTree:    boolToInt(req.FullTree),
+		}).Insert()
+		liberr.ErrIsNil(ctx, err, "新增模板测试用例失败")
+
+		found, err := s.findTestCase(ctx, req.TemplateId, req.Name)
+		liberr.ErrIsNil(ctx, err)
+		testCase, err = toTestCaseInfo(found)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// Edit 修改模板测试用例，未传的字段保持不变
+func (s *sTemplateTests) Edit(ctx context.Context, req *api.TemplateTestsEditReq) (testCase *model.TemplateTestCaseInfo, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		found, err := s.findTestCase(ctx, req.TemplateId, req.Name)
+		liberr.ErrIsNil(ctx, err)
+
+		data := do.TemplateTestCases{}
+		if req.Description != nil {
+			data.Description = *req.Description
+		}
+		if req.Variables != nil {
+			data.Variables, err = encodeVariables(req.Variables)
+			liberr.ErrIsNil(ctx, err)
+		}
+		if req.Expected != nil {
+			data.Expected, err = encodeExpected(req.Expected)
+			liberr.ErrIsNil(ctx, err)
+		}
+		if req.FullTree != nil {
+			data.FullTree = boolToInt(*req.FullTree)
+		}
+
+		_, err = dao.TemplateTestCases.Ctx(ctx).WherePri(found.Id).Data(data).Update()
+		liberr.ErrIsNil(ctx, err, "修改模板测试用例失败")
+
+		found, err = s.findTestCase(ctx, req.TemplateId, req.Name)
+		liberr.ErrIsNil(ctx, err)
+		testCase, err = toTestCaseInfo(found)
+		liberr.ErrIsNil(ctx, err)
+	})
+	return
+}
+
+// Delete 删除模板测试用例
+func (s *sTemplateTests) Delete(ctx context.Context, req *api.TemplateTestsDeleteReq) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		found, err := s.findTestCase(ctx, req.TemplateId, req.Name)
+		liberr.ErrIsNil(ctx, err)
+		_, err = dao.TemplateTestCases.Ctx(ctx).WherePri(found.Id).Delete()
+		liberr.ErrIsNil(ctx, err, "删除模板测试用例失败")
+	})
+	return
+}
+
+// Run 按用例的变量渲染模板并与期望结果逐个文件比较，accept 模式下以渲染结果覆盖期望结果
+func (s *sTemplateTests) Run(ctx context.Context, req *api.TemplateTestsRunReq) (res *api.TemplateTestsRunRes, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		version := req.Version
+		if version == "" {
+			version = model.ReleaseVersionDraft
+		}
+
+		cases, err := s.testCases(ctx, req.TemplateId, req.Cases)
+		liberr.ErrIsNil(ctx, err, "获取模板测试用例失败")
+		if len(req.Cases) > 0 {
+			found := make(map[string]bool, len(cases))
+			for _, testCase := range cases {
+				found[testCase.Name] = true
+			}
+			for _, name := range req.Cases {
+				if !found[name] {
+					liberr.ErrIsNil(ctx, gerror.Newf("测试用例 %s 不存在", name))
+				}
+			}
+		}
+		if len(cases) == 0 {
+			liberr.ErrIsNil(ctx, gerror.New("该模板没有测试用例"))
+		}
+
+		res = &api.TemplateTestsRunRes{
+			TemplateId: req.TemplateId,
+			Version:    version,
+			Cases:      make([]*api.TemplateTestsResult, 0, len(cases)),
+		}
+		accepted := make(map[uint64]map[string]string)
+		for _, testCase := range cases {
+			result, rendered, err := s.runCase(ctx, req.TemplateId, version, testCase)
+			liberr.ErrIsNil(ctx, err)
+			if rendered != nil {
+				res.Version = rendered.Version
+			}
+
+			switch result.Status {
+			case model.TestStatusPassed:
+				res.Passed++
+			case model.TestStatusFailed:
+				res.Failed++
+			default:
+				res.Errors++
+			}
+			if req.Accept && result.Status == model.TestStatusFailed {
+				accepted[testCase.Id] = acceptedExpected(testCase, flattenTree(rendered.Tree))
+				result.Accepted = true
+			}
+			res.Cases = append(res.Cases, result)
+		}
+		res.Total = len(res.Cases)
+
+		if len(accepted) > 0 {
+			err = dao.TemplateTestCases.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				for id, expected := range accepted {
+					encoded, err := encodeExpected(expected)
+					if err != nil {
+						return err
+					}
+					_, err = dao.TemplateTestCases.Ctx(ctx).WherePri(id).Data(do.TemplateTestCases{
+						Expected: encoded,
+					}).Update()
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			liberr.ErrIsNil(ctx, err, "更新测试用例期望结果失败")
+			res.Accepted = true
+		}
+	})
+	return
+}
+
+// runCase 渲染单个用例并比较结果，变量校验失败、超出渲染限制或文件渲染出错时用例状态为 error
+func (s *sTemplateTests) runCase(ctx context.Context, templateId int64, version string, testCase *entity.TemplateTestCases) (*api.TemplateTestsResult, *filesApi.TemplateFilesRenderFileTreeRes, error) {
+	result := &api.TemplateTestsResult{
+		Name:  testCase.Name,
+		Files: []*model.TemplateTestFileResult{},
+	}
+	variables, err := decodeVariables(testCase.Variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected, err := decodeExpected(testCase.Expected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rendered, err := service.TemplateFiles().RenderFileTree(ctx, &filesApi.TemplateFilesRenderFileTreeReq{
+		TemplateId: templateId,
+		Variables:  variables,
+		Version:    version,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if rendered.Error != nil {
+		result.Status = model.TestStatusError
+		result.Error = rendered.Error
+		result.ValidationErrors = rendered.ValidationErrors
+		return result, nil, nil
+	}
+
+	// 还没有期望结果的用例按完整文件树比较，便于通过 accept 模式生成
+	actual := flattenTree(rendered.Tree)
+	result.Files = compareFiles(expected, actual, testCase.FullTree == 1 || len(expected) == 0)
+	result.Status = model.TestStatusPassed
+	for _, file := range result.Files {
+		if file.Status != model.TestFileStatusPassed {
+			result.Status = model.TestStatusFailed
+			break
+		}
+	}
+	// 文件渲染出错时出错部分保留原始内容，比较结果不可信
+	if len(rendered.Diagnostics) > 0 {
+		result.Status = model.TestStatusError
+		result.Diagnostics = rendered.Diagnostics
+	}
+	return result, rendered, nil
+}
+
+// compareFiles 按路径比较期望结果与渲染结果，fullTree 为 true 时渲染出期望中没有的文件也视为失败
+func compareFiles(expected, actual map[string]string, fullTree bool) []*model.TemplateTestFileResult {
+	files := make([]*model.TemplateTestFileResult, 0, len(expected))
+	for filePath, want := range expected {
+		got, ok := actual[filePath]
+		switch {
+		case !ok:
+			files = append(files, &model.TemplateTestFileResult{
+				Path:   filePath,
+				Status: model.TestFileStatusMissing,
+				Diff:   unifiedDiff(filePath, want, ""),
+			})
+		case got != want:
+			files = append(files, &model.TemplateTestFileResult{
+				Path:   filePath,
+				Status: model.TestFileStatusFailed,
+				Diff:   unifiedDiff(filePath, want, got),
+			})
+		default:
+			files = append(files, &model.TemplateTestFileResult{
+				Path:   filePath,
+				Status: model.TestFileStatusPassed,
+			})
+		}
+	}
+	if fullTree {
+		for filePath, got := range actual {
+			if _, ok := expected[filePath]; ok {
+				continue
+			}
+			files = append(files, &model.TemplateTestFileResult{
+				Path:   filePath,
+				Status: model.TestFileStatusUnexpected,
+				Diff:   unifiedDiff(filePath, "", got),
+			})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// acceptedExpected 以渲染结果生成新的期望结果：校验完整文件树或还没有期望结果的用例记录全部文件，
+// 否则只更新原期望中列出的文件，没有被渲染出来的文件从期望中移除
+func acceptedExpected(testCase *entity.TemplateTestCases, actual map[string]string) map[string]string {
+	previous, _ := decodeExpected(testCase.Expected)
+	if testCase.FullTree == 1 || len(previous) == 0 {
+		return actual
+	}
+	expected := make(map[string]string, len(previous))
+	for filePath := range previous {
+		if content, ok := actual[filePath]; ok {
+			expected[filePath] = content
+		}
+	}
+	return expected
+}
+
+// flattenTree 将渲染后的文件树展开为路径到内容的映射，目录不参与比较
+func flattenTree(nodes []*filesApi.RenderFileInfo) map[string]string {
+	files := make(map[string]string)
+	var walk func(nodes []*filesApi.RenderFileInfo)
+	walk = func(nodes []*filesApi.RenderFileInfo) {
+		for _, node := range nodes {
+			if node.IsDirectory == 0 {
+				files[normalizePath(node.FilePath)] = node.FileContent
+			}
+			walk(node.Children)
+		}
+	}
+	walk(nodes)
+	return files
+}
+
+// unifiedDiff 生成期望内容到渲染结果的 unified diff
+func unifiedDiff(filePath, want, got string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(want),
+		B:        difflib.SplitLines(got),
+		FromFile: "expected/" + filePath,
+		ToFile:   "actual/" + filePath,
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
+
+// normalizePath 统一期望结果与渲染结果中的文件路径写法
+func normalizePath(filePath string) string {
+	filePath = strings.ReplaceAll(strings.TrimSpace(filePath), "\\", "/")
+	return strings.TrimPrefix(path.Clean("/"+filePath), "/")
+}
+
+// testCases 获取模板的测试用例，names 不为空时只返回指定名称的用例
+func (s *sTemplateTests) testCases(ctx context.Context, templateId int64, names []string) (cases []*entity.TemplateTestCases, err error) {
+	m := dao.TemplateTestCases.Ctx(ctx).Where(dao.TemplateTestCases.Columns().TemplateId, templateId)
+	if len(names) > 0 {
+		m = m.WhereIn(dao.TemplateTestCases.Columns().Name, names)
+	}
+	err = m.OrderAsc(dao.TemplateTestCases.Columns().Name).Scan(&cases)
+	return
+}
+
+// findTestCase 按名称获取模板的测试用例
+func (s *sTemplateTests) findTestCase(ctx context.Context, templateId int64, name string) (*entity.TemplateTestCases, error) {
+	var testCase *entity.TemplateTestCases
+	err := dao.TemplateTestCases.Ctx(ctx).
+		Where(dao.TemplateTestCases.Columns().TemplateId, templateId).
+		Where(dao.TemplateTestCases.Columns().Name, name).
+		Scan(&testCase)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板测试用例失败")
+	}
+	if testCase == nil {
+		return nil, gerror.Newf("测试用例 %s 不存在", name)
+	}
+	return testCase, nil
+}
+
+func toTestCaseInfo(testCase *entity.TemplateTestCases) (*model.TemplateTestCaseInfo, error) {
+	variables, err := decodeVariables(testCase.Variables)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := decodeExpected(testCase.Expected)
+	if err != nil {
+		return nil, err
+	}
+	info := &model.TemplateTestCaseInfo{
+		Id:          testCase.Id,
+		TemplateId:  testCase.TemplateId,
+		Name:        testCase.Name,
+		Description: testCase.Description,
+		Variables:   variables,
+		Expected:    expected,
+		FileCount:   len(expected),
+		FullTree:    testCase.FullTree == 1,
+	}
+	if testCase.CreatedAt != nil {
+		info.CreatedAt = testCase.CreatedAt.Format("Y-m-d H:i:s")
+	}
+	if testCase.UpdatedAt != nil {
+		info.UpdatedAt = testCase.UpdatedAt.Format("Y-m-d H:i:s")
+	}
+	return info, nil
+}
+
+func encodeVariables(variables map[string]interface{}) (string, error) {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return "", gerror.Wrap(err, "序列化测试用例变量失败")
+	}
+	return string(data), nil
+}
+
+func decodeVariables(data string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	if data == "" {
+		return variables, nil
+	}
+	if err := json.Unmarshal([]byte(data), &variables); err != nil {
+		return nil, gerror.Wrap(err, "解析测试用例变量失败")
+	}
+	return variables, nil
+}
+
+// encodeExpected 序列化期望结果，路径统一写法后不能为空或重复
+func encodeExpected(expected map[string]string) (string, error) {
+	normalized := make(map[string]string, len(expected))
+	for filePath, content := range expected {
+		key := normalizePath(filePath)
+		if key == "" || key == "." {
+			return "", gerror.New("期望结果中的文件路径不能为空")
+		}
+		if _, ok := normalized[key]; ok {
+			return "", gerror.Newf("期望结果中的文件路径重复: %s", key)
+		}
+		normalized[key] = content
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", gerror.Wrap(err, "序列化测试用例期望结果失败")
+	}
+	return string(data), nil
+}
+
+func decodeExpected(data string) (map[string]string, error) {
+	expected := map[string]string{}
+	if data == "" {
+		return expected, nil
+	}
+	if err := json.Unmarshal([]byte(data), &expected); err != nil {
+		return nil, gerror.Wrap(err, "解析测试用例期望结果失败")
+	}
+	return expected, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
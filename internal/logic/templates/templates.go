@@ -2,8 +2,9 @@ package templates
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 
 	api "github.com/ciclebyte/template_starter/api/v1/templates"
@@ -76,6 +77,13 @@ func (s sTemplates) Add(ctx context.Context, req *api.TemplatesAddReq) (err erro
 		if count > 0 {
 			return gerror.New("模板名称已存在")
 		}
+		if _, err = s.parseDelimiters(req.TypeConfig); err != nil {
+			return err
+		}
+		typeConfig := req.TypeConfig
+		if typeConfig == "" {
+			typeConfig = "{}"
+		}
 
 		// add
 		result, err := dao.Templates.Ctx(ctx).TX(tx).Insert(do.Templates{
@@ -84,6 +92,7 @@ func (s sTemplates) Add(ctx context.Context, req *api.TemplatesAddReq) (err erro
 			Introduction: req.Introduction, // 模板详细介绍，支持Markdown格式
 			CategoryId:   req.CategoryId,   // 所属分类ID
 			TemplateType: req.TemplateType, // 模板类型
+			TypeConfig:   typeConfig,       // 类型相关配置
 			IsFeatured:   req.IsFeatured,   // 是否推荐模板
 			Logo:         req.Logo,         // 模板logo图片URL
 			Icon:         req.Icon,         // 模板图标名称
@@ -116,6 +125,14 @@ func (s sTemplates) Edit(ctx context.Context, req *api.TemplatesEditReq) (err er
 		if count > 0 {
 			return gerror.New("模板名称已存在")
 		}
+		if _, err = s.parseDelimiters(req.TypeConfig); err != nil {
+			return err
+		}
+		// 未传类型配置时保留原值
+		var typeConfig interface{}
+		if req.TypeConfig != "" {
+			typeConfig = req.TypeConfig
+		}
 
 		// 编辑模板主表
 		_, err = dao.Templates.Ctx(ctx).TX(tx).WherePri(req.Id).Update(do.Templates{
@@ -125,6 +142,7 @@ func (s sTemplates) Edit(ctx context.Context, req *api.TemplatesEditReq) (err er
 			Introduction: req.Introduction, // 模板详细介绍，支持Markdown格式
 			CategoryId:   req.CategoryId,   // 所属分类ID
 			TemplateType: req.TemplateType, // 模板类型
+			TypeConfig:   typeConfig,       // 类型相关配置
 			IsFeatured:   req.IsFeatured,   // 是否推荐模板
 			Logo:         req.Logo,         // 模板logo图片URL
 			Icon:         req.Icon,         // 模板图标名称
@@ -149,17 +167,19 @@ func (s sTemplates) Edit(ctx context.Context, req *api.TemplatesEditReq) (err er
 	return
 }
 
+// Delete 将模板移入回收站，文件、标签等关联数据保留，恢复时一并恢复
 func (s sTemplates) Delete(ctx context.Context, id int64) (err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
-		_, err = dao.Templates.Ctx(ctx).WherePri(id).Delete()
+		err = s.moveToTrash(ctx, []int64{id})
 		liberr.ErrIsNil(ctx, err, "删除模板失败")
 	})
 	return
 }
 
+// BatchDelete 将模板批量移入回收站
 func (s sTemplates) BatchDelete(ctx context.Context, ids []int64) (err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
-		_, err = dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Id+" in(?)", ids).Delete()
+		err = s.moveToTrash(ctx, ids)
 		liberr.ErrIsNil(ctx, err, "批量删除模板失败")
 	})
 	return
@@ -185,18 +205,72 @@ func (s sTemplates) GetById(ctx context.Context, id int64) (res *model.Templates
 	return
 }
 
-func (s sTemplates) GetVariables(ctx context.Context, templateId int64) (res *api.TemplatesVariablesRes, err error) {
+// GetDelimiters 获取模板的定界符配置，未配置时返回默认的 {{ }}
+func (s sTemplates) GetDelimiters(ctx context.Context, templateId int64) (res *model.TemplateDelimiters, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		typeConfig, err := dao.Templates.Ctx(ctx).WherePri(templateId).Value(dao.Templates.Columns().TypeConfig)
+		liberr.ErrIsNil(ctx, err, "获取模板配置失败")
+		res, err = s.parseDelimiters(typeConfig.String())
+		if err != nil {
+			// 历史数据中的非法配置不影响渲染，按默认定界符处理
+			g.Log().Warning(ctx, "模板", templateId, "定界符配置无效，使用默认定界符:", err)
+			res = &model.TemplateDelimiters{Left: model.DefaultLeftDelim, Right: model.DefaultRightDelim}
+		}
+	})
+	return
+}
+
+// parseDelimiters 从类型配置中解析定界符，配置非法时返回错误
+func (s sTemplates) parseDelimiters(typeConfig string) (*model.TemplateDelimiters, error) {
+	delims := &model.TemplateDelimiters{Left: model.DefaultLeftDelim, Right: model.DefaultRightDelim}
+	if strings.TrimSpace(typeConfig) == "" {
+		return delims, nil
+	}
+	var config model.TemplateTypeConfig
+	if err := json.Unmarshal([]byte(typeConfig), &config); err != nil {
+		return nil, gerror.Wrap(err, "类型配置不是合法的JSON")
+	}
+	if config.Delimiters == nil {
+		return delims, nil
+	}
+	left, right := config.Delimiters.Left, config.Delimiters.Right
+	if left == "" || right == "" {
+		return nil, gerror.New("定界符的左右两侧都不能为空")
+	}
+	if strings.ContainsAny(left+right, " \t\r\n") {
+		return nil, gerror.New("定界符不能包含空白字符")
+	}
+	if left == right {
+		return nil, gerror.New("左右定界符不能相同")
+	}
+	return &model.TemplateDelimiters{Left: left, Right: right}, nil
+}
+
+func (s sTemplates) GetVariables(ctx context.Context, templateId int64, version string) (res *api.TemplatesVariablesRes, err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
 		res = &api.TemplatesVariablesRes{}
 
-		// 1. 自定义变量功能已移除，返回空数组
-		res.CustomVariables = []interface{}{}
+		// 1. 指定发布版本中定义的变量，未发布过的模板使用当前的暴露字段定义
+		_, snapshot, err := service.TemplateReleases().LoadSnapshot(ctx, templateId, version)
+		liberr.ErrIsNil(ctx, err)
+		if snapshot != nil {
+			res.CustomVariables = snapshot.Variables
+		} else {
+			res.CustomVariables, err = service.TemplateExpose().GetVariableDefs(ctx, templateId)
+			liberr.ErrIsNil(ctx, err, "获取模板变量定义失败")
+		}
+		if res.CustomVariables == nil {
+			res.CustomVariables = []*model.TemplateVariableDef{}
+		}
 
 		// 2. 获取模板文件树
 		var fileTree []*model.TemplateFilesInfo
 		err = dao.TemplateFiles.Ctx(ctx).Where("template_id = ?", templateId).Scan(&fileTree)
 		liberr.ErrIsNil(ctx, err, "获取模板文件失败")
 
+		delims, err := s.GetDelimiters(ctx, templateId)
+		liberr.ErrIsNil(ctx, err)
+
 		// 3. 解析模板内容中的内置变量和函数
 		builtinVars := make(map[string]*api.BuiltinVariableInfo)
 		templateFuncs := make(map[string]*api.TemplateFunctionInfo)
@@ -261,7 +335,7 @@ func (s sTemplates) GetVariables(ctx context.Context, templateId int64) (res *ap
 
 			// 解析内置变量 {{.变量名}}
 			for varName, def := range builtinVarDefs {
-				if s.containsVariable(content, varName) {
+				if s.containsVariable(content, varName, delims) {
 					if builtinVars[varName] == nil {
 						builtinVars[varName] = &api.BuiltinVariableInfo{
 							Name:        varName,
@@ -280,7 +354,7 @@ func (s sTemplates) GetVariables(ctx context.Context, templateId int64) (res *ap
 
 			// 解析模板函数 {{函数名}}
 			for funcName, def := range funcDefs {
-				if s.containsFunction(content, funcName) {
+				if s.containsFunction(content, funcName, delims) {
 					if templateFuncs[funcName] == nil {
 						templateFuncs[funcName] = &api.TemplateFunctionInfo{
 							Name:        funcName,
@@ -308,7 +382,7 @@ func (s sTemplates) GetVariables(ctx context.Context, templateId int64) (res *ap
 
 		// 4. 统计信息
 		res.Statistics = &api.VariableStatistics{
-			TotalCustomVariables:  0, // 自定义变量功能已移除
+			TotalCustomVariables:  len(res.CustomVariables),
 			TotalBuiltinVariables: len(builtinVars),
 			TotalFunctions:        len(templateFuncs),
 			TotalFiles:            len(fileSet),
@@ -318,16 +392,16 @@ func (s sTemplates) GetVariables(ctx context.Context, templateId int64) (res *ap
 }
 
 // 辅助方法：检查内容是否包含变量
-func (s sTemplates) containsVariable(content, varName string) bool {
+func (s sTemplates) containsVariable(content, varName string, delims *model.TemplateDelimiters) bool {
 	// 检查 {{.变量名}} 格式
-	pattern := fmt.Sprintf("{{.%s}}", varName)
+	pattern := fmt.Sprintf("%s.%s%s", delims.Left, varName, delims.Right)
 	return strings.Contains(content, pattern)
 }
 
 // 辅助方法：检查内容是否包含函数
-func (s sTemplates) containsFunction(content, funcName string) bool {
+func (s sTemplates) containsFunction(content, funcName string, delims *model.TemplateDelimiters) bool {
 	// 检查 {{函数名}} 格式
-	pattern := fmt.Sprintf("{{%s", funcName)
+	pattern := fmt.Sprintf("%s%s", delims.Left, funcName)
 	return strings.Contains(content, pattern)
 }
 
@@ -356,6 +430,9 @@ func (s sTemplates) AnalyzeVariables(ctx context.Context, templateId int64) (res
 		err = dao.TemplateFiles.Ctx(ctx).Where(dao.TemplateFiles.Columns().TemplateId, templateId).Scan(&files)
 		liberr.ErrIsNil(ctx, err, "获取模板文件失败")
 
+		delims, err := s.GetDelimiters(ctx, templateId)
+		liberr.ErrIsNil(ctx, err)
+
 		// 2. 获取当前变量定义
 		var templateExposeInfo *template_expose_api.TemplateExposeInfo
 		err = dao.TemplateExposeFields.Ctx(ctx).Where(dao.TemplateExposeFields.Columns().TemplateId, templateId).Scan(&templateExposeInfo)
@@ -375,16 +452,15 @@ func (s sTemplates) AnalyzeVariables(ctx context.Context, templateId int64) (res
 
 		// 3. 分析模板文件中的变量使用
 		detectedVars := make(map[string]*api.DetectedVariable)
+		hints := make(map[string]map[string]bool)
 		res.AnalyzedFileCount = len(files)
+		funcs := service.TemplateFiles().TemplateFuncMap()
 
 		g.Log().Info(ctx, "开始分析模板文件，共", len(files), "个文件")
 		
 		for _, file := range files {
-			g.Log().Debug(ctx, "处理文件:", file.FileName, "IsDirectory:", file.IsDirectory, "FilePath:", file.FilePath)
-			
 			// 跳过目录
 			if file.IsDirectory == 1 {
-				g.Log().Debug(ctx, "跳过目录:", file.FileName)
 				continue
 			}
 			
@@ -395,54 +471,67 @@ func (s sTemplates) AnalyzeVariables(ctx context.Context, templateId int64) (res
 				continue
 			}
 			
-			content := fileContent.String()
-			g.Log().Debug(ctx, "文件内容长度:", len(content), "文件:", file.FileName)
-			if len(content) > 0 {
-				g.Log().Debug(ctx, "文件内容预览:", file.FileName, "=>", content[:min(len(content), 100)])
+			// 兼容旧版 {{/var}} 写法，与渲染时保持一致
+			content := strings.ReplaceAll(fileContent.String(), delims.Left+"/", delims.Left+".")
+			if !strings.Contains(content, delims.Left) {
+				continue
 			}
-			
-			if content == "" {
-				g.Log().Debug(ctx, "文件内容为空:", file.FileName)
+
+			// 使用与渲染相同的函数映射解析语法树
+			analyzer, err := analyzeTemplateContent(file, content, delims, funcs)
+			if err != nil {
+				g.Log().Warning(ctx, "解析模板失败:", file.FilePath, err)
+				res.FailedFiles = append(res.FailedFiles, file.FilePath)
 				continue
 			}
+			g.Log().Debug(ctx, "文件", file.FileName, "检测到", len(analyzer.usages), "处变量引用")
 
-			// 使用正则表达式提取所有变量
-			variables := s.extractVariablesFromContent(content)
-			g.Log().Info(ctx, "文件", file.FileName, "提取到", len(variables), "个变量")
-			
-			// 调试：输出检测结果
-			if len(variables) > 0 {
-				g.Log().Debug(ctx, "文件", file.FileName, "检测到变量:", len(variables))
-				for _, v := range variables {
-					g.Log().Debug(ctx, "  变量:", v.Name, "类型:", v.Type, "上下文:", v.Contexts)
+			for path, pathHints := range analyzer.hints {
+				if hints[path] == nil {
+					hints[path] = make(map[string]bool)
+				}
+				for typ := range pathHints {
+					hints[path][typ] = true
 				}
 			}
-			
-			for _, varInfo := range variables {
-				if detectedVars[varInfo.Name] == nil {
-					detectedVars[varInfo.Name] = &api.DetectedVariable{
-						Name:        varInfo.Name,
-						Type:        varInfo.Type,
-						Files:       []string{},
-						Contexts:    []string{},
-						Suggestions: varInfo.Suggestions,
+
+			for _, usage := range analyzer.usages {
+				name := rootName(usage.Path)
+				if detectedVars[name] == nil {
+					detectedVars[name] = &api.DetectedVariable{
+						Name:     name,
+						Files:    []string{},
+						Contexts: []string{},
+						Paths:    []string{},
+						Usages:   []*api.VariableUsage{},
 					}
 				}
+				detectedVar := detectedVars[name]
+				detectedVar.Usages = append(detectedVar.Usages, usage)
+				detectedVar.InConditional = detectedVar.InConditional || usage.InConditional
+				detectedVar.InLoop = detectedVar.InLoop || usage.InLoop
 				
-				// 添加文件信息
-				if !s.containsString(detectedVars[varInfo.Name].Files, file.FileName) {
-					detectedVars[varInfo.Name].Files = append(detectedVars[varInfo.Name].Files, file.FileName)
+				// 添加文件、路径和上下文信息
+				if !s.containsString(detectedVar.Files, file.FileName) {
+					detectedVar.Files = append(detectedVar.Files, file.FileName)
 				}
-				
-				// 添加上下文信息
-				for _, context := range varInfo.Contexts {
-					if !s.containsString(detectedVars[varInfo.Name].Contexts, context) {
-						detectedVars[varInfo.Name].Contexts = append(detectedVars[varInfo.Name].Contexts, context)
-					}
+				if usage.Path != name && !s.containsString(detectedVar.Paths, usage.Path) {
+					detectedVar.Paths = append(detectedVar.Paths, usage.Path)
+				}
+				if !s.containsString(detectedVar.Contexts, usage.Context) {
+					detectedVar.Contexts = append(detectedVar.Contexts, usage.Context)
 				}
 			}
 		}
 
+		// 根据类型线索确定变量类型
+		for name, detectedVar := range detectedVars {
+			detectedVar.Type = resolveVariableType(hints[name])
+			detectedVar.Suggestions = variableTypeSuggestion(detectedVar.Type, detectedVar.Paths)
+			sort.Strings(detectedVar.Paths)
+			sortUsages(detectedVar.Usages)
+		}
+
 		// 4. 分类变量
 		for _, detectedVar := range detectedVars {
 			res.DetectedVariables = append(res.DetectedVariables, detectedVar)
@@ -485,115 +574,6 @@ func min(a, b int) int {
 	return b
 }
 
-// 从内容中提取变量信息
-func (s sTemplates) extractVariablesFromContent(content string) []*api.DetectedVariable {
-	var variables []*api.DetectedVariable
-	
-	// 先检查内容中是否包含模板语法
-	if !strings.Contains(content, "{{") {
-		fmt.Printf("内容中不包含模板语法: %s\n", content[:min(len(content), 50)])
-		return variables
-	}
-	
-	fmt.Printf("开始分析内容，长度: %d\n", len(content))
-	
-	// 正则表达式匹配不同的模板语法
-	patterns := map[string]string{
-		"simple":    `\{\{\.([a-zA-Z_][a-zA-Z0-9_]*)\}\}`,               // {{.varName}}
-		"range":     `\{\{\s*range\s+\.([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`, // {{range .items}}
-		"if":        `\{\{\s*if\s+\.([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`,    // {{if .condition}}
-		"with":      `\{\{\s*with\s+\.([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`,  // {{with .value}}
-		"index":     `\{\{\.([a-zA-Z_][a-zA-Z0-9_]*)\[`,                 // {{.array[index]}}
-		"nested":    `\{\{\.([a-zA-Z_][a-zA-Z0-9_]*)\.`,                 // {{.object.field}}
-		"function":  `\{\{\s*\w+\s+\.([a-zA-Z_][a-zA-Z0-9_]*)`,          // {{len .items}}
-	}
-
-	varMap := make(map[string]*api.DetectedVariable)
-
-	for patternType, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllStringSubmatch(content, -1)
-		
-		// 调试：输出匹配结果
-		if len(matches) > 0 {
-			fmt.Printf("模式 %s 匹配到 %d 个结果\n", patternType, len(matches))
-		}
-		
-		for _, match := range matches {
-			if len(match) > 1 {
-				varName := match[1]
-				context := strings.TrimSpace(match[0])
-				
-				fmt.Printf("  发现变量: %s, 上下文: %s\n", varName, context)
-				
-				if varMap[varName] == nil {
-					varMap[varName] = &api.DetectedVariable{
-						Name:        varName,
-						Type:        s.inferVariableType(patternType, context),
-						Files:       []string{},
-						Contexts:    []string{},
-						Suggestions: s.generateTypeSuggestion(patternType, context),
-					}
-				}
-				
-				// 添加上下文
-				if !s.containsString(varMap[varName].Contexts, context) {
-					varMap[varName].Contexts = append(varMap[varName].Contexts, context)
-				}
-			}
-		}
-	}
-
-	// 转换为切片
-	for _, v := range varMap {
-		variables = append(variables, v)
-	}
-	
-	return variables
-}
-
-// 推断变量类型
-func (s sTemplates) inferVariableType(patternType, context string) string {
-	switch patternType {
-	case "range":
-		return "array" // range 通常用于数组或切片
-	case "if":
-		return "boolean" // if 通常用于布尔值
-	case "index":
-		return "array" // 索引访问通常是数组
-	case "nested":
-		return "object" // 嵌套访问通常是对象
-	case "function":
-		if strings.Contains(context, "len ") {
-			return "array" // len 函数通常用于数组
-		}
-		return "unknown"
-	default:
-		return "string" // 默认为字符串
-	}
-}
-
-// 生成类型建议
-func (s sTemplates) generateTypeSuggestion(patternType, context string) string {
-	switch patternType {
-	case "range":
-		return "建议使用 array 或 object_arr 类型，用于循环遍历"
-	case "if":
-		return "建议使用 boolean 类型，用于条件判断"
-	case "index":
-		return "建议使用 array 类型，支持索引访问"
-	case "nested":
-		return "建议使用 object 类型，支持嵌套属性访问"
-	case "function":
-		if strings.Contains(context, "len ") {
-			return "建议使用 array 类型，用于长度计算"
-		}
-		return "根据使用上下文确定合适的类型"
-	default:
-		return "建议使用 string 类型，或根据实际用途选择其他类型"
-	}
-}
-
 // Fork 复制模板
 func (s sTemplates) Fork(ctx context.Context, req *api.TemplatesForkReq) (res *api.TemplatesForkRes, err error) {
 	err = g.Try(ctx, func(ctx context.Context) {
@@ -683,7 +663,13 @@ func (s sTemplates) Fork(ctx context.Context, req *api.TemplatesForkReq) (res *a
 				return gerror.Wrap(err, "复制模板文件失败")
 			}
 			
-			// 4.5 设置返回结果
+			// 4.5 记录上游来源，之后可拉取上游的修改
+			err = service.TemplateForks().Track(ctx, newTemplateId, sourceId)
+			if err != nil {
+				return gerror.Wrap(err, "记录上游来源失败")
+			}
+			
+			// 4.6 设置返回结果
 			res = &api.TemplatesForkRes{
 				TemplateId: newTemplateId,
 				Name:       req.Name,
@@ -725,6 +711,7 @@ func (s sTemplates) copyTemplateFiles(ctx context.Context, sourceTemplateId, new
 			Sort:              file["sort"].Int(),
 			ParentId:          newParentId,
 			GenerateCondition: generateCondition,
+			RepeatConfig:      file["repeat_config"].String(),
 		}
 		
 		newFileId, err := dao.TemplateFiles.Ctx(ctx).Data(newFileData).InsertAndGetId()
@@ -0,0 +1,208 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	api "github.com/ciclebyte/template_starter/api/v1/templates"
+	dao "github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	do "github.com/ciclebyte/template_starter/internal/model/do"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	liberr "github.com/ciclebyte/template_starter/library/liberr"
+)
+
+// defaultTrashRetentionDays 未配置 trash.retentionDays 时回收站的保留天数
+const defaultTrashRetentionDays = 30
+
+// Trash 获取回收站中的模板列表，按删除时间从近到远排列
+func (s sTemplates) Trash(ctx context.Context, req *api.TemplatesTrashReq) (total interface{}, trashList []*model.TemplateTrashInfo, retentionDays int, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		retentionDays = s.trashRetentionDays(ctx)
+		columns := dao.Templates.Columns()
+		m := dao.Templates.Ctx(ctx).Unscoped().WhereNotNull(columns.DeletedAt)
+		if req.Name != "" {
+			m = m.Where(fmt.Sprintf("%s like ?", columns.Name), "%"+req.Name+"%")
+		}
+
+		total, err = m.Count()
+		liberr.ErrIsNil(ctx, err, "获取回收站模板失败")
+		var templates []*entity.Templates
+		err = m.Page(req.PageNum, req.PageSize).OrderDesc(columns.DeletedAt).Scan(&templates)
+		liberr.ErrIsNil(ctx, err, "获取回收站模板失败")
+
+		trashList = make([]*model.TemplateTrashInfo, 0, len(templates))
+		for _, template := range templates {
+			info := &model.TemplateTrashInfo{
+				Id:           template.Id,
+				Name:         template.Name,
+				Description:  template.Description,
+				TemplateType: template.TemplateType,
+				DeletedBy:    template.DeletedBy,
+			}
+			if template.DeletedAt != nil {
+				info.DeletedAt = template.DeletedAt.Format("Y-m-d H:i:s")
+				if retentionDays > 0 {
+					info.PurgeAt = template.DeletedAt.AddDate(0, 0, retentionDays).Format("Y-m-d H:i:s")
+				}
+			}
+			trashList = append(trashList, info)
+		}
+	})
+	return
+}
+
+// Restore 将模板从回收站恢复，关联数据在移入回收站时保留，随模板一起恢复
+func (s sTemplates) Restore(ctx context.Context, ids []int64) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		err = dao.Templates.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			trashed, err := s.trashedTemplates(ctx, ids)
+			if err != nil {
+				return err
+			}
+			// 移入回收站后可能新建了同名模板
+			for _, template := range trashed {
+				count, err := dao.Templates.Ctx(ctx).Where(dao.Templates.Columns().Name, template.Name).Count()
+				if err != nil {
+					return gerror.Wrap(err, "模板名称判重失败")
+				}
+				if count > 0 {
+					return gerror.Newf("已存在同名模板 %s，请先修改该模板的名称再恢复", template.Name)
+				}
+			}
+			_, err = dao.Templates.Ctx(ctx).Unscoped().
+				WhereIn(dao.Templates.Columns().Id, ids).
+				Data(g.Map{
+					dao.Templates.Columns().DeletedAt: nil,
+					dao.Templates.Columns().DeletedBy: "",
+				}).
+				Update()
+			return err
+		})
+		liberr.ErrIsNil(ctx, err, "恢复模板失败")
+	})
+	return
+}
+
+// Purge 彻底删除回收站中的模板及其全部关联数据，不可恢复
+func (s sTemplates) Purge(ctx context.Context, ids []int64) (err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		err = dao.Templates.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			if _, err := s.trashedTemplates(ctx, ids); err != nil {
+				return err
+			}
+			return s.purgeTemplates(ctx, ids)
+		})
+		liberr.ErrIsNil(ctx, err, "彻底删除模板失败")
+	})
+	return
+}
+
+// PurgeExpired 彻底删除在回收站中超过保留期限的模板，由定时任务调用
+func (s sTemplates) PurgeExpired(ctx context.Context) (purged int, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		retentionDays := s.trashRetentionDays(ctx)
+		if retentionDays <= 0 {
+			return
+		}
+		columns := dao.Templates.Columns()
+		values, err := dao.Templates.Ctx(ctx).Unscoped().
+			WhereNotNull(columns.DeletedAt).
+			WhereLT(columns.DeletedAt, gtime.Now().AddDate(0, 0, -retentionDays)).
+			Array(columns.Id)
+		liberr.ErrIsNil(ctx, err, "获取过期的回收站模板失败")
+		if len(values) == 0 {
+			return
+		}
+
+		ids := make([]int64, 0, len(values))
+		for _, value := range values {
+			ids = append(ids, value.Int64())
+		}
+		err = dao.Templates.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return s.purgeTemplates(ctx, ids)
+		})
+		liberr.ErrIsNil(ctx, err, "清理回收站模板失败")
+		purged = len(ids)
+	})
+	return
+}
+
+// moveToTrash 将模板移入回收站，已在回收站中的模板保持原删除时间
+func (s sTemplates) moveToTrash(ctx context.Context, ids []int64) error {
+	data := do.Templates{DeletedAt: gtime.Now()}
+	if r := g.RequestFromCtx(ctx); r != nil {
+		data.DeletedBy = r.GetCtxVar("username").String()
+	}
+	_, err := dao.Templates.Ctx(ctx).
+		WhereIn(dao.Templates.Columns().Id, ids).
+		WhereNull(dao.Templates.Columns().DeletedAt).
+		Data(data).
+		Update()
+	return err
+}
+
+// trashedTemplates 获取回收站中的模板，任一模板不在回收站中时返回错误
+func (s sTemplates) trashedTemplates(ctx context.Context, ids []int64) ([]*entity.Templates, error) {
+	if len(ids) == 0 {
+		return nil, gerror.New("id不能为空")
+	}
+	var templates []*entity.Templates
+	err := dao.Templates.Ctx(ctx).Unscoped().
+		WhereIn(dao.Templates.Columns().Id, ids).
+		WhereNotNull(dao.Templates.Columns().DeletedAt).
+		Scan(&templates)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取回收站模板失败")
+	}
+	found := make(map[int64]bool, len(templates))
+	for _, template := range templates {
+		found[template.Id] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, gerror.Newf("模板ID %d 不在回收站中", id)
+		}
+	}
+	return templates, nil
+}
+
+// purgeTemplates 删除模板及其全部关联数据，需在事务中调用
+func (s sTemplates) purgeTemplates(ctx context.Context, ids []int64) error {
+	dependents := []struct {
+		name  string
+		model *gdb.Model
+	}{
+		{"文件修订历史", dao.TemplateFileRevisions.Ctx(ctx)},
+		{"文件", dao.TemplateFiles.Ctx(ctx)},
+		{"标签关联", dao.TemplateTags.Ctx(ctx)},
+		{"语言关联", dao.TemplateLanguages.Ctx(ctx)},
+		{"暴露字段", dao.TemplateExposeFields.Ctx(ctx)},
+		{"预设变量订阅", dao.TemplateVariablePresets.Ctx(ctx)},
+		{"变量预设关联", dao.TemplateVarPresets.Ctx(ctx)},
+		{"发布版本", dao.TemplateReleases.Ctx(ctx)},
+		{"测试用例", dao.TemplateTestCases.Ctx(ctx)},
+		{"git来源", dao.TemplateGitSources.Ctx(ctx)},
+		{"上游来源", dao.TemplateForks.Ctx(ctx)},
+	}
+	for _, dependent := range dependents {
+		if _, err := dependent.model.WhereIn("template_id", ids).Delete(); err != nil {
+			return gerror.Wrapf(err, "删除模板%s失败", dependent.name)
+		}
+	}
+	_, err := dao.Templates.Ctx(ctx).Unscoped().WhereIn(dao.Templates.Columns().Id, ids).Delete()
+	if err != nil {
+		return gerror.Wrap(err, "删除模板失败")
+	}
+	return nil
+}
+
+// trashRetentionDays 回收站的保留天数，0 表示不自动清理
+func (s sTemplates) trashRetentionDays(ctx context.Context) int {
+	return g.Cfg().MustGet(ctx, "trash.retentionDays", defaultTrashRetentionDays).Int()
+}
@@ -0,0 +1,302 @@
+package templates
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	api "github.com/ciclebyte/template_starter/api/v1/templates"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+// 推断出的变量类型，按优先级从高到低排列
+var variableTypePriority = []string{"array", "object", "boolean", "number", "string"}
+
+// 比较、运算类函数，参数与数字字面量一起出现时推断为 number
+var numericFuncs = map[string]bool{
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+	"add": true, "sub": true, "mul": true, "div": true, "mod": true, "max": true, "min": true,
+}
+
+// analyzeScope 遍历语法树时的作用域
+type analyzeScope struct {
+	dot    string            // 当前 . 对应的字段路径，"" 表示根变量，unknownPath 表示无法追踪
+	vars   map[string]string // 模板变量（$x）对应的字段路径
+	cond   bool              // 是否处于 if/with 块中
+	loop   bool              // 是否处于 range 块中
+	action string            // 当前动作的源码，用作使用上下文
+}
+
+const unknownPath = "?"
+
+// child 进入新的块作用域，模板变量在块结束后失效
+func (sc analyzeScope) child() analyzeScope {
+	vars := make(map[string]string, len(sc.vars))
+	for k, v := range sc.vars {
+		vars[k] = v
+	}
+	sc.vars = vars
+	return sc
+}
+
+// variableAnalyzer 基于 text/template/parse 语法树分析模板变量
+type variableAnalyzer struct {
+	content string
+	delims  *model.TemplateDelimiters
+	file    *model.TemplateFilesInfo
+	usages  []*api.VariableUsage
+	hints   map[string]map[string]bool // 字段路径 -> 推断类型集合
+}
+
+// analyzeTemplateContent 解析模板内容并返回其中的变量引用和类型线索
+func analyzeTemplateContent(file *model.TemplateFilesInfo, content string, delims *model.TemplateDelimiters, funcs template.FuncMap) (*variableAnalyzer, error) {
+	tmpl, err := template.New(file.FilePath).Delims(delims.Left, delims.Right).Funcs(funcs).Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	a := &variableAnalyzer{content: content, delims: delims, file: file, hints: make(map[string]map[string]bool)}
+	// 文件本身和其中 {{define}} 定义的模板都以根变量作为 .
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		a.walk(t.Tree.Root, analyzeScope{vars: map[string]string{"$": ""}})
+	}
+	return a, nil
+}
+
+func (a *variableAnalyzer) walk(node parse.Node, sc analyzeScope) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			a.walk(child, sc)
+		}
+	case *parse.ActionNode:
+		sc.action = n.String()
+		target := a.pipe(n.Pipe, sc, "")
+		// {{$x := .Foo}} 记录模板变量，作用域内共享同一个变量表
+		for _, decl := range n.Pipe.Decl {
+			sc.vars[decl.Ident[0]] = target
+		}
+	case *parse.IfNode:
+		sc.action = a.blockHeader("if", n.Pipe)
+		a.pipe(n.Pipe, sc, "boolean")
+		inner := sc.child()
+		inner.cond = true
+		a.walk(n.List, inner)
+		elseScope := sc.child()
+		elseScope.cond = true
+		a.walk(n.ElseList, elseScope)
+	case *parse.WithNode:
+		sc.action = a.blockHeader("with", n.Pipe)
+		target := a.pipe(n.Pipe, sc, "object")
+		inner := sc.child()
+		inner.cond = true
+		inner.dot = target
+		for _, decl := range n.Pipe.Decl {
+			inner.vars[decl.Ident[0]] = target
+		}
+		a.walk(n.List, inner)
+		elseScope := sc.child()
+		elseScope.cond = true
+		a.walk(n.ElseList, elseScope)
+	case *parse.RangeNode:
+		sc.action = a.blockHeader("range", n.Pipe)
+		target := a.pipe(n.Pipe, sc, "array")
+		inner := sc.child()
+		inner.loop = true
+		inner.dot = unknownPath
+		if target != unknownPath {
+			inner.dot = target + "[]"
+		}
+		// {{range $i, $v := .Items}} 中最后一个变量为元素
+		if decl := n.Pipe.Decl; len(decl) > 0 {
+			inner.vars[decl[len(decl)-1].Ident[0]] = inner.dot
+		}
+		a.walk(n.List, inner)
+		elseScope := sc.child()
+		elseScope.cond = true
+		a.walk(n.ElseList, elseScope)
+	case *parse.TemplateNode:
+		sc.action = n.String()
+		a.pipe(n.Pipe, sc, "")
+	}
+}
+
+// blockHeader 返回块动作的开头部分，如 {{if .Enabled}}
+func (a *variableAnalyzer) blockHeader(keyword string, pipe *parse.PipeNode) string {
+	return a.delims.Left + keyword + " " + pipe.String() + a.delims.Right
+}
+
+// pipe 分析管道中的变量引用，hint 为管道整体的类型线索；
+// 管道只有一个字段引用时返回该字段路径，否则返回 unknownPath
+func (a *variableAnalyzer) pipe(pipe *parse.PipeNode, sc analyzeScope, hint string) string {
+	if pipe == nil {
+		return unknownPath
+	}
+	result := unknownPath
+	for _, cmd := range pipe.Cmds {
+		paths := a.command(cmd, sc)
+		if len(pipe.Cmds) == 1 && len(cmd.Args) == 1 && len(paths) == 1 {
+			result = paths[0]
+		}
+	}
+	if result != unknownPath && hint != "" {
+		a.hint(result, hint)
+	}
+	return result
+}
+
+// command 分析单个命令，返回其中直接引用的字段路径
+func (a *variableAnalyzer) command(cmd *parse.CommandNode, sc analyzeScope) []string {
+	var paths []string
+	var funcName string
+	hasNumber := false
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		funcName = ident.Ident
+	}
+	for _, arg := range cmd.Args {
+		if _, ok := arg.(*parse.NumberNode); ok {
+			hasNumber = true
+		}
+	}
+	for i, arg := range cmd.Args {
+		path := a.arg(arg, sc)
+		if path == unknownPath {
+			continue
+		}
+		paths = append(paths, path)
+		switch {
+		case funcName == "len" || (funcName == "index" && i == 1):
+			a.hint(path, "array")
+		case funcName == "not" || funcName == "and" || funcName == "or":
+			a.hint(path, "boolean")
+		case numericFuncs[funcName] && hasNumber:
+			a.hint(path, "number")
+		}
+	}
+	return paths
+}
+
+// arg 分析命令参数并记录变量引用，返回参数对应的字段路径
+func (a *variableAnalyzer) arg(node parse.Node, sc analyzeScope) string {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		return a.use(join(sc.dot, n.Ident), n.Position(), sc)
+	case *parse.DotNode:
+		if sc.dot == "" || sc.dot == unknownPath {
+			return unknownPath
+		}
+		return a.use(sc.dot, n.Position(), sc)
+	case *parse.VariableNode:
+		base, ok := sc.vars[n.Ident[0]]
+		if !ok || base == unknownPath || (base == "" && len(n.Ident) == 1) {
+			return unknownPath
+		}
+		return a.use(join(base, n.Ident[1:]), n.Position(), sc)
+	case *parse.ChainNode:
+		if pipe, ok := n.Node.(*parse.PipeNode); ok {
+			base := a.pipe(pipe, sc, "")
+			if base != unknownPath {
+				return a.use(join(base, n.Field), n.Position(), sc)
+			}
+		}
+		return unknownPath
+	case *parse.PipeNode:
+		return a.pipe(n, sc, "")
+	}
+	return unknownPath
+}
+
+// use 记录一次变量引用，并为路径上的每一级父路径添加 object/array 线索
+func (a *variableAnalyzer) use(path string, pos parse.Pos, sc analyzeScope) string {
+	if path == "" || strings.HasPrefix(path, unknownPath) {
+		return unknownPath
+	}
+	a.usages = append(a.usages, &api.VariableUsage{
+		File:          a.file.FilePath,
+		Line:          1 + strings.Count(a.content[:min(int(pos), len(a.content))], "\n"),
+		Path:          path,
+		Context:       sc.action,
+		InConditional: sc.cond,
+		InLoop:        sc.loop,
+	})
+	for i := 1; i < len(path); i++ {
+		switch {
+		case path[i] == '.' && path[i-1] != ']':
+			a.hint(path[:i], "object")
+		case strings.HasPrefix(path[i:], "[]"):
+			a.hint(path[:i], "array")
+		}
+	}
+	return path
+}
+
+func (a *variableAnalyzer) hint(path, typ string) {
+	if a.hints[path] == nil {
+		a.hints[path] = make(map[string]bool)
+	}
+	a.hints[path][typ] = true
+}
+
+// join 拼接字段路径
+func join(base string, fields []string) string {
+	if base == unknownPath {
+		return unknownPath
+	}
+	if len(fields) == 0 {
+		return base
+	}
+	if base == "" {
+		return strings.Join(fields, ".")
+	}
+	return base + "." + strings.Join(fields, ".")
+}
+
+// rootName 返回字段路径的顶层变量名
+func rootName(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// resolveVariableType 按优先级从类型线索中选出变量类型，没有线索时为 string
+func resolveVariableType(hints map[string]bool) string {
+	for _, typ := range variableTypePriority {
+		if hints[typ] {
+			return typ
+		}
+	}
+	return "string"
+}
+
+// variableTypeSuggestion 生成类型建议
+func variableTypeSuggestion(typ string, paths []string) string {
+	switch typ {
+	case "array":
+		return "建议使用 array 或 object_arr 类型，用于循环遍历或长度计算"
+	case "object":
+		return "建议使用 object 类型，包含字段: " + strings.Join(paths, ", ")
+	case "boolean":
+		return "建议使用 boolean 类型，用于条件判断"
+	case "number":
+		return "建议使用 number 类型，用于数值比较或计算"
+	default:
+		return "建议使用 string 类型，或根据实际用途选择其他类型"
+	}
+}
+
+// sortUsages 按文件和行号排序引用
+func sortUsages(usages []*api.VariableUsage) {
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].File != usages[j].File {
+			return usages[i].File < usages[j].File
+		}
+		return usages[i].Line < usages[j].Line
+	})
+}
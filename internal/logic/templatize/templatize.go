@@ -0,0 +1,276 @@
+package templatize
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/pmezard/go-difflib/difflib"
+
+	filesApi "github.com/ciclebyte/template_starter/api/v1/template_files"
+	api "github.com/ciclebyte/template_starter/api/v1/templatize"
+	"github.com/ciclebyte/template_starter/internal/dao"
+	model "github.com/ciclebyte/template_starter/internal/model"
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+	"github.com/ciclebyte/template_starter/internal/service"
+	"github.com/ciclebyte/template_starter/library/liberr"
+)
+
+type sTemplatize struct{}
+
+func init() {
+	service.RegisterTemplatize(New())
+}
+
+func New() *sTemplatize {
+	return &sTemplatize{}
+}
+
+// templatizePlan 模板化对模板文件的修改
+type templatizePlan struct {
+	result   *api.TemplatizeResult
+	contents map[int64]string              // 文件ID到替换后的内容
+	renames  []*model.TemplatizeFileChange // 需要重命名的文件，上级目录在前
+}
+
+// Preview 预览模板化的修改，不修改模板文件
+func (s *sTemplatize) Preview(ctx context.Context, req *api.TemplatizePreviewReq) (res *api.TemplatizeResult, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		plan, err := s.plan(ctx, req.TemplateId, req.Literal, req.Variable, req.Variants)
+		liberr.ErrIsNil(ctx, err)
+		res = plan.result
+	})
+	return
+}
+
+// Apply 在一个事务中替换文件内容并重命名文件和目录，修改会记录文件修订历史
+func (s *sTemplatize) Apply(ctx context.Context, req *api.TemplatizeApplyReq) (res *api.TemplatizeResult, err error) {
+	err = g.Try(ctx, func(ctx context.Context) {
+		plan, err := s.plan(ctx, req.TemplateId, req.Literal, req.Variable, req.Variants)
+		liberr.ErrIsNil(ctx, err)
+		if len(plan.result.Files) == 0 {
+			liberr.ErrIsNil(ctx, gerror.Newf("未在模板中找到 %s 的任何写法", req.Literal))
+		}
+
+		err = dao.TemplateFiles.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			for id, content := range plan.contents {
+				err := service.TemplateFiles().Edit(ctx, &filesApi.TemplateFilesEditReq{Id: id, FileContent: content})
+				if err != nil {
+					return err
+				}
+			}
+			for _, change := range plan.renames {
+				err := service.TemplateFiles().Rename(ctx, &filesApi.TemplateFilesRenameReq{Id: change.FileId, FileName: change.NewFileName})
+				if err != nil {
+					return gerror.Wrapf(err, "重命名 %s 失败", change.FilePath)
+				}
+			}
+			return nil
+		})
+		liberr.ErrIsNil(ctx, err, "模板化失败")
+		plan.result.Applied = true
+		res = plan.result
+	})
+	return
+}
+
+// plan 计算字面量各种写法的替换表达式，以及对文件名、路径和内容的修改
+func (s *sTemplatize) plan(ctx context.Context, templateId int64, literal, variable string, only []string) (*templatizePlan, error) {
+	literal = strings.TrimSpace(literal)
+	if len(splitWords(literal)) == 0 {
+		return nil, gerror.New("字面量需要包含字母或数字")
+	}
+	delims, err := service.Templates().GetDelimiters(ctx, templateId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.TemplatizeResult{
+		TemplateId: templateId,
+		Variants:   []*model.TemplatizeVariant{},
+		Files:      []*model.TemplatizeFileChange{},
+		Warnings:   []string{},
+	}
+
+	// 生成各种写法的替换规则，无法由变量值得到的写法不替换
+	selected := make(map[string]bool, len(only))
+	for _, text := range only {
+		selected[text] = true
+	}
+	funcs := service.TemplateFiles().TemplateFuncMap()
+	var rules []*variantRule
+	for _, text := range caseVariants(literal) {
+		if len(selected) > 0 && !selected[text] {
+			continue
+		}
+		delete(selected, text)
+		pipeline, ok := resolvePipeline(funcs, literal, text)
+		if !ok {
+			// 自动生成的罕见写法没有对应的函数管道时直接跳过，只提示明确指定的写法
+			if len(only) > 0 {
+				result.Warnings = append(result.Warnings, "写法 "+text+" 无法由变量值通过模板函数得到，不会被替换")
+			}
+			continue
+		}
+		rules = append(rules, &variantRule{
+			Text:       text,
+			Pipeline:   pipeline,
+			Expression: expression(delims.Left, delims.Right, variable, pipeline),
+		})
+	}
+	for text := range selected {
+		result.Warnings = append(result.Warnings, "写法 "+text+" 不是 "+literal+" 的大小写变体，已忽略")
+	}
+	sortRules(rules)
+
+	var files []*entity.TemplateFiles
+	err = dao.TemplateFiles.Ctx(ctx).
+		Where(dao.TemplateFiles.Columns().TemplateId, templateId).
+		Scan(&files)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取模板文件失败")
+	}
+
+	plan := &templatizePlan{
+		result:   result,
+		contents: make(map[int64]string),
+	}
+	counts := make(map[string]int)
+	changes := make(map[int64]*model.TemplatizeFileChange)
+	newNames := make(map[int64]string)
+	for _, file := range files {
+		change := &model.TemplatizeFileChange{
+			FileId:      file.Id,
+			FilePath:    file.FilePath,
+			IsDirectory: file.IsDirectory,
+		}
+		name, nameCounts := templatizeText(file.FileName, delims.Left, delims.Right, rules)
+		for text, count := range nameCounts {
+			counts[text] += count
+			change.NameCount += count
+		}
+		if name != file.FileName {
+			change.NewFileName = name
+			newNames[file.Id] = name
+		}
+		if file.IsDirectory == 0 {
+			content, contentCounts := templatizeText(file.FileContent, delims.Left, delims.Right, rules)
+			for text, count := range contentCounts {
+				counts[text] += count
+				change.ContentCount += count
+			}
+			if content != file.FileContent {
+				plan.contents[file.Id] = content
+				change.Diff = unifiedDiff(file.FilePath, file.FileContent, content)
+			}
+		}
+		changes[file.Id] = change
+	}
+
+	// 上级目录重命名后下级文件的路径随之变化
+	byId := make(map[int64]*entity.TemplateFiles, len(files))
+	for _, file := range files {
+		byId[file.Id] = file
+	}
+	type pathState struct {
+		path    string
+		changed bool
+	}
+	newPaths := make(map[int64]pathState)
+	var newPath func(file *entity.TemplateFiles, depth int) pathState
+	newPath = func(file *entity.TemplateFiles, depth int) pathState {
+		if state, ok := newPaths[file.Id]; ok {
+			return state
+		}
+		name, renamed := newNames[file.Id]
+		if !renamed {
+			name = file.FileName
+		}
+		state := pathState{path: file.FilePath}
+		parent, ok := byId[int64(file.ParentId)]
+		if ok && depth < len(files) {
+			if parentState := newPath(parent, depth+1); parentState.changed {
+				state = pathState{path: parentState.path + "/" + name, changed: true}
+			}
+		}
+		if renamed && !state.changed {
+			state = pathState{path: name, changed: true}
+			if dir := path.Dir(file.FilePath); dir != "." {
+				state.path = dir + "/" + name
+			}
+		}
+		newPaths[file.Id] = state
+		return state
+	}
+	for _, file := range files {
+		change := changes[file.Id]
+		if state := newPath(file, 0); state.changed {
+			change.NewFilePath = state.path
+		}
+		if change.NewFilePath != "" || change.NameCount > 0 || change.ContentCount > 0 {
+			result.Files = append(result.Files, change)
+		}
+		if change.NewFileName != "" {
+			plan.renames = append(plan.renames, change)
+		}
+	}
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].FilePath < result.Files[j].FilePath
+	})
+	sort.SliceStable(plan.renames, func(i, j int) bool {
+		return strings.Count(plan.renames[i].FilePath, "/") < strings.Count(plan.renames[j].FilePath, "/")
+	})
+
+	for _, rule := range rules {
+		result.Variants = append(result.Variants, &model.TemplatizeVariant{
+			Text:       rule.Text,
+			Pipeline:   rule.Pipeline,
+			Expression: rule.Expression,
+			Count:      counts[rule.Text],
+		})
+	}
+	sort.SliceStable(result.Variants, func(i, j int) bool {
+		return result.Variants[i].Count > result.Variants[j].Count
+	})
+	if len(result.Files) == 0 {
+		result.Warnings = append(result.Warnings, "未在模板中找到 "+literal+" 的任何写法")
+	}
+	if warning := s.checkVariable(ctx, templateId, literal, variable); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+	return plan, nil
+}
+
+// checkVariable 目标变量未在模板变量中定义时给出提示
+func (s *sTemplatize) checkVariable(ctx context.Context, templateId int64, literal, variable string) string {
+	variables, err := service.Templates().GetVariables(ctx, templateId, model.ReleaseVersionDraft)
+	if err != nil {
+		return ""
+	}
+	root := strings.SplitN(variable, ".", 2)[0]
+	for _, def := range variables.CustomVariables {
+		if def.Name == root {
+			return ""
+		}
+	}
+	return "变量 " + root + " 未在模板变量中定义，请添加该变量，默认值可设为 " + literal
+}
+
+// unifiedDiff 生成文件内容替换前后的 unified diff
+func unifiedDiff(filePath, from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: "a/" + filePath,
+		ToFile:   "b/" + filePath,
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
@@ -0,0 +1,214 @@
+package templatize
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// candidatePipelines 由变量值得到字面量各种写法的函数管道，按优先级排列，
+// 对每种写法取第一个作用于原字面量后结果一致的管道
+var candidatePipelines = []string{
+	"",
+	"kebabcase",
+	"snakecase",
+	"camelcase",
+	"camelcase | untitle",
+	"snakecase | upper",
+	"kebabcase | upper",
+	"camelcase | lower",
+	"camelcase | upper",
+	"lower",
+	"upper",
+	"title",
+	`snakecase | replace "_" "."`,
+	`snakecase | replace "_" " "`,
+	`snakecase | replace "_" " " | title`,
+	`snakecase | replace "_" " " | upper`,
+	"kebabcase | title",
+	`snakecase | upper | replace "_" "."`,
+	`snakecase | replace "_" " " | title | replace " " "_"`,
+	`snakecase | replace "_" " " | title | replace " " "."`,
+}
+
+// variantRule 一种写法的替换规则
+type variantRule struct {
+	Text       string
+	Pipeline   string
+	Expression string
+}
+
+// splitWords 将字面量拆分为单词，非字母数字字符和大小写边界均视为分隔，
+// 连续大写字母视为一个缩写词，如 HTTPServer 拆分为 HTTP 和 Server
+func splitWords(literal string) []string {
+	runes := []rune(literal)
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		if !isAlnum(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) {
+			prev := current[len(current)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// caseVariants 生成字面量常见的大小写写法，原字面量排在第一位
+func caseVariants(literal string) []string {
+	words := splitWords(literal)
+	seen := map[string]bool{literal: true}
+	variants := []string{literal}
+	add := func(text string) {
+		if text != "" && !seen[text] {
+			seen[text] = true
+			variants = append(variants, text)
+		}
+	}
+
+	lower := make([]string, len(words))
+	upper := make([]string, len(words))
+	title := make([]string, len(words))
+	for i, word := range words {
+		lower[i] = strings.ToLower(word)
+		upper[i] = strings.ToUpper(word)
+		title[i] = titleWord(word)
+	}
+	for _, sep := range []string{"-", "_", "", ".", " "} {
+		add(strings.Join(lower, sep))
+		add(strings.Join(upper, sep))
+		add(strings.Join(title, sep))
+	}
+	if len(words) > 1 {
+		add(lower[0] + strings.Join(title[1:], ""))
+	}
+	return variants
+}
+
+// resolvePipeline 找出由变量值得到该写法的函数管道，变量值即原字面量
+func resolvePipeline(funcs template.FuncMap, literal, variant string) (string, bool) {
+	for _, pipeline := range candidatePipelines {
+		source := "{{.V}}"
+		if pipeline != "" {
+			source = "{{.V | " + pipeline + "}}"
+		}
+		tpl, err := template.New("").Funcs(funcs).Parse(source)
+		if err != nil {
+			continue
+		}
+		var out bytes.Buffer
+		if err := tpl.Execute(&out, map[string]interface{}{"V": literal}); err != nil {
+			continue
+		}
+		if out.String() == variant {
+			return pipeline, true
+		}
+	}
+	return "", false
+}
+
+// expression 生成替换成的模板表达式，使用模板配置的定界符
+func expression(left, right, variable, pipeline string) string {
+	if pipeline == "" {
+		return left + "." + variable + right
+	}
+	return left + "." + variable + " | " + pipeline + right
+}
+
+// templatizeText 将文本中各种写法替换为模板表达式，已有的模板动作原样保留，返回替换后的文本和每种写法的替换次数
+func templatizeText(text, left, right string, rules []*variantRule) (string, map[string]int) {
+	counts := make(map[string]int)
+	var out strings.Builder
+	for text != "" {
+		start := strings.Index(text, left)
+		if start < 0 {
+			replaceLiterals(&out, text, rules, counts)
+			break
+		}
+		replaceLiterals(&out, text[:start], rules, counts)
+		end := strings.Index(text[start+len(left):], right)
+		if end < 0 {
+			out.WriteString(text[start:])
+			break
+		}
+		end += start + len(left) + len(right)
+		out.WriteString(text[start:end])
+		text = text[end:]
+	}
+	return out.String(), counts
+}
+
+// replaceLiterals 在不含模板动作的文本中替换各种写法，较长的写法优先匹配
+func replaceLiterals(out *strings.Builder, text string, rules []*variantRule, counts map[string]int) {
+	for i := 0; i < len(text); {
+		matched := false
+		for _, rule := range rules {
+			end := i + len(rule.Text)
+			if strings.HasPrefix(text[i:], rule.Text) && isWordBoundary(text, i, end) {
+				out.WriteString(rule.Expression)
+				counts[rule.Text]++
+				i = end
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			out.WriteString(text[i : i+size])
+			i += size
+		}
+	}
+}
+
+// isWordBoundary 判断 text[start:end] 是否为完整的单词，前后紧邻字母或数字时只接受驼峰边界，
+// 避免 my-service 匹配到 my-services 中、api 匹配到 rapid 中
+func isWordBoundary(text string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	last, _ := utf8.DecodeLastRuneInString(text[start:end])
+	if start > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isAlnum(prev) && !(unicode.IsUpper(first) && !unicode.IsUpper(prev)) {
+			return false
+		}
+	}
+	if end < len(text) {
+		next, _ := utf8.DecodeRuneInString(text[end:])
+		if isAlnum(next) && !(unicode.IsUpper(next) && !unicode.IsUpper(last)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRules 较长的写法排在前面，避免被其中包含的较短写法抢先匹配
+func sortRules(rules []*variantRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].Text) > len(rules[j].Text)
+	})
+}
+
+func titleWord(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + strings.ToLower(word[size:])
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
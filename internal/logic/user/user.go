@@ -245,6 +245,13 @@ func (s *sUser) UpdateUser(ctx context.Context, req *user.UpdateUserReq) (*user.
 		return nil, err
 	}
 
+	// 禁用账户时撤销全部登录会话
+	if req.Status == 0 {
+		if _, err = service.Auth().RevokeUserSessions(ctx, req.Id, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	return &user.UpdateUserRes{}, nil
 }
 
@@ -385,9 +392,36 @@ func (s *sUser) UpdateUserStatus(ctx context.Context, req *user.UpdateUserStatus
 		return nil, errors.New("更新用户状态失败")
 	}
 
+	// 禁用账户时撤销全部登录会话，已签发的令牌立即失效
+	if req.Status == 0 {
+		if _, err = service.Auth().RevokeUserSessions(ctx, req.Id, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	return &user.UpdateUserStatusRes{}, nil
 }
 
+// RevokeUserSessions 撤销用户的全部登录会话
+func (s *sUser) RevokeUserSessions(ctx context.Context, req *user.RevokeUserSessionsReq) (*user.RevokeUserSessionsRes, error) {
+	// 检查用户是否存在
+	exists, err := dao.Users.Ctx(ctx).Where("id", req.Id).One()
+	if err != nil {
+		g.Log().Error(ctx, "check user exists failed:", err)
+		return nil, err
+	}
+	if exists.IsEmpty() {
+		return nil, errors.New("用户不存在")
+	}
+
+	revoked, err := service.Auth().RevokeUserSessions(ctx, req.Id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &user.RevokeUserSessionsRes{Revoked: revoked}, nil
+}
+
 // AssignUserRoles 分配用户角色
 func (s *sUser) AssignUserRoles(ctx context.Context, req *user.AssignUserRolesReq) (*user.AssignUserRolesRes, error) {
 	// 检查用户是否存在
@@ -18,6 +18,7 @@ type ApiKeys struct {
 	KeyId       interface{} // API Key ID (公开标识)
 	KeySecret   interface{} // API Key Secret (加密存储)
 	Permissions interface{} // API Key 权限列表
+	TemplateIds interface{} // 限定访问的模板ID列表
 	LastUsedAt  *gtime.Time // 最后使用时间
 	LastUsedIp  interface{} // 最后使用IP
 	ExpiresAt   *gtime.Time // 过期时间
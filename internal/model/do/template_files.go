@@ -25,4 +25,5 @@ type TemplateFiles struct {
 	CreatedAt         *gtime.Time // 记录创建时间
 	UpdatedAt         *gtime.Time //
 	GenerateCondition interface{} //
+	RepeatConfig      interface{} // 按数组变量批量生成配置，JSON格式
 }
@@ -24,4 +24,6 @@ type Templates struct {
 	Icon         interface{} // 模板图标名称
 	TemplateType interface{} // 模板类型：basic=基础模板，scaffold=脚手架模板，data_driven=数据驱动模板
 	TypeConfig   interface{} // 类型相关配置，JSON格式
+	DeletedAt    *gtime.Time // 删除时间，不为空表示在回收站中
+	DeletedBy    interface{} // 删除人用户名
 }
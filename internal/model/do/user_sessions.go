@@ -11,13 +11,16 @@ import (
 
 // UserSessions is the golang structure of table user_sessions for DAO operations like Where/Data.
 type UserSessions struct {
-	g.Meta    `orm:"table:user_sessions, do:true"`
-	Id        interface{} // 会话ID
-	UserId    interface{} // 用户ID
-	IpAddress interface{} // IP地址
-	UserAgent interface{} // 用户代理
-	Data      interface{} // 会话数据
-	ExpiresAt *gtime.Time // 过期时间
-	CreatedAt *gtime.Time //
-	UpdatedAt *gtime.Time //
+	g.Meta         `orm:"table:user_sessions, do:true"`
+	Id             interface{} // 会话ID
+	UserId         interface{} // 用户ID
+	IpAddress      interface{} // IP地址
+	UserAgent      interface{} // 用户代理
+	Data           interface{} // 会话数据
+	RefreshTokenId interface{} // 当前有效的刷新令牌ID（jti），每次刷新时轮换
+	ExpiresAt      *gtime.Time // 过期时间
+	LastSeenAt     *gtime.Time // 最后活跃时间
+	RevokedAt      *gtime.Time // 撤销时间，不为空表示会话已失效
+	CreatedAt      *gtime.Time //
+	UpdatedAt      *gtime.Time //
 }
@@ -16,6 +16,7 @@ type ApiKeys struct {
 	KeyId       string      `json:"keyId"       description:"API Key ID (公开标识)"`
 	KeySecret   string      `json:"keySecret"   description:"API Key Secret (加密存储)"`
 	Permissions string      `json:"permissions" description:"API Key 权限列表"`
+	TemplateIds string      `json:"templateIds" description:"限定访问的模板ID列表"`
 	LastUsedAt  *gtime.Time `json:"lastUsedAt"  description:"最后使用时间"`
 	LastUsedIp  string      `json:"lastUsedIp"  description:"最后使用IP"`
 	ExpiresAt   *gtime.Time `json:"expiresAt"   description:"过期时间"`
@@ -23,4 +23,5 @@ type TemplateFiles struct {
 	CreatedAt         *gtime.Time `json:"createdAt"         description:"记录创建时间"`
 	UpdatedAt         *gtime.Time `json:"updatedAt"         description:""`
 	GenerateCondition string      `json:"generateCondition" description:""`
+	RepeatConfig      string      `json:"repeatConfig"      description:"按数组变量批量生成配置，JSON格式"`
 }
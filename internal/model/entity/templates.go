@@ -22,4 +22,6 @@ type Templates struct {
 	Icon         string      `json:"icon"         description:"模板图标名称"`
 	TemplateType string      `json:"templateType" description:"模板类型：basic=基础模板，scaffold=脚手架模板，data_driven=数据驱动模板"`
 	TypeConfig   string      `json:"typeConfig"   description:"类型相关配置，JSON格式"`
+	DeletedAt    *gtime.Time `json:"deletedAt"    description:"删除时间，不为空表示在回收站中"`
+	DeletedBy    string      `json:"deletedBy"    description:"删除人用户名"`
 }
@@ -10,12 +10,15 @@ import (
 
 // UserSessions is the golang structure for table user_sessions.
 type UserSessions struct {
-	Id        string      `json:"id"        description:"会话ID"`
-	UserId    int64       `json:"userId"    description:"用户ID"`
-	IpAddress string      `json:"ipAddress" description:"IP地址"`
-	UserAgent string      `json:"userAgent" description:"用户代理"`
-	Data      string      `json:"data"      description:"会话数据"`
-	ExpiresAt *gtime.Time `json:"expiresAt" description:"过期时间"`
-	CreatedAt *gtime.Time `json:"createdAt" description:""`
-	UpdatedAt *gtime.Time `json:"updatedAt" description:""`
+	Id             string      `json:"id"             description:"会话ID"`
+	UserId         int64       `json:"userId"         description:"用户ID"`
+	IpAddress      string      `json:"ipAddress"      description:"IP地址"`
+	UserAgent      string      `json:"userAgent"      description:"用户代理"`
+	Data           string      `json:"data"           description:"会话数据"`
+	RefreshTokenId string      `json:"refreshTokenId" description:"当前有效的刷新令牌ID（jti），每次刷新时轮换"`
+	ExpiresAt      *gtime.Time `json:"expiresAt"      description:"过期时间"`
+	LastSeenAt     *gtime.Time `json:"lastSeenAt"     description:"最后活跃时间"`
+	RevokedAt      *gtime.Time `json:"revokedAt"      description:"撤销时间，不为空表示会话已失效"`
+	CreatedAt      *gtime.Time `json:"createdAt"      description:""`
+	UpdatedAt      *gtime.Time `json:"updatedAt"      description:""`
 }
@@ -1,9 +1,13 @@
 package model
 
 // GenerateCondition 文件生成条件
+//
+// Expression 非空时按表达式求值，例如 database == "mysql" && (enableCache || env in ["prod","staging"])；
+// 为空时沿用旧的单变量布尔比较（VariableName == ExpectedValue）。
 type GenerateCondition struct {
-	Enabled       bool   `json:"enabled"`       // 是否启用条件
-	VariableName  string `json:"variableName"`  // 关联变量名
-	ExpectedValue bool   `json:"expectedValue"` // 期望值
-	Description   string `json:"description"`   // 条件描述
-}
\ No newline at end of file
+	Enabled       bool   `json:"enabled"`              // 是否启用条件
+	Expression    string `json:"expression,omitempty"` // 条件表达式
+	VariableName  string `json:"variableName"`         // 关联变量名
+	ExpectedValue bool   `json:"expectedValue"`        // 期望值
+	Description   string `json:"description"`          // 条件描述
+}
@@ -0,0 +1,19 @@
+package model
+
+// RateLimitConfig 接口限流配置，保存在系统配置 system.rate_limit 中
+type RateLimitConfig struct {
+	Enabled  bool               `json:"enabled"`
+	Policies []*RateLimitPolicy `json:"policies"` // 按顺序匹配，一个接口只使用第一个匹配的策略
+	// TrustedProxies 可信反向代理的IP或CIDR，只有直接连接的对端在其中时才从 X-Forwarded-For、X-Real-IP 取客户端IP，
+	// 为空时匿名访问按连接的对端IP计数
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+// RateLimitPolicy 一组接口的令牌桶限流策略，每个用户、API Key 或匿名访问的客户端IP各有一个令牌桶
+type RateLimitPolicy struct {
+	Name   string   `json:"name"`   // 策略名称，不同策略的令牌桶相互独立
+	Routes []string `json:"routes"` // 去掉 /api/v1 后的路由前缀，如 /auth/login、/ai
+	Limit  int      `json:"limit"`  // 每个时间窗口补充的令牌数
+	Window int      `json:"window"` // 时间窗口，单位秒
+	Burst  int      `json:"burst"`  // 令牌桶容量，即允许的突发请求数，为0时等于 limit
+}
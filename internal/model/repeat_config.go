@@ -0,0 +1,19 @@
+package model
+
+// RepeatConfig 文件批量生成配置
+//
+// 启用后渲染器会遍历 Over 指向的数组变量，为每个元素生成一个文件，
+// 当前元素和下标分别以 ItemAlias、IndexAlias 注入到文件名、路径和内容的渲染变量中，
+// 例如 internal/model/{{.item.Name | snakecase}}.go。
+type RepeatConfig struct {
+	Enabled    bool   `json:"enabled"`    // 是否启用批量生成
+	Over       string `json:"over"`       // 数组变量路径，支持嵌套字段，如 tables、schema.tables
+	ItemAlias  string `json:"itemAlias"`  // 当前元素的变量名，默认 item
+	IndexAlias string `json:"indexAlias"` // 当前下标的变量名，默认 index
+}
+
+// 批量生成默认别名
+const (
+	DefaultRepeatItemAlias  = "item"
+	DefaultRepeatIndexAlias = "index"
+)
@@ -0,0 +1,91 @@
+package model
+
+// 模板包格式：ZIP 压缩包，根目录下的 manifest.json 描述模板元数据，
+// files/ 目录下按原路径保存未渲染的模板文件
+const (
+	BundleFormatVersion = 1
+	BundleManifestName  = "manifest.json"
+	BundleFilesDir      = "files/"
+	BundleFileExt       = ".tsbundle.zip"
+)
+
+// 导入时模板名称冲突的处理方式
+const (
+	BundleConflictError     = "error"     // 名称已存在时报错
+	BundleConflictRename    = "rename"    // 自动重命名为 name-2、name-3 ...
+	BundleConflictOverwrite = "overwrite" // 覆盖同名模板的内容，保留模板ID
+)
+
+// TemplateBundleManifest 模板包清单，实体之间按名称或代码关联，便于在不同实例间迁移
+type TemplateBundleManifest struct {
+	FormatVersion int                       `json:"formatVersion"`
+	ExportedAt    string                    `json:"exportedAt"`
+	Template      *TemplateBundleTemplate   `json:"template"`
+	Category      *TemplateBundleCategory   `json:"category,omitempty"`
+	Tags          []*TemplateBundleTag      `json:"tags"`
+	Languages     []*TemplateBundleLanguage `json:"languages"`
+	ExposeFields  *TemplateBundleExpose     `json:"exposeFields,omitempty"`
+	Presets       []*TemplateBundlePreset   `json:"presets"`
+	Files         []*TemplateBundleFile     `json:"files"`
+}
+
+// TemplateBundleTemplate 模板基本信息
+type TemplateBundleTemplate struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Introduction string `json:"introduction"`
+	TemplateType string `json:"templateType"`
+	TypeConfig   string `json:"typeConfig"`
+	IsFeatured   int    `json:"isFeatured"`
+	Logo         string `json:"logo"`
+	Icon         string `json:"icon"`
+}
+
+// TemplateBundleCategory 模板分类，导入时按名称匹配，不存在时创建
+type TemplateBundleCategory struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Sort        int    `json:"sort"`
+}
+
+// TemplateBundleTag 模板标签，导入时按名称匹配，不存在时创建
+type TemplateBundleTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TemplateBundleLanguage 模板语言，导入时按语言代码匹配
+type TemplateBundleLanguage struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	IsPrimary int    `json:"isPrimary"`
+}
+
+// TemplateBundleExpose 模板暴露字段（变量定义）
+type TemplateBundleExpose struct {
+	FieldSchemaJson string `json:"fieldSchemaJson"`
+	Version         string `json:"version"`
+	Description     string `json:"description"`
+}
+
+// TemplateBundlePreset 模板订阅的变量预设，导入时按名称匹配，不存在时作为自定义预设创建
+type TemplateBundlePreset struct {
+	Name            string `json:"name"`
+	DisplayName     string `json:"displayName"`
+	Description     string `json:"description"`
+	SchemaJson      string `json:"schemaJson"`
+	DefaultDataJson string `json:"defaultDataJson"`
+	Icon            string `json:"icon"`
+	Version         string `json:"version"`
+}
+
+// TemplateBundleFile 模板文件元数据，文件内容保存在 files/<path>
+type TemplateBundleFile struct {
+	Path              string `json:"path"`
+	IsDirectory       int    `json:"isDirectory"`
+	Sort              int    `json:"sort"`
+	Md5               string `json:"md5"`
+	GenerateCondition string `json:"generateCondition,omitempty"`
+	RepeatConfig      string `json:"repeatConfig,omitempty"`
+}
@@ -0,0 +1,34 @@
+package model
+
+// 模板文件修订的变更类型
+const (
+	FileRevisionActionCreate    = "create"    // 新增文件
+	FileRevisionActionEdit      = "edit"      // 修改内容
+	FileRevisionActionRename    = "rename"    // 重命名
+	FileRevisionActionMove      = "move"      // 移动到其他目录
+	FileRevisionActionCondition = "condition" // 修改生成条件
+	FileRevisionActionRepeat    = "repeat"    // 修改批量生成配置
+	FileRevisionActionDelete    = "delete"    // 删除文件，记录删除前的内容
+	FileRevisionActionRestore   = "restore"   // 恢复到历史修订
+)
+
+// TemplateFileRevisionInfo 模板文件修订信息，列表中不含文件内容
+type TemplateFileRevisionInfo struct {
+	Id                uint64 `json:"id"`
+	FileId            int64  `json:"fileId"`
+	TemplateId        uint64 `json:"templateId"`
+	Revision          int    `json:"revision"`
+	Action            string `json:"action"`
+	FilePath          string `json:"filePath"`
+	FileName          string `json:"fileName"`
+	FileContent       string `json:"fileContent,omitempty"`
+	FileSize          uint   `json:"fileSize"`
+	IsDirectory       int    `json:"isDirectory"`
+	Md5               string `json:"md5"`
+	ParentId          uint64 `json:"parentId"`
+	GenerateCondition string `json:"generateCondition"`
+	RepeatConfig      string `json:"repeatConfig"`
+	AuthorId          uint64 `json:"authorId"`
+	AuthorName        string `json:"authorName"`
+	CreatedAt         string `json:"createdAt"`
+}
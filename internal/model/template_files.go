@@ -14,6 +14,7 @@ type TemplateFilesInfo struct {
 	Sort              int         `orm:"sort"  json:"sort"`                // 排序
 	ParentId          int         `orm:"parent_id"  json:"parentId"`       // 父目录ID，如果是文件则指向所属目录
 	GenerateCondition string      `orm:"generate_condition"  json:"generateCondition"` // 生成条件JSON
+	RepeatConfig      string      `orm:"repeat_config"  json:"repeatConfig"`           // 批量生成配置JSON
 	CreatedAt         *gtime.Time `orm:"created_at"  json:"createdAt"`     // 记录创建时间
 	UpdatedAt         *gtime.Time `orm:"updated_at"  json:"updatedAt"`     // None
 }
@@ -0,0 +1,39 @@
+package model
+
+// 拉取上游修改时单个文件的处理结果
+const (
+	MergeActionAdded    = "added"    // 上游新增的文件，已添加到当前模板
+	MergeActionModified = "modified" // 当前模板未修改，已直接采用上游内容
+	MergeActionDeleted  = "deleted"  // 上游已删除且当前模板未修改，已删除
+	MergeActionMerged   = "merged"   // 双方都有修改且互不冲突，已自动合并
+	MergeActionConflict = "conflict" // 双方的修改存在冲突，需要手动解决
+)
+
+// 冲突类型
+const (
+	MergeConflictContent      = "content"       // 双方修改了同一处内容，文件中已写入冲突标记
+	MergeConflictAddAdd       = "add-add"       // 双方新增了同一路径的不同文件，文件中已写入冲突标记
+	MergeConflictDeleteModify = "delete-modify" // 上游删除了当前模板修改过的文件，已保留当前内容
+	MergeConflictModifyDelete = "modify-delete" // 当前模板删除了上游修改过的文件，已恢复为上游内容
+)
+
+// TemplateUpstreamInfo Fork 出的模板的上游来源
+type TemplateUpstreamInfo struct {
+	TemplateId         uint64 `json:"templateId"`
+	UpstreamTemplateId uint64 `json:"upstreamTemplateId"`
+	UpstreamName       string `json:"upstreamName"` // 上游模板已删除时为空
+	BaseVersion        string `json:"baseVersion"`  // 最近一次同步的上游版本，draft 表示上游当时未发布的内容
+	BaseFileCount      int    `json:"baseFileCount"`
+	SyncedBy           string `json:"syncedBy"`
+	SyncedAt           string `json:"syncedAt"` // 最近一次拉取上游修改的时间，未拉取过为空
+	ForkedAt           string `json:"forkedAt"`
+}
+
+// TemplateMergeFile 拉取上游修改时单个文件的处理结果
+type TemplateMergeFile struct {
+	Path         string `json:"path"`
+	Action       string `json:"action"`                 // added、modified、deleted、merged 或 conflict
+	ConflictType string `json:"conflictType,omitempty"` // content、add-add、delete-modify 或 modify-delete
+	Conflicts    int    `json:"conflicts,omitempty"`    // 文件中冲突块的数量
+	Content      string `json:"content,omitempty"`      // 写入冲突标记后的内容，仅内容冲突时返回
+}
@@ -0,0 +1,22 @@
+package model
+
+// DefaultGitRef 未指定引用时导入的引用
+const DefaultGitRef = "HEAD"
+
+// TemplateGitSourceInfo 模板最近一次从 git 仓库导入的来源
+type TemplateGitSourceInfo struct {
+	TemplateId    uint64 `json:"templateId"`
+	Repository    string `json:"repository"`    // 仓库路径，本地路径或 file:// 地址
+	Ref           string `json:"ref"`           // 导入的引用
+	Subdir        string `json:"subdir"`        // 作为模板根目录的子目录
+	Commit        string `json:"commit"`        // 导入时引用指向的提交
+	CommitMessage string `json:"commitMessage"` // 提交说明的首行
+	ImportedBy    string `json:"importedBy"`
+	ImportedAt    string `json:"importedAt"`
+}
+
+// TemplateGitCommit 两次导入之间的提交
+type TemplateGitCommit struct {
+	Commit  string `json:"commit"`  // 提交的短哈希
+	Subject string `json:"subject"` // 提交说明的首行
+}
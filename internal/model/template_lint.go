@@ -0,0 +1,31 @@
+package model
+
+// 模板检查问题的严重程度
+const (
+	LintSeverityError   = "error"   // 渲染时必然出错
+	LintSeverityWarning = "warning" // 可能导致渲染结果不符合预期
+)
+
+// 模板检查规则
+const (
+	LintRuleSyntax            = "syntax"              // 模板语法错误
+	LintRuleUndefinedFunction = "undefined-function"  // 调用了未注册的函数
+	LintRuleUndefinedTemplate = "undefined-template"  // {{template}} 引用了未定义的模板
+	LintRuleUndeclaredVar     = "undeclared-variable" // 使用了未声明的变量
+	LintRuleComputedVariable  = "computed-variable"   // 计算变量无法计算
+	LintRuleCondition         = "condition"           // 生成条件无效或引用了未声明的变量
+	LintRuleRepeat            = "repeat"              // 批量生成配置无效或引用了未声明的变量
+	LintRuleEmptyPath         = "empty-path"          // 路径渲染后出现空的文件名或目录名
+	LintRulePathCollision     = "path-collision"      // 多个文件渲染到同一路径
+)
+
+// TemplateLintIssue 模板检查发现的问题
+type TemplateLintIssue struct {
+	Severity string `json:"severity"` // error 或 warning
+	Rule     string `json:"rule"`     // 规则，见 LintRule* 常量
+	FileId   int64  `json:"fileId"`   // 所在文件ID，变量定义的问题为 0
+	File     string `json:"file"`     // 所在文件路径
+	Part     string `json:"part"`     // 所在部分：fileContent、fileName、filePath、condition、repeat、variables
+	Line     int    `json:"line"`     // 行号，无法定位时为 0
+	Message  string `json:"message"`
+}
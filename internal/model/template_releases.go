@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/ciclebyte/template_starter/internal/model/entity"
+)
+
+// 模板发布版本状态
+const (
+	ReleaseStatusPublished  = "published"
+	ReleaseStatusDeprecated = "deprecated"
+)
+
+// 渲染时指定的特殊版本
+const (
+	ReleaseVersionLatest = "latest" // 最新发布版本，等同于不指定版本
+	ReleaseVersionDraft  = "draft"  // 模板当前的工作区内容，未发布的修改
+)
+
+// TemplateReleaseSnapshot 发布版本快照，包含渲染所需的全部内容，发布后不再随模板修改而变化
+type TemplateReleaseSnapshot struct {
+	Files           []*entity.TemplateFiles `json:"files"`           // 文件树，含生成条件和批量生成配置
+	FieldSchemaJson string                  `json:"fieldSchemaJson"` // 发布时的暴露字段定义原文
+	Variables       []*TemplateVariableDef  `json:"variables"`       // 解析后的变量定义
+	Delimiters      *TemplateDelimiters     `json:"delimiters"`      // 模板定界符
+}
+
+// TemplateReleaseInfo 发布版本信息，不含快照内容
+type TemplateReleaseInfo struct {
+	Id               uint64 `json:"id"`
+	TemplateId       uint64 `json:"templateId"`
+	Version          string `json:"version"`
+	Status           string `json:"status"`
+	Changelog        string `json:"changelog"`
+	DeprecatedReason string `json:"deprecatedReason"`
+	FileCount        int    `json:"fileCount"`
+	IsLatest         bool   `json:"isLatest"` // 是否为默认渲染的最新发布版本
+	DeprecatedAt     string `json:"deprecatedAt"`
+	CreatedAt        string `json:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package model
+
+// TemplateSearchMatch 文件内容中的一处匹配
+type TemplateSearchMatch struct {
+	Id     string   `json:"id"`     // 匹配ID，替换时用于选择要替换的匹配
+	Line   int      `json:"line"`   // 所在行号，从1开始
+	Column int      `json:"column"` // 所在列号，从1开始，按字符计算
+	Text   string   `json:"text"`   // 匹配所在行的完整内容
+	Match  string   `json:"match"`  // 匹配到的文本
+	Before []string `json:"before"` // 匹配所在行之前的上下文行
+	After  []string `json:"after"`  // 匹配所在行之后的上下文行
+}
+
+// TemplateSearchFile 单个文件中的匹配
+type TemplateSearchFile struct {
+	TemplateId   int64                  `json:"templateId"`
+	TemplateName string                 `json:"templateName"`
+	FileId       int64                  `json:"fileId"`
+	FilePath     string                 `json:"filePath"`
+	Matches      []*TemplateSearchMatch `json:"matches"`
+}
+
+// TemplateReplaceFile 替换对单个文件的修改
+type TemplateReplaceFile struct {
+	TemplateId   int64  `json:"templateId"`
+	TemplateName string `json:"templateName"`
+	FileId       int64  `json:"fileId"`
+	FilePath     string `json:"filePath"`
+	Replaced     int    `json:"replaced"` // 替换的匹配数量
+	Diff         string `json:"diff"`     // 内容的 unified diff
+}
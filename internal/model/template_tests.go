@@ -0,0 +1,37 @@
+package model
+
+// 测试用例的运行结果
+const (
+	TestStatusPassed = "passed" // 所有文件与期望结果一致
+	TestStatusFailed = "failed" // 存在与期望结果不一致的文件
+	TestStatusError  = "error"  // 变量校验失败或渲染出错，无法与期望结果比较
+)
+
+// 单个文件的比较结果
+const (
+	TestFileStatusPassed     = "passed"     // 渲染结果与期望内容一致
+	TestFileStatusFailed     = "failed"     // 渲染结果与期望内容不一致
+	TestFileStatusMissing    = "missing"    // 期望中的文件没有被渲染出来
+	TestFileStatusUnexpected = "unexpected" // 校验完整文件树时，渲染出了期望中没有的文件
+)
+
+// TemplateTestCaseInfo 模板测试用例
+type TemplateTestCaseInfo struct {
+	Id          uint64                 `json:"id"`
+	TemplateId  uint64                 `json:"templateId"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Variables   map[string]interface{} `json:"variables"`          // 渲染使用的变量值
+	Expected    map[string]string      `json:"expected,omitempty"` // 期望的渲染结果，键为渲染后的文件路径
+	FileCount   int                    `json:"fileCount"`          // 期望结果中的文件数
+	FullTree    bool                   `json:"fullTree"`           // 是否校验完整文件树
+	CreatedAt   string                 `json:"createdAt"`
+	UpdatedAt   string                 `json:"updatedAt"`
+}
+
+// TemplateTestFileResult 测试用例中单个文件的比较结果
+type TemplateTestFileResult struct {
+	Path   string `json:"path"`           // 渲染后的文件路径
+	Status string `json:"status"`         // passed、failed、missing 或 unexpected
+	Diff   string `json:"diff,omitempty"` // 期望内容到渲染结果的 unified diff
+}
@@ -0,0 +1,21 @@
+package model
+
+// TemplateTypeConfig 模板类型相关配置，对应 templates.type_config 字段的 JSON 内容
+type TemplateTypeConfig struct {
+	Delimiters *TemplateDelimiters `json:"delimiters,omitempty"` // 自定义模板定界符，未配置时使用 {{ }}
+}
+
+// TemplateDelimiters 模板定界符
+//
+// 用于生成本身包含 {{ }} 的文件（Go 模板、Helm Chart、Vue 单文件组件等），
+// 例如配置为 [[ ]] 后，模板中写作 [[ .ProjectName ]]，而 {{ }} 原样输出。
+type TemplateDelimiters struct {
+	Left  string `json:"left"`  // 左定界符，如 [[、<%
+	Right string `json:"right"` // 右定界符，如 ]]、%>
+}
+
+// 默认模板定界符
+const (
+	DefaultLeftDelim  = "{{"
+	DefaultRightDelim = "}}"
+)
@@ -0,0 +1,40 @@
+package model
+
+// TemplateVariableDef 模板变量定义，对应模板暴露字段 FieldSchemaJson 中的一项
+//
+//	{
+//	  "ProjectName": {"type": "string", "required": true, "pattern": "^[a-z][a-z0-9-]*$"},
+//	  "Port":        {"type": "integer", "default": 8080, "min": 1, "max": 65535},
+//	  "Database":    {"type": "enum", "enum": ["mysql", "postgres"], "default": "mysql"},
+//	  "PackageName": {"type": "string", "computed": "{{.ProjectName | lower | replace \"-\" \"_\"}}"}
+//	}
+type TemplateVariableDef struct {
+	Name        string                          `json:"name"`                 // 变量名，即 FieldSchemaJson 中的键
+	Type        string                          `json:"type"`                 // 变量类型，见 VariableType* 常量
+	Description string                          `json:"description"`          // 变量说明
+	Default     interface{}                     `json:"default,omitempty"`    // 默认值，未传入时使用
+	Required    bool                            `json:"required"`             // 是否必填
+	Enum        []interface{}                   `json:"enum,omitempty"`       // 可选值
+	Pattern     string                          `json:"pattern,omitempty"`    // 字符串的正则约束
+	Min         *float64                        `json:"min,omitempty"`        // 数值的最小值
+	Max         *float64                        `json:"max,omitempty"`        // 数值的最大值
+	MinItems    *int                            `json:"minItems,omitempty"`   // 数组的最少元素数
+	MaxItems    *int                            `json:"maxItems,omitempty"`   // 数组的最多元素数
+	Items       *TemplateVariableDef            `json:"items,omitempty"`      // 数组元素的定义
+	Properties  map[string]*TemplateVariableDef `json:"properties,omitempty"` // 对象字段的定义
+	Computed    string                          `json:"computed,omitempty"`   // 计算表达式，设置后变量值由其他变量计算得出，不接受输入
+	Sort        int                             `json:"sort"`                 // 在 FieldSchemaJson 中的顺序
+}
+
+// 模板变量类型
+const (
+	VariableTypeString    = "string"
+	VariableTypeSecret    = "secret"
+	VariableTypeInteger   = "integer"
+	VariableTypeNumber    = "number"
+	VariableTypeBoolean   = "boolean"
+	VariableTypeEnum      = "enum"
+	VariableTypeArray     = "array"
+	VariableTypeObject    = "object"
+	VariableTypeObjectArr = "object_arr"
+)
@@ -13,3 +13,14 @@ type TemplatesInfo struct {
 	Icon         string                  `orm:"icon"  json:"icon"`                       // 模板图标名称
 	Languages    []TemplateLanguagesInfo `json:"languages"`                              // 模板支持的语言
 }
+
+// TemplateTrashInfo 回收站中的模板
+type TemplateTrashInfo struct {
+	Id           int64  `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	TemplateType string `json:"templateType"`
+	DeletedBy    string `json:"deletedBy"`
+	DeletedAt    string `json:"deletedAt"`
+	PurgeAt      string `json:"purgeAt"` // 超过保留期限被彻底删除的时间，未配置保留期限时为空
+}
@@ -0,0 +1,21 @@
+package model
+
+// TemplatizeVariant 字面量的一种大小写写法及替换成的模板表达式
+type TemplatizeVariant struct {
+	Text       string `json:"text"`       // 字面量的写法，如 my-service、MyService、my_service
+	Pipeline   string `json:"pipeline"`   // 由变量值得到该写法的函数管道，为空表示直接使用变量值
+	Expression string `json:"expression"` // 替换成的模板表达式，如 {{.Name | camelcase}}
+	Count      int    `json:"count"`      // 在文件名和内容中出现的次数
+}
+
+// TemplatizeFileChange 模板化对单个文件的修改
+type TemplatizeFileChange struct {
+	FileId       int64  `json:"fileId"`
+	FilePath     string `json:"filePath"`              // 修改前的路径
+	NewFilePath  string `json:"newFilePath,omitempty"` // 文件名或上级目录名被替换后的路径，路径不变时为空
+	NewFileName  string `json:"newFileName,omitempty"` // 替换后的文件名，文件名不变时为空
+	IsDirectory  int    `json:"isDirectory"`
+	NameCount    int    `json:"nameCount"`      // 文件名中替换的次数
+	ContentCount int    `json:"contentCount"`   // 内容中替换的次数
+	Diff         string `json:"diff,omitempty"` // 内容的 unified diff
+}
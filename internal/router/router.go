@@ -17,6 +17,8 @@ func (router *Router) BindController(ctx context.Context, group *ghttp.RouterGro
 		
 		// 认证相关路由 - 使用OptionalAuth中间件，在控制器方法中处理认证检查
 		group.Middleware(service.Middleware().OptionalAuth)
+		// 按用户、API Key或客户端IP限流，需要在认证之后
+		group.Middleware(service.Middleware().RateLimit)
 		group.Bind(controller.Auth)
 		
 		// 管理功能路由 (需要认证)
@@ -29,6 +31,33 @@ func (router *Router) BindController(ctx context.Context, group *ghttp.RouterGro
 				controller.ApiKey,
 			)
 		})
+
+		// 修改模板的路由 (需要认证和模板编辑权限)
+		group.Group("", func(group *ghttp.RouterGroup) {
+			group.Middleware(
+				service.Middleware().RequireAuth,
+				service.Middleware().RequirePermission("template:edit"),
+			)
+			group.Bind(
+				controller.TemplateSearch,
+				controller.TemplateReleases.Publish,
+				controller.TemplateReleases.Deprecate,
+				controller.TemplateForks.Pull,
+				controller.Templatize.Apply,
+			)
+		})
+
+		// 导入模板的路由 (需要认证和模板创建权限)
+		group.Group("", func(group *ghttp.RouterGroup) {
+			group.Middleware(
+				service.Middleware().RequireAuth,
+				service.Middleware().RequirePermission("template:create"),
+			)
+			group.Bind(
+				controller.TemplateBundle.Import,
+				controller.TemplateGit.Import,
+			)
+		})
 		
 		// 其他公开访问路由 (可选认证)
 		group.Bind(
@@ -46,6 +75,12 @@ func (router *Router) BindController(ctx context.Context, group *ghttp.RouterGro
 			controller.Tags,
 			controller.VarPreset,
 			controller.TemplateExpose,
+			controller.TemplateReleases.List,
+			controller.TemplateBundle.Export,
+			controller.TemplateGit.Source,
+			controller.TemplateTests,
+			controller.TemplateForks.Upstream,
+			controller.Templatize.Preview,
 			controller.TemplateVariablePresets,
 		)
 
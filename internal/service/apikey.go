@@ -28,6 +28,9 @@ type IApiKey interface {
 	// RegenerateApiKey 重新生成API Key Secret
 	RegenerateApiKey(ctx context.Context, req *apikeyApi.RegenerateApiKeyReq) (*apikeyApi.RegenerateApiKeyRes, error)
 	
+	// GetApiKeyScopes 获取API Key权限范围
+	GetApiKeyScopes(ctx context.Context, req *apikeyApi.GetApiKeyScopesReq) (*apikeyApi.GetApiKeyScopesRes, error)
+	
 	// GetApiKeyLogs 获取API Key使用日志
 	GetApiKeyLogs(ctx context.Context, req *apikeyApi.GetApiKeyLogsReq) (*apikeyApi.GetApiKeyLogsRes, error)
 
@@ -82,6 +82,15 @@ type IAuth interface {
 	
 	// 获取用户角色列表
 	GetUserRoles(ctx context.Context, userId int64) ([]string, error)
+	
+	// 验证令牌所属的会话是否有效
+	ValidateSession(ctx context.Context, claims *libJWT.Claims) error
+	
+	// 撤销用户的一个会话
+	RevokeSession(ctx context.Context, userId int64, sessionId string) error
+	
+	// 撤销用户的全部会话，exceptSessionId 不为空时保留该会话
+	RevokeUserSessions(ctx context.Context, userId int64, exceptSessionId string) (int64, error)
 }
 
 var localAuth IAuth
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io/fs"
 	"text/template"
 
 	"github.com/ciclebyte/template_starter/internal/model"
@@ -10,7 +11,7 @@ import (
 type (
 	IBuiltinFunctions interface {
 		GetBuiltinFunctions(ctx context.Context) (*model.BuiltinFunctionsResponse, error)
-		GetTemplateFuncMap() template.FuncMap
+		GetTemplateFuncMap(fsys fs.FS, currentPath string) template.FuncMap
 	}
 )
 
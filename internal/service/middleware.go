@@ -6,6 +6,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/gogf/gf/v2/net/ghttp"
 )
 
@@ -13,9 +15,12 @@ type IMiddleware interface {
 	MiddlewareCORS(r *ghttp.Request)
 	RequireAuth(r *ghttp.Request)
 	OptionalAuth(r *ghttp.Request)
+	RateLimit(r *ghttp.Request)
 	RequirePermission(permission string) ghttp.HandlerFunc
 	RequireRole(role string) ghttp.HandlerFunc
 	RequireTemplateOwnerOrPermission(permission string) ghttp.HandlerFunc
+	ApiKeyTemplateIds(ctx context.Context) (templateIds []int64, limited bool)
+	CheckApiKeyTemplates(ctx context.Context, templateIds []int64) error
 }
 
 var localMiddleware IMiddleware
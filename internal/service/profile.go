@@ -22,6 +22,11 @@ type (
 		// 账户安全
 		GetSecurityInfo(ctx context.Context, req *profile.GetSecurityInfoReq) (*profile.GetSecurityInfoRes, error)
 		GetLoginHistory(ctx context.Context, req *profile.GetLoginHistoryReq) (*profile.GetLoginHistoryRes, error)
+		
+		// 登录会话
+		ListSessions(ctx context.Context, req *profile.ListSessionsReq) (*profile.ListSessionsRes, error)
+		RevokeSession(ctx context.Context, req *profile.RevokeSessionReq) (*profile.RevokeSessionRes, error)
+		RevokeAllSessions(ctx context.Context, req *profile.RevokeAllSessionsReq) (*profile.RevokeAllSessionsRes, error)
 	}
 )
 
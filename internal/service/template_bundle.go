@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_bundle"
+)
+
+type ITemplateBundle interface {
+	Export(ctx context.Context, templateId int64) (err error)
+	Import(ctx context.Context, req *api.TemplateBundleImportReq) (res *api.TemplateBundleImportRes, err error)
+}
+
+var localTemplateBundle ITemplateBundle
+
+func TemplateBundle() ITemplateBundle {
+	if localTemplateBundle == nil {
+		panic("implement not found for interface ITemplateBundle, forgot register?")
+	}
+	return localTemplateBundle
+}
+
+func RegisterTemplateBundle(i ITemplateBundle) {
+	localTemplateBundle = i
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	api "github.com/ciclebyte/template_starter/api/v1/template_expose"
+	model "github.com/ciclebyte/template_starter/internal/model"
 )
 
 type ITemplateExpose interface {
@@ -11,6 +12,7 @@ type ITemplateExpose interface {
 	Set(ctx context.Context, req *api.TemplateExposeSetReq) (err error)
 	Del(ctx context.Context, req *api.TemplateExposeDelReq) (err error)
 	Versions(ctx context.Context, req *api.TemplateExposeVersionsReq) (versions []*api.TemplateExposeVersionInfo, err error)
+	GetVariableDefs(ctx context.Context, templateId int64) ([]*model.TemplateVariableDef, error)
 }
 
 var localTemplateExpose ITemplateExpose
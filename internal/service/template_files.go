@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"text/template"
 
 	api "github.com/ciclebyte/template_starter/api/v1/template_files"
 	model "github.com/ciclebyte/template_starter/internal/model"
@@ -25,6 +26,15 @@ type ITemplateFiles interface {
 	Move(ctx context.Context, req *api.TemplateFilesMoveReq) (err error)
 	SetCondition(ctx context.Context, req *api.TemplateFilesSetConditionReq) (err error)
 	GetCondition(ctx context.Context, req *api.TemplateFilesGetConditionReq) (res *api.TemplateFilesGetConditionRes, err error)
+	SetRepeat(ctx context.Context, req *api.TemplateFilesSetRepeatReq) (err error)
+	GetRepeat(ctx context.Context, req *api.TemplateFilesGetRepeatReq) (res *api.TemplateFilesGetRepeatRes, err error)
+	Revisions(ctx context.Context, fileId int64) (revisions []*model.TemplateFileRevisionInfo, err error)
+	RevisionDetail(ctx context.Context, fileId int64, revision int) (res *model.TemplateFileRevisionInfo, err error)
+	RevisionDiff(ctx context.Context, req *api.TemplateFilesRevisionDiffReq) (res *api.TemplateFilesRevisionDiffRes, err error)
+	RestoreRevision(ctx context.Context, req *api.TemplateFilesRevisionRestoreReq) (res *model.TemplateFileRevisionInfo, err error)
+	DeletedFiles(ctx context.Context, templateId int64) (files []*model.TemplateFileRevisionInfo, err error)
+	Lint(ctx context.Context, req *api.TemplateFilesLintReq) (res *api.TemplateFilesLintRes, err error)
+	TemplateFuncMap() template.FuncMap
 }
 
 var localTemplateFiles ITemplateFiles
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_forks"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+type ITemplateForks interface {
+	Track(ctx context.Context, templateId, upstreamTemplateId int64) (err error)
+	Upstream(ctx context.Context, templateId int64) (upstream *model.TemplateUpstreamInfo, err error)
+	Pull(ctx context.Context, req *api.TemplateForksPullReq) (res *api.TemplateForksPullRes, err error)
+}
+
+var localTemplateForks ITemplateForks
+
+func TemplateForks() ITemplateForks {
+	if localTemplateForks == nil {
+		panic("implement not found for interface ITemplateForks, forgot register?")
+	}
+	return localTemplateForks
+}
+
+func RegisterTemplateForks(i ITemplateForks) {
+	localTemplateForks = i
+}
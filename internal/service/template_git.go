@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_git"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+type ITemplateGit interface {
+	Import(ctx context.Context, req *api.TemplateGitImportReq) (res *api.TemplateGitImportRes, err error)
+	Source(ctx context.Context, templateId int64) (source *model.TemplateGitSourceInfo, err error)
+}
+
+var localTemplateGit ITemplateGit
+
+func TemplateGit() ITemplateGit {
+	if localTemplateGit == nil {
+		panic("implement not found for interface ITemplateGit, forgot register?")
+	}
+	return localTemplateGit
+}
+
+func RegisterTemplateGit(i ITemplateGit) {
+	localTemplateGit = i
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_releases"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+type ITemplateReleases interface {
+	List(ctx context.Context, req *api.TemplateReleasesListReq) (releases []*model.TemplateReleaseInfo, err error)
+	Publish(ctx context.Context, req *api.TemplateReleasesPublishReq) (release *model.TemplateReleaseInfo, err error)
+	Deprecate(ctx context.Context, req *api.TemplateReleasesDeprecateReq) (err error)
+	LoadSnapshot(ctx context.Context, templateId int64, version string) (release *model.TemplateReleaseInfo, snapshot *model.TemplateReleaseSnapshot, err error)
+}
+
+var localTemplateReleases ITemplateReleases
+
+func TemplateReleases() ITemplateReleases {
+	if localTemplateReleases == nil {
+		panic("implement not found for interface ITemplateReleases, forgot register?")
+	}
+	return localTemplateReleases
+}
+
+func RegisterTemplateReleases(i ITemplateReleases) {
+	localTemplateReleases = i
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_search"
+)
+
+type ITemplateSearch interface {
+	Search(ctx context.Context, req *api.TemplateSearchReq) (res *api.TemplateSearchRes, err error)
+	Replace(ctx context.Context, req *api.TemplateReplaceReq) (res *api.TemplateReplaceRes, err error)
+}
+
+var localTemplateSearch ITemplateSearch
+
+func TemplateSearch() ITemplateSearch {
+	if localTemplateSearch == nil {
+		panic("implement not found for interface ITemplateSearch, forgot register?")
+	}
+	return localTemplateSearch
+}
+
+func RegisterTemplateSearch(i ITemplateSearch) {
+	localTemplateSearch = i
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/template_tests"
+	model "github.com/ciclebyte/template_starter/internal/model"
+)
+
+type ITemplateTests interface {
+	List(ctx context.Context, req *api.TemplateTestsListReq) (cases []*model.TemplateTestCaseInfo, err error)
+	Detail(ctx context.Context, req *api.TemplateTestsDetailReq) (testCase *model.TemplateTestCaseInfo, err error)
+	Add(ctx context.Context, req *api.TemplateTestsAddReq) (testCase *model.TemplateTestCaseInfo, err error)
+	Edit(ctx context.Context, req *api.TemplateTestsEditReq) (testCase *model.TemplateTestCaseInfo, err error)
+	Delete(ctx context.Context, req *api.TemplateTestsDeleteReq) (err error)
+	Run(ctx context.Context, req *api.TemplateTestsRunReq) (res *api.TemplateTestsRunRes, err error)
+}
+
+var localTemplateTests ITemplateTests
+
+func TemplateTests() ITemplateTests {
+	if localTemplateTests == nil {
+		panic("implement not found for interface ITemplateTests, forgot register?")
+	}
+	return localTemplateTests
+}
+
+func RegisterTemplateTests(i ITemplateTests) {
+	localTemplateTests = i
+}
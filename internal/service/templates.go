@@ -13,8 +13,13 @@ type ITemplates interface {
 	Edit(ctx context.Context, req *api.TemplatesEditReq) (err error)
 	Delete(ctx context.Context, id int64) (err error)
 	BatchDelete(ctx context.Context, ids []int64) (err error)
+	Trash(ctx context.Context, req *api.TemplatesTrashReq) (total interface{}, res []*model.TemplateTrashInfo, retentionDays int, err error)
+	Restore(ctx context.Context, ids []int64) (err error)
+	Purge(ctx context.Context, ids []int64) (err error)
+	PurgeExpired(ctx context.Context) (purged int, err error)
 	GetById(ctx context.Context, id int64) (res *model.TemplatesInfo, err error)
-	GetVariables(ctx context.Context, templateId int64) (res *api.TemplatesVariablesRes, err error)
+	GetDelimiters(ctx context.Context, templateId int64) (res *model.TemplateDelimiters, err error)
+	GetVariables(ctx context.Context, templateId int64, version string) (res *api.TemplatesVariablesRes, err error)
 	AnalyzeVariables(ctx context.Context, templateId int64) (res *api.TemplatesAnalyzeVariablesRes, err error)
 	Fork(ctx context.Context, req *api.TemplatesForkReq) (res *api.TemplatesForkRes, err error)
 }
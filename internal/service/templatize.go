@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	api "github.com/ciclebyte/template_starter/api/v1/templatize"
+)
+
+type ITemplatize interface {
+	Preview(ctx context.Context, req *api.TemplatizePreviewReq) (res *api.TemplatizeResult, err error)
+	Apply(ctx context.Context, req *api.TemplatizeApplyReq) (res *api.TemplatizeResult, err error)
+}
+
+var localTemplatize ITemplatize
+
+func Templatize() ITemplatize {
+	if localTemplatize == nil {
+		panic("implement not found for interface ITemplatize, forgot register?")
+	}
+	return localTemplatize
+}
+
+func RegisterTemplatize(i ITemplatize) {
+	localTemplatize = i
+}
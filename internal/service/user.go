@@ -20,6 +20,7 @@ type (
 		GetUser(ctx context.Context, req *user.GetUserReq) (*user.GetUserRes, error)
 		ResetPassword(ctx context.Context, req *user.ResetPasswordReq) (*user.ResetPasswordRes, error)
 		UpdateUserStatus(ctx context.Context, req *user.UpdateUserStatusReq) (*user.UpdateUserStatusRes, error)
+		RevokeUserSessions(ctx context.Context, req *user.RevokeUserSessionsReq) (*user.RevokeUserSessionsRes, error)
 		AssignUserRoles(ctx context.Context, req *user.AssignUserRolesReq) (*user.AssignUserRolesRes, error)
 	}
 )
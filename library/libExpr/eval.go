@@ -0,0 +1,380 @@
+package libExpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// Program 编译后的条件表达式
+//
+// 支持的语法：
+//   - 逻辑运算：&& || !（也可写作 and or not），支持括号分组
+//   - 比较运算：== != < <= > >=
+//   - 成员判断：x in [..]、x not in [..]，右侧也可以是数组变量、对象（判断键）或字符串（判断子串）
+//   - 字面量：字符串（单引号或双引号）、数字、true、false、nil
+//   - 变量引用：name、.name、a.b.c、list[0]、obj["key"]
+type Program struct {
+	source string
+	root   node
+	vars   []string
+}
+
+// Compile 解析表达式，语法错误时返回带列号的错误
+func Compile(source string) (*Program, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("表达式不能为空")
+	}
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, vars: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("第%d列: 意外的%s", tok.pos, describe(tok))
+	}
+	vars := make([]string, 0, len(p.vars))
+	for name := range p.vars {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	return &Program{source: source, root: root, vars: vars}, nil
+}
+
+// Source 返回原始表达式
+func (p *Program) Source() string {
+	return p.source
+}
+
+// Variables 返回表达式引用的顶层变量名（已排序去重）
+func (p *Program) Variables() []string {
+	return p.vars
+}
+
+// Eval 计算表达式的值
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	return p.root.eval(vars)
+}
+
+// EvalBool 计算表达式并按真值规则转换为布尔值
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	value, err := p.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return Truthy(value), nil
+}
+
+// Truthy 真值规则：nil、false、0、空字符串、"false"、"0" 以及空数组/对象为假，其余为真
+func Truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false" && v != "0"
+	}
+	if f, ok := toNumber(value); ok {
+		return f != 0
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Ptr, reflect.Interface:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+func (n *literalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	items := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+// eval 变量不存在或中间路径为空时返回 nil，而不是报错
+func (n *pathNode) eval(vars map[string]interface{}) (interface{}, error) {
+	current, ok := vars[n.root]
+	if !ok {
+		return nil, nil
+	}
+	for _, step := range n.steps {
+		if current == nil {
+			return nil, nil
+		}
+		if step.index == nil {
+			current = lookupKey(current, step.field)
+			continue
+		}
+		index, err := step.index.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		if i, isNum := toNumber(index); isNum && isList(current) {
+			current = lookupIndex(current, int(i))
+		} else {
+			current = lookupKey(current, gconv.String(index))
+		}
+	}
+	return current, nil
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return !Truthy(value), nil
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	// 逻辑运算短路求值
+	if n.op == tokAnd || n.op == tokOr {
+		left, err := n.left.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == tokAnd && !Truthy(left) {
+			return false, nil
+		}
+		if n.op == tokOr && Truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		return Truthy(right), nil
+	}
+
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case tokEq:
+		return equal(left, right), nil
+	case tokNe:
+		return !equal(left, right), nil
+	case tokIn:
+		found, err := contains(right, left)
+		if err != nil {
+			return nil, fmt.Errorf("第%d列: %v", n.pos, err)
+		}
+		return found != n.negate, nil
+	}
+
+	cmp, err := compare(left, right)
+	if err != nil {
+		return nil, fmt.Errorf("第%d列: %v", n.pos, err)
+	}
+	switch n.op {
+	case tokLt:
+		return cmp < 0, nil
+	case tokLe:
+		return cmp <= 0, nil
+	case tokGt:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// toNumber 将数值类型转换为 float64，字符串不做转换
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return gconv.Float64(v), true
+	case interface{ Float64() (float64, error) }: // json.Number
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// numericPair 当一侧为数字、另一侧为数字或可解析为数字的字符串时，返回两个数值
+func numericPair(a, b interface{}) (float64, float64, bool) {
+	fa, okA := toNumber(a)
+	fb, okB := toNumber(b)
+	if okA && okB {
+		return fa, fb, true
+	}
+	if okA {
+		if s, isStr := b.(string); isStr {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return fa, f, true
+			}
+		}
+	}
+	if okB {
+		if s, isStr := a.(string); isStr {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return f, fb, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// equal 判断两个值是否相等，数字与数字字符串、布尔与布尔字符串视为可比
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if fa, fb, ok := numericPair(a, b); ok {
+		return fa == fb
+	}
+	if ba, isBool := a.(bool); isBool {
+		return ba == gconv.Bool(b)
+	}
+	if bb, isBool := b.(bool); isBool {
+		return bb == gconv.Bool(a)
+	}
+	if isList(a) || isList(b) || isMap(a) || isMap(b) {
+		return reflect.DeepEqual(a, b)
+	}
+	return gconv.String(a) == gconv.String(b)
+}
+
+// compare 比较大小，仅支持数字之间和字符串之间
+func compare(a, b interface{}) (int, error) {
+	if fa, fb, ok := numericPair(a, b); ok {
+		switch {
+		case fa < fb:
+			return -1, nil
+		case fa > fb:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if okA && okB {
+		return strings.Compare(sa, sb), nil
+	}
+	return 0, fmt.Errorf("无法比较 %s 和 %s 的大小", typeName(a), typeName(b))
+}
+
+// contains 实现 in 运算：数组判断元素、对象判断键、字符串判断子串
+func contains(container, item interface{}) (bool, error) {
+	if container == nil {
+		return false, nil
+	}
+	if s, ok := container.(string); ok {
+		return strings.Contains(s, gconv.String(item)), nil
+	}
+	rv := reflect.ValueOf(container)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if equal(rv.Index(i).Interface(), item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		key := gconv.String(item)
+		for _, k := range rv.MapKeys() {
+			if gconv.String(k.Interface()) == key {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("in 右侧必须是数组、对象或字符串，实际为 %s", typeName(container))
+}
+
+// lookupKey 按字段名读取对象属性，对象不存在该字段时返回 nil
+func lookupKey(value interface{}, key string) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m[key]
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, k := range rv.MapKeys() {
+			if gconv.String(k.Interface()) == key {
+				return rv.MapIndex(k).Interface()
+			}
+		}
+	case reflect.Struct:
+		if field := rv.FieldByName(key); field.IsValid() && field.CanInterface() {
+			return field.Interface()
+		}
+	}
+	return nil
+}
+
+// lookupIndex 按下标读取数组元素，越界时返回 nil
+func lookupIndex(value interface{}, index int) interface{} {
+	rv := reflect.ValueOf(value)
+	if index < 0 || index >= rv.Len() {
+		return nil
+	}
+	return rv.Index(index).Interface()
+}
+
+func isList(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+func isMap(value interface{}) bool {
+	return value != nil && reflect.TypeOf(value).Kind() == reflect.Map
+}
+
+// typeName 返回用于错误提示的类型名
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return "字符串"
+	case bool:
+		return "布尔值"
+	}
+	if _, ok := toNumber(value); ok {
+		return "数字"
+	}
+	if isList(value) {
+		return "数组"
+	}
+	if isMap(value) {
+		return "对象"
+	}
+	return reflect.TypeOf(value).String()
+}
@@ -0,0 +1,142 @@
+package libExpr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvalBool(t *testing.T) {
+	vars := map[string]interface{}{
+		"enabled": true,
+		"db":      "mysql",
+		"port":    8080,
+		"version": "1.10",
+		"count":   "3",
+		"modules": []interface{}{"auth", "user"},
+		"options": map[string]interface{}{
+			"cache": map[string]interface{}{"type": "redis"},
+			"ports": []interface{}{80, 443},
+		},
+	}
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		// ! 优先于 &&，&& 优先于 ||
+		{`!enabled || true`, true},
+		{`!(enabled || true)`, false},
+		{`true || false && false`, true},
+		{`(true || false) && false`, false},
+		{`!false && false`, false},
+		{`false && false || true`, true},
+		{`not enabled or db == "mysql" and port == 8080`, true},
+		{`not (enabled or false)`, false},
+		{`true or false and false`, true},
+		{`not false and not enabled`, false},
+		{`enabled and !false && db != "pgsql"`, true},
+
+		// in 和 not in
+		{`db in ["mysql", "pgsql"]`, true},
+		{`db in ["sqlite"]`, false},
+		{`db not in ["sqlite"]`, true},
+		{`db not in ["mysql"]`, false},
+		{`"auth" in modules`, true},
+		{`"admin" not in modules`, true},
+		{`"cache" in options`, true},
+		{`"sql" in db`, true},
+		{`443 in options.ports`, true},
+		{`"443" in options.ports`, true},
+		{`"auth" in missing`, false},
+		{`!(db in ["mysql"])`, false},
+
+		// 数字与字符串比较
+		{`port == "8080"`, true},
+		{`"8080" == port`, true},
+		{`port > "1024"`, true},
+		{`count < 10`, true},
+		{`count == 3`, true},
+		{`version > 1.9`, false},
+		{`version > "1.9"`, false},
+		{`db == 0`, false},
+
+		// 不存在的路径
+		{`missing`, false},
+		{`missing == nil`, true},
+		{`missing.deep.path == nil`, true},
+		{`options.cache.type == "redis"`, true},
+		{`options.cache.size == nil`, true},
+		{`options.ports[5] == nil`, true},
+		{`modules[0] == "auth"`, true},
+		{`options["cache"].type == "redis"`, true},
+		{`!missing && !options.none`, true},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			program, err := Compile(c.expr)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			got, err := program.EvalBool(vars)
+			if err != nil {
+				t.Fatalf("eval: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	vars := map[string]interface{}{"db": "mysql", "port": 8080}
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{`db > 1`, "无法比较"},
+		{`port < true`, "无法比较"},
+		{`missing < 1`, "无法比较"},
+		{`db in port`, "in 右侧必须是数组、对象或字符串"},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			program, err := Compile(c.expr)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			_, err = program.Eval(vars)
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("got error %v, want %q", err, c.err)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{`(a && b`, "第8列"},
+		{`a && b)`, "第7列"},
+		{`((a)`, "第5列"},
+		{`a &&`, "第5列"},
+		{`a ||`, "第5列"},
+		{`!`, "第2列"},
+		{`a ==`, "第5列"},
+		{`a not b`, `"not" 后应为 "in"`},
+		{`a & b`, "无效的运算符"},
+		{`a = 1`, "比较请使用"},
+		{`"abc`, "缺少结束引号"},
+		{`a in [1, 2`, "第11列"},
+		{``, "表达式不能为空"},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			_, err := Compile(c.expr)
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("got error %v, want %q", err, c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package libExpr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokAnd      // && and
+	tokOr       // || or
+	tokNot      // ! not
+	tokIn       // in
+	tokEq       // ==
+	tokNe       // !=
+	tokLt       // <
+	tokLe       // <=
+	tokGt       // >
+	tokGe       // >=
+	tokLParen   // (
+	tokRParen   // )
+	tokLBracket // [
+	tokRBracket // ]
+	tokComma    // ,
+	tokDot      // .
+)
+
+// token 词法单元
+type token struct {
+	kind tokenKind
+	text string // 原始文本（字符串字面量为解码后的值）
+	pos  int    // 在表达式中的起始位置（从1开始）
+}
+
+// lex 将表达式拆分为词法单元
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", pos})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokLBracket, "[", pos})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokRBracket, "]", pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ",", pos})
+			i++
+		case r == '.' && !(i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			tokens = append(tokens, token{tokDot, ".", pos})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("第%d列: 无效的运算符 %q，是否应为 %q", pos, string(r), string(r)+string(r))
+			}
+			kind := tokAnd
+			if r == '|' {
+				kind = tokOr
+			}
+			tokens = append(tokens, token{kind, string(r) + string(r), pos})
+			i += 2
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			hasEq := i+1 < len(runes) && runes[i+1] == '='
+			switch {
+			case r == '=' && hasEq:
+				tokens = append(tokens, token{tokEq, "==", pos})
+			case r == '=':
+				return nil, fmt.Errorf("第%d列: 无效的运算符 \"=\"，比较请使用 \"==\"", pos)
+			case r == '!' && hasEq:
+				tokens = append(tokens, token{tokNe, "!=", pos})
+			case r == '!':
+				tokens = append(tokens, token{tokNot, "!", pos})
+			case r == '<' && hasEq:
+				tokens = append(tokens, token{tokLe, "<=", pos})
+			case r == '<':
+				tokens = append(tokens, token{tokLt, "<", pos})
+			case r == '>' && hasEq:
+				tokens = append(tokens, token{tokGe, ">=", pos})
+			default:
+				tokens = append(tokens, token{tokGt, ">", pos})
+			}
+			if hasEq {
+				i += 2
+			} else {
+				i++
+			}
+		case r == '"' || r == '\'':
+			str, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, str, pos})
+			i = next
+		case unicode.IsDigit(r) || r == '.' || (r == '-' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), pos})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, token{tokAnd, word, pos})
+			case "or":
+				tokens = append(tokens, token{tokOr, word, pos})
+			case "not":
+				tokens = append(tokens, token{tokNot, word, pos})
+			case "in":
+				tokens = append(tokens, token{tokIn, word, pos})
+			default:
+				tokens = append(tokens, token{tokIdent, word, pos})
+			}
+		default:
+			return nil, fmt.Errorf("第%d列: 无法识别的字符 %q", pos, string(r))
+		}
+	}
+	tokens = append(tokens, token{tokEOF, "", len(runes) + 1})
+	return tokens, nil
+}
+
+// lexString 读取单引号或双引号包裹的字符串字面量，支持反斜杠转义
+func lexString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	i := start + 1
+	for i < len(runes) {
+		r := runes[i]
+		if r == '\\' && i+1 < len(runes) {
+			switch runes[i+1] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			default:
+				sb.WriteRune(runes[i+1])
+			}
+			i += 2
+			continue
+		}
+		if r == quote {
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteRune(r)
+		i++
+	}
+	return "", 0, fmt.Errorf("第%d列: 字符串缺少结束引号", start+1)
+}
@@ -0,0 +1,257 @@
+package libExpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// node 表达式语法树节点
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+// literalNode 字面量：字符串、数字、布尔、nil
+type literalNode struct {
+	value interface{}
+}
+
+// listNode 列表字面量：["a", "b"]
+type listNode struct {
+	items []node
+}
+
+// pathStep 路径中的一段，字段名或索引表达式二选一
+type pathStep struct {
+	field string
+	index node
+}
+
+// pathNode 变量引用，支持嵌套字段 a.b.c 和索引 a[0] / a["key"]
+type pathNode struct {
+	root  string
+	steps []pathStep
+	pos   int
+}
+
+// unaryNode 一元运算：!x
+type unaryNode struct {
+	op      tokenKind
+	operand node
+}
+
+// binaryNode 二元运算：逻辑、比较和 in
+type binaryNode struct {
+	op     tokenKind
+	negate bool // 用于 not in
+	left   node
+	right  node
+	pos    int
+}
+
+// parser 递归下降解析器
+type parser struct {
+	tokens []token
+	pos    int
+	vars   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, text string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("第%d列: 期望 %q，实际为 %s", tok.pos, text, describe(tok))
+	}
+	return tok, nil
+}
+
+// describe 返回词法单元的可读描述，用于错误提示
+func describe(tok token) string {
+	if tok.kind == tokEOF {
+		return "表达式结尾"
+	}
+	if tok.kind == tokString {
+		return strconv.Quote(tok.text)
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+// parseOr or := and ( ("||" | "or") and )*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		tok := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tokOr, left: left, right: right, pos: tok.pos}
+	}
+	return left, nil
+}
+
+// parseAnd and := unary ( ("&&" | "and") unary )*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		tok := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tokAnd, left: left, right: right, pos: tok.pos}
+	}
+	return left, nil
+}
+
+// parseUnary unary := ("!" | "not") unary | comparison
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: tokNot, operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison comparison := primary [ op primary ]，op 为比较运算符、in 或 not in
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	switch tok.kind {
+	case tokEq, tokNe, tokLt, tokLe, tokGt, tokGe, tokIn:
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: tok.kind, left: left, right: right, pos: tok.pos}, nil
+	case tokNot:
+		if p.tokens[p.pos+1].kind != tokIn {
+			return nil, fmt.Errorf("第%d列: \"not\" 后应为 \"in\"", tok.pos)
+		}
+		p.next()
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: tokIn, negate: true, left: left, right: right, pos: tok.pos}, nil
+	}
+	return left, nil
+}
+
+// parsePrimary primary := literal | path | list | "(" or ")"
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokLBracket:
+		list := &listNode{}
+		if p.peek().kind == tokRBracket {
+			p.next()
+			return list, nil
+		}
+		for {
+			item, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+			if p.peek().kind == tokComma {
+				p.next()
+				continue
+			}
+			if _, err := p.expect(tokRBracket, "]"); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+	case tokString:
+		return &literalNode{value: tok.text}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("第%d列: 无效的数字 %q", tok.pos, tok.text)
+		}
+		return &literalNode{value: f}, nil
+	case tokDot:
+		// 兼容 Go 模板写法 .Name
+		ident, err := p.expect(tokIdent, "变量名")
+		if err != nil {
+			return nil, err
+		}
+		return p.parsePath(ident)
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "nil", "null":
+			return &literalNode{value: nil}, nil
+		}
+		return p.parsePath(tok)
+	}
+	return nil, fmt.Errorf("第%d列: 意外的%s", tok.pos, describe(tok))
+}
+
+// parsePath 解析变量根名之后的 .field 和 [index] 访问
+func (p *parser) parsePath(root token) (node, error) {
+	path := &pathNode{root: root.text, pos: root.pos}
+	p.vars[root.text] = true
+	for {
+		switch p.peek().kind {
+		case tokDot:
+			p.next()
+			field, err := p.expect(tokIdent, "字段名")
+			if err != nil {
+				return nil, err
+			}
+			path.steps = append(path.steps, pathStep{field: field.text})
+		case tokLBracket:
+			p.next()
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokRBracket, "]"); err != nil {
+				return nil, err
+			}
+			path.steps = append(path.steps, pathStep{index: index})
+		default:
+			return path, nil
+		}
+	}
+}
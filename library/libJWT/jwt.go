@@ -32,6 +32,8 @@ type TokenInfo struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 	TokenType    string `json:"token_type"`
+
+	RefreshTokenID string `json:"-"` // 刷新令牌的ID（jti），用于刷新令牌轮换，不返回给客户端
 }
 
 // Claims JWT载荷
@@ -42,6 +44,7 @@ type Claims struct {
 	Roles       []string `json:"roles"`
 	Permissions []string `json:"permissions"`
 	TokenType   string   `json:"token_type"` // access 或 refresh
+	SessionID   string   `json:"sid"`        // 所属会话ID，会话撤销后令牌失效
 	jwt.RegisteredClaims
 }
 
@@ -74,8 +77,8 @@ func GetManager() *JWTManager {
 	return jwtManager
 }
 
-// GenerateTokens 生成访问令牌和刷新令牌
-func (j *JWTManager) GenerateTokens(userID int64, username, email string, roles, permissions []string) (*TokenInfo, error) {
+// GenerateTokens 生成访问令牌和刷新令牌，两个令牌都绑定到同一个会话
+func (j *JWTManager) GenerateTokens(sessionID string, userID int64, username, email string, roles, permissions []string) (*TokenInfo, error) {
 	now := time.Now()
 	
 	// 生成访问令牌
@@ -86,6 +89,7 @@ func (j *JWTManager) GenerateTokens(userID int64, username, email string, roles,
 		Roles:       roles,
 		Permissions: permissions,
 		TokenType:   "access",
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.AccessExpire)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -103,18 +107,20 @@ func (j *JWTManager) GenerateTokens(userID int64, username, email string, roles,
 	}
 
 	// 生成刷新令牌
+	refreshTokenID := guid.S()
 	refreshClaims := &Claims{
 		UserID:    userID,
 		Username:  username,
 		Email:     email,
 		TokenType: "refresh",
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.RefreshExpire)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    j.Issuer,
 			Subject:   username,
-			ID:        guid.S(),
+			ID:        refreshTokenID,
 		},
 	}
 
@@ -129,6 +135,8 @@ func (j *JWTManager) GenerateTokens(userID int64, username, email string, roles,
 		RefreshToken: refreshTokenString,
 		ExpiresIn:    int64(j.AccessExpire.Seconds()),
 		TokenType:    "Bearer",
+
+		RefreshTokenID: refreshTokenID,
 	}, nil
 }
 
@@ -160,7 +168,7 @@ func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 	return nil, ErrTokenInvalid
 }
 
-// RefreshAccessToken 刷新访问令牌
+// RefreshAccessToken 刷新访问令牌，生成新的令牌对，调用方需要使旧的刷新令牌失效
 func (j *JWTManager) RefreshAccessToken(refreshTokenString string, roles, permissions []string) (*TokenInfo, error) {
 	// 验证刷新令牌
 	claims, err := j.ValidateToken(refreshTokenString)
@@ -173,8 +181,8 @@ func (j *JWTManager) RefreshAccessToken(refreshTokenString string, roles, permis
 		return nil, ErrTokenInvalid
 	}
 
-	// 生成新的令牌对
-	return j.GenerateTokens(claims.UserID, claims.Username, claims.Email, roles, permissions)
+	// 生成新的令牌对，沿用原来的会话
+	return j.GenerateTokens(claims.SessionID, claims.UserID, claims.Username, claims.Email, roles, permissions)
 }
 
 // ExtractTokenFromHeader 从Authorization头中提取token
@@ -59,4 +59,29 @@ export function getLoginHistory(params) {
         method: 'get',
         params
     })
-}
\ No newline at end of file
+}
+
+// 获取登录会话列表
+export function getSessions() {
+    return request({
+        url: '/api/v1/profile/sessions',
+        method: 'get'
+    })
+}
+
+// 撤销登录会话
+export function revokeSession(id) {
+    return request({
+        url: `/api/v1/profile/sessions/${id}`,
+        method: 'delete'
+    })
+}
+
+// 撤销全部登录会话
+export function revokeAllSessions(keepCurrent = true) {
+    return request({
+        url: '/api/v1/profile/sessions',
+        method: 'delete',
+        params: { keepCurrent }
+    })
+}
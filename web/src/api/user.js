@@ -68,4 +68,12 @@ export function assignUserRoles(id, data) {
         method: 'put',
         data: { id, ...data }
     })
+}
+
+// 撤销用户的全部登录会话
+export function revokeUserSessions(id) {
+    return request({
+        url: `/api/v1/users/${id}/sessions`,
+        method: 'delete'
+    })
 }
\ No newline at end of file
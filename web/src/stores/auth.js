@@ -14,6 +14,9 @@ export const useAuthStore = defineStore('auth', () => {
     let fetchingUserInfo = false
     let fetchingPromise = null
     
+    // 刷新锁，刷新令牌每次使用后即失效，并发刷新会被服务端视为令牌重用
+    let refreshingPromise = null
+    
     // 计算属性
     const isLoggedIn = computed(() => !!user.value && !!accessToken.value)
     const isAdmin = computed(() => roles.value.includes('super_admin') || roles.value.includes('system_admin'))
@@ -131,20 +134,31 @@ export const useAuthStore = defineStore('auth', () => {
     
     // 刷新令牌
     const doRefreshToken = async () => {
-        try {
-            const response = await refreshToken({ refresh_token: refreshTokenValue.value })
-            if (response.data.code === 0) {
-                setTokens(response.data.data)
-                return true
-            } else {
+        // 已有刷新请求在进行中时共用其结果
+        if (refreshingPromise) {
+            return await refreshingPromise
+        }
+        
+        refreshingPromise = (async () => {
+            try {
+                const response = await refreshToken({ refresh_token: refreshTokenValue.value })
+                if (response.data.code === 0) {
+                    setTokens(response.data.data)
+                    return true
+                } else {
+                    clearAuth()
+                    return false
+                }
+            } catch (error) {
+                console.error('刷新令牌失败:', error)
                 clearAuth()
                 return false
+            } finally {
+                refreshingPromise = null
             }
-        } catch (error) {
-            console.error('刷新令牌失败:', error)
-            clearAuth()
-            return false
-        }
+        })()
+        
+        return await refreshingPromise
     }
     
     // 权限检查